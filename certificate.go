@@ -0,0 +1,138 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"errors"
+
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/eric135/go-ndn2/util"
+)
+
+// certificateKeyComponent is the value of the generic name component that precedes the key ID in a certificate name.
+var certificateKeyComponent = []byte("KEY")
+
+// Certificate is a specialized Data packet containing a public key, named /<identity>/KEY/<key-id>/<issuer>/<version>.
+type Certificate struct {
+	Data
+}
+
+// NewCertificate creates a Certificate from the specified Data, validating the structure of its name.
+func NewCertificate(data *Data) (*Certificate, error) {
+	if data == nil {
+		return nil, util.ErrNonExistent
+	}
+	if err := validateCertificateName(&data.name); err != nil {
+		return nil, err
+	}
+
+	c := new(Certificate)
+	c.Data = *data.DeepCopy()
+	return c, nil
+}
+
+// DecodeCertificate decodes a Certificate from the wire.
+func DecodeCertificate(wire *tlv.Block) (*Certificate, error) {
+	data, err := DecodeData(wire)
+	if err != nil {
+		return nil, err
+	}
+	return NewCertificate(data)
+}
+
+// validateCertificateName checks that the specified name has the structure /<identity>/KEY/<key-id>/<issuer>/<version>.
+func validateCertificateName(name *Name) error {
+	if name.Size() < 4 {
+		return errors.New("Certificate name is too short")
+	}
+	if !isKeyComponent(name.At(name.Size() - 4)) {
+		return errors.New("Certificate name does not contain KEY component")
+	}
+	return nil
+}
+
+// isKeyComponent returns whether the name component is the generic KEY component that separates the identity from the key ID in key and certificate names.
+func isKeyComponent(component NameComponent) bool {
+	return component.Type() == tlv.GenericNameComponent && bytes.Equal(component.Value(), certificateKeyComponent)
+}
+
+// ParseCertificateName splits a certificate name of the form /<identity>/KEY/<key-id>/<issuer-id>/<version> into its parts, returning copies of the identity, key ID, and issuer ID. The KEY component is located by its position from the end of the name rather than by searching, since the identity may itself contain a KEY component. The version must be a VersionNameComponent.
+func ParseCertificateName(n *Name) (identity *Name, keyID NameComponent, issuerID NameComponent, version uint64, err error) {
+	if n == nil {
+		return nil, nil, nil, 0, util.ErrNonExistent
+	}
+	if err := validateCertificateName(n); err != nil {
+		return nil, nil, nil, 0, err
+	}
+	version, ok := n.Version(n.Size() - 1)
+	if !ok {
+		return nil, nil, nil, 0, errors.New("Certificate name does not end with a version")
+	}
+	return n.Prefix(n.Size() - 4), n.At(n.Size() - 3).DeepCopy(), n.At(n.Size() - 2).DeepCopy(), version, nil
+}
+
+// MakeCertificateName constructs a certificate name of the form /<identity>/KEY/<key-id>/<issuer-id>/<version>, the inverse of ParseCertificateName.
+func MakeCertificateName(identity *Name, keyID NameComponent, issuerID NameComponent, version uint64) *Name {
+	var n *Name
+	if identity == nil {
+		n = NewName()
+	} else {
+		n = identity.DeepCopy()
+	}
+	return n.AppendGeneric(certificateKeyComponent).Append(keyID).Append(issuerID).AppendVersion(version)
+}
+
+// DeepCopy returns a deep copy of the Certificate.
+func (c *Certificate) DeepCopy() *Certificate {
+	copyC := new(Certificate)
+	copyC.Data = *c.Data.DeepCopy()
+	return copyC
+}
+
+// Identity returns the name of the identity the certificate belongs to.
+func (c *Certificate) Identity() *Name {
+	return c.name.Prefix(c.name.Size() - 4)
+}
+
+// KeyName returns the name of the key contained in the certificate (/<identity>/KEY/<key-id>).
+func (c *Certificate) KeyName() *Name {
+	return c.name.Prefix(c.name.Size() - 2)
+}
+
+// Issuer returns a copy of the issuer ID component of the certificate name.
+func (c *Certificate) Issuer() NameComponent {
+	return c.name.At(c.name.Size() - 2).DeepCopy()
+}
+
+// PublicKey parses the DER-encoded SubjectPublicKeyInfo in the content of the certificate.
+func (c *Certificate) PublicKey() (crypto.PublicKey, error) {
+	if len(c.content) == 0 {
+		return nil, errors.New("Certificate does not contain a public key")
+	}
+	return x509.ParsePKIXPublicKey(c.content)
+}
+
+// ValidityPeriod returns a copy of the ValidityPeriod in the SignatureInfo of the certificate, or nil if none is set.
+func (c *Certificate) ValidityPeriod() *ValidityPeriod {
+	if c.signatureInfo == nil {
+		return nil
+	}
+	return c.signatureInfo.ValidityPeriod()
+}
+
+// SetName sets the name of the Certificate, which must have the structure of a certificate name.
+func (c *Certificate) SetName(name *Name) error {
+	if err := validateCertificateName(name); err != nil {
+		return err
+	}
+	c.Data.SetName(name)
+	return nil
+}
@@ -0,0 +1,101 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	ndn "github.com/eric135/go-ndn2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCertificate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+
+	name, err := ndn.NameFromString("/go/ndn/KEY/abcd/self/v=1")
+	assert.NoError(t, err)
+	d := ndn.NewData(name, publicKey)
+	signatureInfo := ndn.NewSignatureInfo(ndn.SignatureSha256WithEcdsa)
+	notBefore := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+	signatureInfo.SetValidityPeriod(ndn.NewValidityPeriod(notBefore, notAfter))
+	d.SetSignatureInfo(signatureInfo)
+	d.SetSignatureValue([]byte{0xaa})
+
+	c, err := ndn.NewCertificate(d)
+	assert.NoError(t, err)
+	assert.Equal(t, "/go/ndn", c.Identity().String())
+	assert.Equal(t, "/go/ndn/KEY/abcd", c.KeyName().String())
+	assert.Equal(t, "self", c.Issuer().String())
+	parsedKey, err := c.PublicKey()
+	assert.NoError(t, err)
+	assert.Equal(t, &key.PublicKey, parsedKey)
+
+	// Round trip
+	encoded, err := c.Encode()
+	assert.NoError(t, err)
+	decoded, err := ndn.DecodeCertificate(encoded)
+	assert.NoError(t, err)
+	assert.True(t, c.Name().Equals(decoded.Name()))
+	assert.Equal(t, publicKey, decoded.Content())
+	assert.Equal(t, notBefore, decoded.ValidityPeriod().NotBefore())
+	assert.Equal(t, notAfter, decoded.ValidityPeriod().NotAfter())
+
+	// Invalid names
+	name, _ = ndn.NameFromString("/go/ndn/abcd/self/v=1")
+	_, err = ndn.NewCertificate(ndn.NewData(name, publicKey))
+	assert.Error(t, err)
+	name, _ = ndn.NameFromString("/KEY/abcd/self")
+	_, err = ndn.NewCertificate(ndn.NewData(name, publicKey))
+	assert.Error(t, err)
+	name, _ = ndn.NameFromString("/go/ndn/abcd/self/v=1")
+	assert.Error(t, c.SetName(name))
+	assert.Equal(t, "/go/ndn/KEY/abcd/self/v=1", c.Name().String())
+
+	// Invalid public key
+	name, _ = ndn.NameFromString("/go/ndn/KEY/abcd/self/v=1")
+	c, err = ndn.NewCertificate(ndn.NewData(name, []byte{0x01, 0x02}))
+	assert.NoError(t, err)
+	_, err = c.PublicKey()
+	assert.Error(t, err)
+	assert.Nil(t, c.ValidityPeriod())
+}
+
+func TestParseCertificateName(t *testing.T) {
+	name, _ := ndn.NameFromString("/go/KEY/ndn/KEY/abcd/self/v=7")
+	identity, keyID, issuerID, version, err := ndn.ParseCertificateName(name)
+	assert.NoError(t, err)
+	assert.Equal(t, "/go/KEY/ndn", identity.String())
+	assert.Equal(t, "abcd", keyID.String())
+	assert.Equal(t, "self", issuerID.String())
+	assert.Equal(t, uint64(7), version)
+
+	// Inverse
+	made := ndn.MakeCertificateName(identity, keyID, issuerID, version)
+	assert.True(t, made.Equals(name))
+	_, err = ndn.NewCertificate(ndn.NewData(made, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "/KEY/abcd/self/v=1", ndn.MakeCertificateName(nil, keyID, issuerID, 1).String())
+
+	// KEY must be a GenericNameComponent in the fourth-to-last position
+	for _, invalid := range []string{"/go/KEY/abcd/self", "/go/32=KEY/abcd/self/v=7", "/go/KEY/abcd/self/v=7/extra", "/go/KEY/abcd/self/7"} {
+		name, _ = ndn.NameFromString(invalid)
+		_, _, _, _, err = ndn.ParseCertificateName(name)
+		assert.Error(t, err, invalid)
+	}
+	_, _, _, _, err = ndn.ParseCertificateName(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,158 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn
+
+import (
+	"crypto"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/eric135/go-ndn2/util"
+)
+
+// Legacy signed command Interests append four GenericNameComponents to the command name: a timestamp (milliseconds since the Unix epoch, as an NNI), a random nonce (as an NNI), the encoded SignatureInfo, and the encoded SignatureValue. The signature covers the name components up to and including the SignatureInfo component.
+//
+// This is the format expected by NFD management before the adoption of Signed Interest (NDN packet format v0.3) in NFD 22.02. Later releases of NFD accept both formats.
+const legacyCommandComponents = 4
+
+// MakeLegacyCommand converts the Interest into a legacy signed command Interest by appending the timestamp, nonce, SignatureInfo, and SignatureValue components to its name, signing it with the specified signer. Legacy command Interests cannot carry ApplicationParameters.
+func (i *Interest) MakeLegacyCommand(signer Signer) error {
+	if signer == nil {
+		return util.ErrNonExistent
+	}
+	if len(i.parameters) > 0 {
+		return errors.New("Legacy command Interests cannot carry ApplicationParameters")
+	}
+
+	var nonce [8]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return err
+	}
+	signatureInfo := NewSignatureInfo(signer.Type())
+	signatureInfo.SetKeyLocator(signer.KeyLocator())
+	signatureInfoWire, err := signatureInfo.Encode().Wire()
+	if err != nil {
+		return err
+	}
+
+	name := i.name.DeepCopy()
+	name.Append(NewGenericNameComponent(tlv.EncodeNNI(uint64(time.Now().UnixNano() / int64(time.Millisecond)))))
+	name.Append(NewGenericNameComponent(tlv.EncodeNNI(binary.BigEndian.Uint64(nonce[:]))))
+	name.Append(NewGenericNameComponent(signatureInfoWire))
+	if err := name.checkLimits(); err != nil {
+		return err
+	}
+
+	signatureValue, err := signer.ComputeSignatureValue(legacyCommandSignedPortion(name))
+	if err != nil {
+		return err
+	}
+	signatureValueWire, err := tlv.NewBlock(tlv.SignatureValue, signatureValue).Wire()
+	if err != nil {
+		return err
+	}
+	name.Append(NewGenericNameComponent(signatureValueWire))
+
+	i.name = *name
+	i.wire = nil
+	return nil
+}
+
+// LegacyCommandTimestamp returns the timestamp of a legacy signed command Interest, which recipients should use to reject replayed commands.
+func (i *Interest) LegacyCommandTimestamp() (time.Time, error) {
+	timestamp, _, _, err := i.parseLegacyCommand()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, 0).Add(time.Duration(timestamp) * time.Millisecond), nil
+}
+
+// LegacyCommandSignatureInfo returns the SignatureInfo of a legacy signed command Interest.
+func (i *Interest) LegacyCommandSignatureInfo() (*SignatureInfo, error) {
+	_, signatureInfo, _, err := i.parseLegacyCommand()
+	return signatureInfo, err
+}
+
+// VerifyLegacyCommand verifies the signature of a legacy signed command Interest using the specified public key, which is ignored for DigestSha256 signatures. Checking that the key is trusted and that the timestamp is not replayed are left to the caller.
+func (i *Interest) VerifyLegacyCommand(publicKey crypto.PublicKey) error {
+	_, signatureInfo, signatureValue, err := i.parseLegacyCommand()
+	if err != nil {
+		return err
+	}
+	return verifySignature(signatureInfo.signatureType, publicKey, legacyCommandSignedPortion(i.name.Prefix(i.name.Size()-1)), signatureValue)
+}
+
+// parseLegacyCommand decodes the timestamp, SignatureInfo, and SignatureValue from the name of a legacy signed command Interest.
+func (i *Interest) parseLegacyCommand() (uint64, *SignatureInfo, []byte, error) {
+	return parseLegacyCommandName(&i.name)
+}
+
+// parseLegacyCommandName decodes the timestamp, SignatureInfo, and SignatureValue from the final components of the name of a legacy signed command Interest.
+func parseLegacyCommandName(name *Name) (uint64, *SignatureInfo, []byte, error) {
+	size := name.Size()
+	if size < legacyCommandComponents {
+		return 0, nil, nil, errors.New("Name is too short for a legacy command Interest")
+	}
+	for index := size - legacyCommandComponents; index < size; index++ {
+		if name.At(index).Type() != tlv.GenericNameComponent {
+			return 0, nil, nil, errors.New("Legacy command Interest components must be GenericNameComponents")
+		}
+	}
+
+	timestamp, err := tlv.DecodeNNI(componentValueRef(name.At(size - 4)))
+	if err != nil {
+		return 0, nil, nil, errors.New("Error decoding legacy command timestamp")
+	}
+
+	signatureInfoBlock, err := decodeSingleBlock(componentValueRef(name.At(size - 2)))
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	if signatureInfoBlock.Type() != tlv.SignatureInfo {
+		return 0, nil, nil, tlv.ErrUnexpected
+	}
+	signatureInfo, err := DecodeSignatureInfo(signatureInfoBlock)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	signatureValueBlock, err := decodeSingleBlock(componentValueRef(name.At(size - 1)))
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	if signatureValueBlock.Type() != tlv.SignatureValue {
+		return 0, nil, nil, tlv.ErrUnexpected
+	}
+	return timestamp, signatureInfo, signatureValueBlock.Value(), nil
+}
+
+// decodeSingleBlock decodes a block that must occupy the entire buffer.
+func decodeSingleBlock(wire []byte) (*tlv.Block, error) {
+	block, blockLen, err := tlv.DecodeBlock(wire)
+	if err != nil {
+		return nil, err
+	}
+	if blockLen != uint64(len(wire)) {
+		return nil, errors.New("Trailing data after " + tlv.TypeName(block.Type()))
+	}
+	return block, nil
+}
+
+// legacyCommandSignedPortion returns the encoded components of the specified name, which is the command name up to and including the SignatureInfo component.
+func legacyCommandSignedPortion(name *Name) []byte {
+	var signedPortion []byte
+	for _, component := range name.components {
+		// Name components always encode successfully
+		componentWire, _ := component.Encode().Wire()
+		signedPortion = append(signedPortion, componentWire...)
+	}
+	return signedPortion
+}
@@ -0,0 +1,99 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	ndn "github.com/eric135/go-ndn2"
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterestLegacyCommand(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	keyName, _ := ndn.NameFromString("/go/KEY/abcd")
+	name, _ := ndn.NameFromString("/localhost/nfd/rib/register")
+
+	before := time.Now().Truncate(time.Millisecond)
+	i := ndn.NewInterest(name)
+	assert.NoError(t, i.MakeLegacyCommand(ndn.NewEcdsaSigner(keyName, key)))
+	after := time.Now()
+	assert.Equal(t, 8, i.Name().Size())
+	assert.True(t, name.PrefixOf(i.Name()))
+
+	timestamp, err := i.LegacyCommandTimestamp()
+	assert.NoError(t, err)
+	assert.False(t, timestamp.Before(before))
+	assert.False(t, timestamp.After(after))
+	signatureInfo, err := i.LegacyCommandSignatureInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(ndn.SignatureSha256WithEcdsa), signatureInfo.SignatureType())
+	assert.Equal(t, "/go/KEY/abcd", signatureInfo.KeyLocator().Name().String())
+	assert.NoError(t, i.VerifyLegacyCommand(&key.PublicKey))
+
+	// Survives encoding and decoding
+	encoded, err := i.Encode()
+	assert.NoError(t, err)
+	decoded, err := ndn.DecodeInterest(encoded)
+	assert.NoError(t, err)
+	assert.NoError(t, decoded.VerifyLegacyCommand(&key.PublicKey))
+
+	// Wrong key
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	assert.Error(t, i.VerifyLegacyCommand(&otherKey.PublicKey))
+
+	// Tampered command name
+	tampered := i.Name()
+	assert.NoError(t, tampered.Set(3, ndn.NewGenericNameComponent([]byte("unregister"))))
+	i.SetName(tampered)
+	assert.Error(t, i.VerifyLegacyCommand(&key.PublicKey))
+
+	// Two commands differ in their nonce components
+	i1 := ndn.NewInterest(name)
+	assert.NoError(t, i1.MakeLegacyCommand(ndn.NewSha256Signer()))
+	i2 := ndn.NewInterest(name)
+	assert.NoError(t, i2.MakeLegacyCommand(ndn.NewSha256Signer()))
+	assert.False(t, i1.Name().At(5).Equals(i2.Name().At(5)))
+}
+
+func TestInterestLegacyCommandDigest(t *testing.T) {
+	name, _ := ndn.NameFromString("/localhost/nfd/status")
+	i := ndn.NewInterest(name)
+	assert.NoError(t, i.MakeLegacyCommand(ndn.NewSha256Signer()))
+	signatureInfo, err := i.LegacyCommandSignatureInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(ndn.SignatureDigestSha256), signatureInfo.SignatureType())
+	assert.Nil(t, signatureInfo.KeyLocator())
+	assert.NoError(t, i.VerifyLegacyCommand(nil))
+}
+
+func TestInterestLegacyCommandInvalid(t *testing.T) {
+	name, _ := ndn.NameFromString("/localhost/nfd/rib/register")
+	i := ndn.NewInterest(name)
+	assert.Error(t, i.MakeLegacyCommand(nil))
+
+	// Not a command Interest
+	_, err := i.LegacyCommandTimestamp()
+	assert.Error(t, err)
+	assert.Error(t, i.VerifyLegacyCommand(nil))
+	short, _ := ndn.NameFromString("/localhost")
+	_, err = ndn.NewInterest(short).LegacyCommandSignatureInfo()
+	assert.Error(t, err)
+
+	// ApplicationParameters are not supported
+	withParameters := ndn.NewInterest(name)
+	withParameters.AppendApplicationParameter(tlv.NewBlock(tlv.ApplicationParameters, []byte{0x01}))
+	assert.Error(t, withParameters.MakeLegacyCommand(ndn.NewSha256Signer()))
+}
@@ -0,0 +1,103 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn
+
+import (
+	"errors"
+	"time"
+)
+
+// DataBuilder constructs signed Data packets using chainable setters.
+type DataBuilder struct {
+	name     *Name
+	content  []byte
+	metaInfo *MetaInfo
+	signer   Signer
+}
+
+// NewDataBuilder creates an empty DataBuilder.
+func NewDataBuilder() *DataBuilder {
+	return new(DataBuilder)
+}
+
+// Name sets the name of the Data.
+func (b *DataBuilder) Name(name *Name) *DataBuilder {
+	if name == nil {
+		b.name = nil
+	} else {
+		b.name = name.DeepCopy()
+	}
+	return b
+}
+
+// Content sets the content of the Data.
+func (b *DataBuilder) Content(content []byte) *DataBuilder {
+	if content == nil {
+		b.content = nil
+	} else {
+		b.content = make([]byte, len(content))
+		copy(b.content, content)
+	}
+	return b
+}
+
+// ContentType sets the content type of the Data.
+func (b *DataBuilder) ContentType(contentType uint64) *DataBuilder {
+	b.ensureMetaInfo().SetContentType(&contentType)
+	return b
+}
+
+// FreshnessPeriod sets the freshness period of the Data.
+func (b *DataBuilder) FreshnessPeriod(freshnessPeriod time.Duration) *DataBuilder {
+	b.ensureMetaInfo().SetFreshnessPeriod(&freshnessPeriod)
+	return b
+}
+
+// FinalBlockID sets the final block ID of the Data.
+func (b *DataBuilder) FinalBlockID(finalBlockID NameComponent) *DataBuilder {
+	b.ensureMetaInfo().SetFinalBlockID(finalBlockID)
+	return b
+}
+
+// Sign sets the signer used to sign the Data. If no signer is set, the Data is signed with DigestSha256.
+func (b *DataBuilder) Sign(signer Signer) *DataBuilder {
+	b.signer = signer
+	return b
+}
+
+// ensureMetaInfo returns the MetaInfo of the builder, creating it if necessary.
+func (b *DataBuilder) ensureMetaInfo() *MetaInfo {
+	if b.metaInfo == nil {
+		b.metaInfo = NewMetaInfo()
+	}
+	return b.metaInfo
+}
+
+// Build constructs, signs, and encodes the Data.
+func (b *DataBuilder) Build() (*Data, error) {
+	if b.name == nil {
+		return nil, errors.New("Name must be set to build Data")
+	}
+
+	d := NewData(b.name, b.content)
+	if b.metaInfo != nil {
+		d.SetMetaInfo(b.metaInfo)
+	}
+
+	signer := b.signer
+	if signer == nil {
+		signer = NewSha256Signer()
+	}
+	if err := d.Sign(signer); err != nil {
+		return nil, err
+	}
+	if _, err := d.Encode(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
@@ -0,0 +1,68 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	ndn "github.com/eric135/go-ndn2"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingSigner struct{}
+
+func (s *failingSigner) Type() uint64                { return ndn.SignatureSha256WithEcdsa }
+func (s *failingSigner) KeyLocator() *ndn.KeyLocator { return nil }
+func (s *failingSigner) ComputeSignatureValue(signedPortion []byte) ([]byte, error) {
+	return nil, errors.New("Signing failed")
+}
+
+func TestDataBuilder(t *testing.T) {
+	name, _ := ndn.NameFromString("/go/ndn")
+	d, err := ndn.NewDataBuilder().
+		Name(name).
+		Content([]byte{0x01, 0x02}).
+		ContentType(ndn.ContentTypeBlob).
+		FreshnessPeriod(10 * time.Second).
+		FinalBlockID(ndn.NewSegmentNameComponent(3)).
+		Build()
+	assert.NoError(t, err)
+	assert.True(t, d.HasWire())
+	assert.Equal(t, "/go/ndn", d.Name().String())
+	assert.Equal(t, []byte{0x01, 0x02}, d.Content())
+	assert.Equal(t, uint64(ndn.ContentTypeBlob), *d.MetaInfo().ContentType())
+	assert.Equal(t, 10*time.Second, *d.MetaInfo().FreshnessPeriod())
+	assert.Equal(t, "seg=3", d.MetaInfo().FinalBlockID().String())
+	assert.Equal(t, uint64(ndn.SignatureDigestSha256), d.SignatureInfo().SignatureType())
+	assert.Len(t, d.SignatureValue(), 32)
+
+	// Custom signer
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	keyName, _ := ndn.NameFromString("/go/KEY/abcd")
+	d, err = ndn.NewDataBuilder().Name(name).Sign(ndn.NewEcdsaSigner(keyName, key)).Build()
+	assert.NoError(t, err)
+	assert.Nil(t, d.MetaInfo())
+	assert.Equal(t, uint64(ndn.SignatureSha256WithEcdsa), d.SignatureInfo().SignatureType())
+	assert.Equal(t, "/go/KEY/abcd", d.SignatureInfo().KeyLocator().Name().String())
+
+	// Missing name
+	d, err = ndn.NewDataBuilder().Content([]byte{0x01}).Build()
+	assert.Nil(t, d)
+	assert.Error(t, err)
+
+	// Signing failure
+	d, err = ndn.NewDataBuilder().Name(name).Sign(new(failingSigner)).Build()
+	assert.Nil(t, d)
+	assert.Error(t, err)
+}
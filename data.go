@@ -7,27 +7,530 @@
 
 package ndn
 
-import "github.com/eric135/go-ndn2/tlv"
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/eric135/go-ndn2/util"
+)
+
+// MaxPacketSize is the maximum size of an encoded packet that can be sent on most faces, in bytes. Larger packets must be fragmented by the link layer or are dropped by faces that cannot fragment them.
+const MaxPacketSize = 8800
+
+// checkPacketSize returns util.ErrTooLong if the encoded packet is larger than the specified maximum size.
+func checkPacketSize(wire *tlv.Block, maxSize int) error {
+	if wire.Size() > maxSize {
+		return util.ErrTooLong
+	}
+	return nil
+}
 
 // Data represents an NDN Data packet.
 type Data struct {
-	// TODO
+	name           Name
+	metaInfo       *MetaInfo
+	content        []byte
+	signatureInfo  *SignatureInfo
+	signatureValue []byte
+	wire           *tlv.Block
 }
 
-// DecodeData decodes a Data packet from the wire.
-func DecodeData(wire *tlv.Block) *Data {
-	// TODO
-	return nil
+// NewData creates a new Data packet with the specified name and content.
+func NewData(name *Name, content []byte) *Data {
+	d := new(Data)
+	d.name = *name.DeepCopy()
+	d.SetContent(content)
+	return d
+}
+
+// DecodeData decodes a Data packet from the wire, tolerating non-canonical encodings.
+func DecodeData(wire *tlv.Block) (*Data, error) {
+	return DecodeDataWithOptions(wire, tlv.DecodeOptions{})
+}
+
+// DecodeDataWithOptions decodes a Data packet from the wire, checking the encoding according to the specified options.
+func DecodeDataWithOptions(wire *tlv.Block, opts tlv.DecodeOptions) (*Data, error) {
+	if wire == nil {
+		return nil, util.ErrNonExistent
+	}
+	if !wire.Parse() {
+		return nil, tlv.ErrBufferTooShort
+	}
+	if err := checkElements(wire, opts); err != nil {
+		return nil, err
+	}
+
+	d := new(Data)
+	d.wire = wire.DeepCopy()
+	order := newElementOrder(opts)
+	hasName := false
+	for _, elem := range wire.Subelements() {
+		switch elem.Type() {
+		case tlv.Name:
+			if err := order.check(1, "Name"); err != nil {
+				return nil, err
+			}
+			hasName = true
+			nameWire, err := elem.Wire()
+			if err != nil {
+				return nil, err
+			}
+			name, _, err := decodeNameFromBuffer(nameWire, opts)
+			if err != nil {
+				return nil, err
+			}
+			d.name = *name
+		case tlv.MetaInfo:
+			if err := order.check(2, "MetaInfo"); err != nil {
+				return nil, err
+			}
+			metaInfo, err := decodeMetaInfo(elem, opts)
+			if err != nil {
+				return nil, err
+			}
+			d.metaInfo = metaInfo
+		case tlv.Content:
+			if err := order.check(3, "Content"); err != nil {
+				return nil, err
+			}
+			d.content = make([]byte, len(elem.Value()))
+			copy(d.content, elem.Value())
+		case tlv.SignatureInfo:
+			if err := order.check(4, "SignatureInfo"); err != nil {
+				return nil, err
+			}
+			signatureInfo, err := decodeSignatureInfo(elem, opts)
+			if err != nil {
+				return nil, err
+			}
+			d.signatureInfo = signatureInfo
+		case tlv.SignatureValue:
+			if err := order.check(5, "SignatureValue"); err != nil {
+				return nil, err
+			}
+			d.signatureValue = make([]byte, len(elem.Value()))
+			copy(d.signatureValue, elem.Value())
+		default:
+			if tlv.IsCritical(elem.Type()) {
+				return nil, tlv.ErrUnrecognizedCritical
+			}
+			// If non-critical, ignore
+		}
+	}
+
+	if !hasName {
+		return nil, errors.New("Data is missing Name")
+	}
+	if d.signatureInfo == nil || d.signatureValue == nil {
+		return nil, errors.New("Data is missing signature")
+	}
+
+	return d, nil
+}
+
+func (d *Data) String() string {
+	str := "Data(Name=" + d.name.String()
+	if d.metaInfo != nil {
+		str += ", " + d.metaInfo.String()
+	}
+	str += ", Content=" + strconv.Itoa(len(d.content)) + "B"
+	if d.signatureInfo != nil {
+		str += ", " + d.signatureInfo.String()
+	}
+	str += ")"
+	return str
 }
 
 // DeepCopy returns a deep copy of the Data.
 func (d *Data) DeepCopy() *Data {
-	// TODO
-	return nil
+	copyD := new(Data)
+	copyD.name = *d.name.DeepCopy()
+	if d.metaInfo != nil {
+		copyD.metaInfo = d.metaInfo.DeepCopy()
+	}
+	if d.content != nil {
+		copyD.content = make([]byte, len(d.content))
+		copy(copyD.content, d.content)
+	}
+	if d.signatureInfo != nil {
+		copyD.signatureInfo = d.signatureInfo.DeepCopy()
+	}
+	if d.signatureValue != nil {
+		copyD.signatureValue = make([]byte, len(d.signatureValue))
+		copy(copyD.signatureValue, d.signatureValue)
+	}
+	if d.wire != nil {
+		copyD.wire = d.wire.DeepCopy()
+	}
+	return copyD
+}
+
+//////////////////
+// Setters/Getters
+//////////////////
+
+// Name returns a copy of the name of the Data.
+func (d *Data) Name() *Name {
+	return d.name.DeepCopy()
+}
+
+// SetName sets the name of the Data.
+func (d *Data) SetName(name *Name) {
+	d.name = *name.DeepCopy()
+	d.wire = nil
+}
+
+// MetaInfo returns a copy of the MetaInfo of the Data or nil if no MetaInfo is set.
+func (d *Data) MetaInfo() *MetaInfo {
+	if d.metaInfo == nil {
+		return nil
+	}
+	return d.metaInfo.DeepCopy()
+}
+
+// SetMetaInfo sets the MetaInfo of the Data (or unsets it if nil is specified).
+func (d *Data) SetMetaInfo(metaInfo *MetaInfo) {
+	if metaInfo == nil {
+		d.metaInfo = nil
+	} else {
+		d.metaInfo = metaInfo.DeepCopy()
+	}
+	d.wire = nil
+}
+
+// Content returns a copy of the content of the Data.
+func (d *Data) Content() []byte {
+	content := make([]byte, len(d.content))
+	copy(content, d.content)
+	return content
+}
+
+// ContentBlock decodes the content of the Data as a single TLV element, returning an error if the content is absent or is not exactly one well-formed TLV element.
+func (d *Data) ContentBlock() (*tlv.Block, error) {
+	if d.content == nil {
+		return nil, util.ErrNonExistent
+	}
+	return decodeSingleBlock(d.content)
+}
+
+// ContentAs decodes the content of the Data as a single TLV element into the struct pointed to by v using tlv.Unmarshal.
+func (d *Data) ContentAs(v interface{}) error {
+	block, err := d.ContentBlock()
+	if err != nil {
+		return err
+	}
+	return tlv.Unmarshal(block, v)
+}
+
+// SetContent sets the content of the Data (or unsets it if nil is specified).
+func (d *Data) SetContent(content []byte) {
+	if content == nil {
+		d.content = nil
+	} else {
+		d.content = make([]byte, len(content))
+		copy(d.content, content)
+	}
+	d.wire = nil
+}
+
+// SignatureInfo returns a copy of the SignatureInfo of the Data or nil if no SignatureInfo is set.
+func (d *Data) SignatureInfo() *SignatureInfo {
+	if d.signatureInfo == nil {
+		return nil
+	}
+	return d.signatureInfo.DeepCopy()
+}
+
+// SetSignatureInfo sets the SignatureInfo of the Data (or unsets it if nil is specified).
+func (d *Data) SetSignatureInfo(signatureInfo *SignatureInfo) {
+	if signatureInfo == nil {
+		d.signatureInfo = nil
+	} else {
+		d.signatureInfo = signatureInfo.DeepCopy()
+	}
+	d.wire = nil
+}
+
+// SignatureValue returns a copy of the signature value of the Data.
+func (d *Data) SignatureValue() []byte {
+	signatureValue := make([]byte, len(d.signatureValue))
+	copy(signatureValue, d.signatureValue)
+	return signatureValue
+}
+
+// SetSignatureValue sets the signature value of the Data.
+func (d *Data) SetSignatureValue(signatureValue []byte) {
+	d.signatureValue = make([]byte, len(signatureValue))
+	copy(d.signatureValue, signatureValue)
+	d.wire = nil
 }
 
+///////////
+// Encoding
+///////////
+
 // Encode encodes the Data into a block.
-func (d *Data) Encode() *tlv.Block {
-	// TODO
+func (d *Data) Encode() (*tlv.Block, error) {
+	wire, err := d.encodeWire()
+	if err != nil {
+		return nil, err
+	}
+	return wire.DeepCopy(), nil
+}
+
+// EncodeWithMaxSize encodes the Data into a block like Encode, but returns util.ErrTooLong if the encoded Data is larger than the specified maximum size, such as MaxPacketSize. This allows producers to detect packets that would be dropped by faces that cannot fragment them when encoding, rather than after sending.
+func (d *Data) EncodeWithMaxSize(maxSize int) (*tlv.Block, error) {
+	wire, err := d.encodeWire()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPacketSize(wire, maxSize); err != nil {
+		return nil, err
+	}
+	return wire.DeepCopy(), nil
+}
+
+// WriteTo writes the wire encoding of the Data to the specified writer. The cached wire is used if present, avoiding any allocation; otherwise, the Data is encoded and the wire cached.
+func (d *Data) WriteTo(w io.Writer) (int64, error) {
+	wire, err := d.encodeWire()
+	if err != nil {
+		return 0, err
+	}
+	return wire.WriteTo(w)
+}
+
+// encodeWire returns the cached wire of the Data, encoding it first if necessary. The returned block must not be modified.
+func (d *Data) encodeWire() (*tlv.Block, error) {
+	if d.wire != nil {
+		if _, err := d.wire.Wire(); err != nil {
+			return nil, err
+		}
+		return d.wire, nil
+	}
+
+	// Validate fields
+	if d.signatureInfo == nil {
+		return nil, errors.New("SignatureInfo must be set to encode")
+	}
+
+	wire := tlv.NewEmptyBlock(tlv.Data)
+
+	// Name
+	wire.Append(d.name.Encode())
+
+	// MetaInfo
+	if d.metaInfo != nil {
+		wire.Append(d.metaInfo.Encode())
+	}
+
+	// Content
+	if d.content != nil {
+		wire.Append(tlv.NewBlock(tlv.Content, d.content))
+	}
+
+	// SignatureInfo
+	wire.Append(d.signatureInfo.Encode())
+
+	// SignatureValue
+	wire.Append(tlv.NewBlock(tlv.SignatureValue, d.signatureValue))
+
+	if _, err := wire.Wire(); err != nil {
+		return nil, err
+	}
+	d.wire = wire
+	return d.wire, nil
+}
+
+// VerifyCanonicalEncoding checks that the wire encoding of the Data is canonical, so that other implementations re-encoding it would produce the same signed bytes. The wire is decoded strictly, rejecting non-minimal numbers and out-of-order elements, and then re-encoded from the decoded fields, which must reproduce it exactly. Unrecognized elements are not re-encoded, so they also cause an error. This is intended as a self-check for producers, such as in tests.
+func (d *Data) VerifyCanonicalEncoding() error {
+	wire, err := d.encodeWire()
+	if err != nil {
+		return err
+	}
+	original, err := wire.Wire()
+	if err != nil {
+		return err
+	}
+
+	decoded, err := DecodeDataWithOptions(wire.DeepCopy(), tlv.DecodeOptions{Strict: true})
+	if err != nil {
+		return err
+	}
+	decoded = decoded.DeepCopy()
+	decoded.wire = nil
+	reencoded, err := decoded.encodeWire()
+	if err != nil {
+		return err
+	}
+	reencodedWire, err := reencoded.Wire()
+	if err != nil {
+		return err
+	}
+	return compareEncodings(original, reencodedWire)
+}
+
+// signedPortion returns the encoded elements of the Data covered by its signature (Name through SignatureInfo). If the Data has a wire encoding, such as when it was decoded and not modified since, the elements are taken from the wire as-is, so that the signature over a non-canonical encoding still verifies.
+func (d *Data) signedPortion() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := d.writeSignedPortion(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeSignedPortion writes the signed portion of the Data, as returned by signedPortion, to the writer, allowing it to be hashed incrementally without collecting it into an intermediate buffer.
+func (d *Data) writeSignedPortion(w io.Writer) error {
+	if d.signatureInfo == nil {
+		return errors.New("SignatureInfo must be set to compute signed portion")
+	}
+
+	if d.wire != nil {
+		for _, elem := range d.wire.Subelements() {
+			if elem.Type() == tlv.SignatureValue {
+				return nil
+			}
+			if _, err := elem.WriteTo(w); err != nil {
+				return err
+			}
+		}
+		return errors.New("Data is missing SignatureValue")
+	}
+
+	if _, err := d.name.WriteTo(w); err != nil {
+		return err
+	}
+	if d.metaInfo != nil {
+		if _, err := d.metaInfo.Encode().WriteTo(w); err != nil {
+			return err
+		}
+	}
+	if d.content != nil {
+		// Write the Content directly, rather than copying it into a block
+		if _, err := w.Write(tlv.EncodeVarNum(tlv.Content)); err != nil {
+			return err
+		}
+		if _, err := w.Write(tlv.EncodeVarNum(uint64(len(d.content)))); err != nil {
+			return err
+		}
+		if _, err := w.Write(d.content); err != nil {
+			return err
+		}
+	}
+	_, err := d.signatureInfo.Encode().WriteTo(w)
+	return err
+}
+
+// Sign signs the Data with the specified signer, replacing the signature type and KeyLocator in its SignatureInfo.
+func (d *Data) Sign(signer Signer) error {
+	if signer == nil {
+		return util.ErrNonExistent
+	}
+
+	signatureInfo := d.signatureInfo
+	if signatureInfo == nil {
+		signatureInfo = NewSignatureInfo(signer.Type())
+	} else {
+		signatureInfo = signatureInfo.DeepCopy()
+		signatureInfo.SetSignatureType(signer.Type())
+	}
+	signatureInfo.SetKeyLocator(signer.KeyLocator())
+	d.signatureInfo = signatureInfo
+	d.wire = nil
+
+	var signatureValue []byte
+	if ds, ok := signer.(digestSigner); ok {
+		h := sha256.New()
+		if err := d.writeSignedPortion(h); err != nil {
+			return err
+		}
+		var err error
+		if signatureValue, err = ds.signDigest(h.Sum(nil)); err != nil {
+			return err
+		}
+	} else {
+		signedPortion, err := d.signedPortion()
+		if err != nil {
+			return err
+		}
+		if signatureValue, err = signer.ComputeSignatureValue(signedPortion); err != nil {
+			return err
+		}
+	}
+	d.signatureValue = signatureValue
 	return nil
 }
+
+// Verify checks the signature of the Data with the specified verifier, returning an error if the signature type of the Data does not match that of the verifier or the signature is invalid.
+func (d *Data) Verify(verifier Verifier) error {
+	if verifier == nil {
+		return util.ErrNonExistent
+	}
+	if d.signatureInfo == nil || d.signatureInfo.signatureType != verifier.Type() {
+		return errors.New("Signature type does not match verifier")
+	}
+	if dv, ok := verifier.(digestVerifier); ok {
+		h := sha256.New()
+		if err := d.writeSignedPortion(h); err != nil {
+			return err
+		}
+		return dv.verifyDigest(h.Sum(nil), d.signatureValue)
+	}
+	signedPortion, err := d.signedPortion()
+	if err != nil {
+		return err
+	}
+	return verifier.Verify(signedPortion, d.signatureValue)
+}
+
+// ImplicitDigest returns the SHA-256 digest of the encoded Data.
+func (d *Data) ImplicitDigest() ([]byte, error) {
+	h := sha256.New()
+	if _, err := d.WriteTo(h); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// FullName returns the name of the Data with its implicit digest appended.
+func (d *Data) FullName() (*Name, error) {
+	digest, err := d.ImplicitDigest()
+	if err != nil {
+		return nil, err
+	}
+	return d.name.DeepCopy().Append(NewImplicitSha256DigestComponent(digest)), nil
+}
+
+// HasWire returns whether a wire encoding exists for the Data.
+func (d *Data) HasWire() bool {
+	return d.wire != nil
+}
+
+// Equals returns whether the specified Data has the same wire encoding as this Data, including its MetaInfo, SignatureInfo, and SignatureValue. The cached wire of each Data is used if present; otherwise, it is encoded (and cached). Data that cannot be encoded is never equal to anything.
+func (d *Data) Equals(other *Data) bool {
+	if other == nil {
+		return false
+	}
+	if d == other {
+		return true
+	}
+	wire, err := d.encodeWire()
+	if err != nil {
+		return false
+	}
+	otherWire, err := other.encodeWire()
+	if err != nil {
+		return false
+	}
+	wireBytes, _ := wire.Wire()
+	otherWireBytes, _ := otherWire.Wire()
+	return bytes.Equal(wireBytes, otherWireBytes)
+}
+
+// SameContentAs returns whether the specified Data has the same Name and Content as this Data, ignoring its MetaInfo and signature, such as for Data signed separately by different producers. An absent Content is considered equal to an empty one.
+func (d *Data) SameContentAs(other *Data) bool {
+	return other != nil && d.name.Equals(&other.name) && bytes.Equal(d.content, other.content)
+}
@@ -0,0 +1,447 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	ndn "github.com/eric135/go-ndn2"
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/eric135/go-ndn2/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func dataTestWire() []byte {
+	return []byte{
+		tlv.Name, 0x09, tlv.GenericNameComponent, 0x02, 0x67, 0x6f, tlv.GenericNameComponent, 0x03, 0x6e, 0x64, 0x6e,
+		tlv.MetaInfo, 0x0c,
+		tlv.ContentType, 0x01, 0x00,
+		tlv.FreshnessPeriod, 0x02, 0x03, 0xe8,
+		tlv.FinalBlockID, 0x03, tlv.SegmentNameComponent, 0x01, 0x05,
+		tlv.Content, 0x04, 0x01, 0x02, 0x03, 0x04,
+		tlv.SignatureInfo, 0x0c,
+		tlv.SignatureType, 0x01, 0x03,
+		tlv.KeyLocator, 0x07, tlv.Name, 0x05, tlv.GenericNameComponent, 0x03, 0x6b, 0x65, 0x79,
+		tlv.SignatureValue, 0x04, 0xaa, 0xbb, 0xcc, 0xdd}
+}
+
+func TestDataCreate(t *testing.T) {
+	name, err := ndn.NameFromString("/go/ndn")
+	assert.NoError(t, err)
+	d := ndn.NewData(name, []byte{0x01, 0x02, 0x03, 0x04})
+	assert.Equal(t, "/go/ndn", d.Name().String())
+	assert.Equal(t, []byte{0x01, 0x02, 0x03, 0x04}, d.Content())
+	assert.Nil(t, d.MetaInfo())
+	assert.Nil(t, d.SignatureInfo())
+	assert.Equal(t, "Data(Name=/go/ndn, Content=4B)", d.String())
+
+	// Cannot encode without SignatureInfo
+	encoded, err := d.Encode()
+	assert.Nil(t, encoded)
+	assert.Error(t, err)
+	assert.False(t, d.HasWire())
+
+	d.SetSignatureInfo(ndn.NewSignatureInfo(ndn.SignatureDigestSha256))
+	d.SetSignatureValue([]byte{0xaa})
+	encoded, err = d.Encode()
+	assert.NoError(t, err)
+	assert.True(t, d.HasWire())
+	wire, err := encoded.Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{tlv.Data, 0x19,
+		tlv.Name, 0x09, tlv.GenericNameComponent, 0x02, 0x67, 0x6f, tlv.GenericNameComponent, 0x03, 0x6e, 0x64, 0x6e,
+		tlv.Content, 0x04, 0x01, 0x02, 0x03, 0x04,
+		tlv.SignatureInfo, 0x03, tlv.SignatureType, 0x01, 0x00,
+		tlv.SignatureValue, 0x01, 0xaa}, wire)
+
+	// Round trip
+	decoded, err := ndn.DecodeData(encoded)
+	assert.NoError(t, err)
+	assert.NotNil(t, decoded)
+	assert.True(t, d.Name().Equals(decoded.Name()))
+	assert.Equal(t, d.Content(), decoded.Content())
+	assert.Equal(t, uint64(ndn.SignatureDigestSha256), decoded.SignatureInfo().SignatureType())
+	assert.Equal(t, []byte{0xaa}, decoded.SignatureValue())
+}
+
+func TestDataDecode(t *testing.T) {
+	d, err := ndn.DecodeData(tlv.NewBlock(tlv.Data, dataTestWire()))
+	assert.NoError(t, err)
+	assert.NotNil(t, d)
+	assert.True(t, d.HasWire())
+	assert.Equal(t, "/go/ndn", d.Name().String())
+	assert.Equal(t, uint64(ndn.ContentTypeBlob), *d.MetaInfo().ContentType())
+	assert.Equal(t, 1000*time.Millisecond, *d.MetaInfo().FreshnessPeriod())
+	assert.Equal(t, "seg=5", d.MetaInfo().FinalBlockID().String())
+	assert.Equal(t, []byte{0x01, 0x02, 0x03, 0x04}, d.Content())
+	assert.Equal(t, uint64(ndn.SignatureSha256WithEcdsa), d.SignatureInfo().SignatureType())
+	assert.Equal(t, "/key", d.SignatureInfo().KeyLocator().Name().String())
+	assert.Nil(t, d.SignatureInfo().KeyLocator().Digest())
+	assert.Equal(t, []byte{0xaa, 0xbb, 0xcc, 0xdd}, d.SignatureValue())
+	assert.Equal(t, "Data(Name=/go/ndn, MetaInfo(ContentType=0, FreshnessPeriod=1000ms, FinalBlockId=seg=5), Content=4B, SignatureInfo(SignatureType=3, KeyLocator(Name=/key)))", d.String())
+
+	// Re-encoding an unmodified Data produces the same wire
+	encoded, err := d.Encode()
+	assert.NoError(t, err)
+	wire, err := encoded.Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, append([]byte{tlv.Data, byte(len(dataTestWire()))}, dataTestWire()...), wire)
+
+	// Re-encoding a modified Data produces the same elements
+	d.SetContent([]byte{0x01, 0x02, 0x03, 0x04})
+	assert.False(t, d.HasWire())
+	encoded, err = d.Encode()
+	assert.NoError(t, err)
+	wire, err = encoded.Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, append([]byte{tlv.Data, byte(len(dataTestWire()))}, dataTestWire()...), wire)
+}
+
+func TestDataDecodeInvalid(t *testing.T) {
+	// Missing Name
+	d, err := ndn.DecodeData(tlv.NewBlock(tlv.Data, dataTestWire()[11:]))
+	assert.Nil(t, d)
+	assert.Error(t, err)
+
+	// Missing signature
+	d, err = ndn.DecodeData(tlv.NewBlock(tlv.Data, dataTestWire()[:31]))
+	assert.Nil(t, d)
+	assert.Error(t, err)
+
+	// Out-of-order elements are only rejected when decoding strictly
+	wire := dataTestWire()
+	outOfOrder := append(append(append([]byte{}, wire[25:31]...), wire[:25]...), wire[31:]...)
+	d, err = ndn.DecodeDataWithOptions(tlv.NewBlock(tlv.Data, outOfOrder), tlv.DecodeOptions{Strict: true})
+	assert.Nil(t, d)
+	assert.Error(t, err)
+	d, err = ndn.DecodeData(tlv.NewBlock(tlv.Data, outOfOrder))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x01, 0x02, 0x03, 0x04}, d.Content())
+
+	// Duplicate elements are always rejected
+	duplicate := append(append([]byte{}, wire[:31]...), wire[25:]...)
+	d, err = ndn.DecodeData(tlv.NewBlock(tlv.Data, duplicate))
+	assert.Nil(t, d)
+	assert.Error(t, err)
+
+	// Truncated element
+	d, err = ndn.DecodeData(tlv.NewBlock(tlv.Data, wire[:len(wire)-1]))
+	assert.Nil(t, d)
+	assert.Error(t, err)
+
+	// Non-minimal TLV-LENGTH of KeyLocator Name is only rejected when decoding strictly
+	nonMinimalKeyLocator := []byte{tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f,
+		tlv.SignatureInfo, 0x0c, tlv.SignatureType, 0x01, 0x03,
+		tlv.KeyLocator, 0x07, tlv.Name, 0xfd, 0x00, 0x03, tlv.GenericNameComponent, 0x01, 0x6b,
+		tlv.SignatureValue, 0x00}
+	d, err = ndn.DecodeDataWithOptions(tlv.NewBlock(tlv.Data, nonMinimalKeyLocator), tlv.DecodeOptions{Strict: true})
+	assert.Nil(t, d)
+	assert.Error(t, err)
+	d, err = ndn.DecodeData(tlv.NewBlock(tlv.Data, nonMinimalKeyLocator))
+	assert.NoError(t, err)
+	assert.Equal(t, "/k", d.SignatureInfo().KeyLocator().Name().String())
+}
+
+func TestDataDecodeEvolvability(t *testing.T) {
+	wire := dataTestWire()
+
+	// Unknown non-critical element between Content and SignatureInfo is ignored
+	withNonCritical := append(append(append([]byte{}, wire[:31]...), 0xF0, 0x02, 0x01, 0x02), wire[31:]...)
+	d, err := ndn.DecodeData(tlv.NewBlock(tlv.Data, withNonCritical))
+	assert.NoError(t, err)
+	assert.NotNil(t, d)
+	assert.Equal(t, "/go/ndn", d.Name().String())
+	assert.Equal(t, []byte{0x01, 0x02, 0x03, 0x04}, d.Content())
+	assert.Equal(t, uint64(ndn.SignatureSha256WithEcdsa), d.SignatureInfo().SignatureType())
+
+	// Unknown critical element is rejected
+	withCritical := append(append(append([]byte{}, wire[:31]...), 0xF1, 0x02, 0x01, 0x02), wire[31:]...)
+	d, err = ndn.DecodeData(tlv.NewBlock(tlv.Data, withCritical))
+	assert.Nil(t, d)
+	assert.Equal(t, tlv.ErrUnrecognizedCritical, err)
+
+	// Unknown non-critical element inside MetaInfo is ignored
+	metaInfo, err := ndn.DecodeMetaInfo(tlv.NewBlock(tlv.MetaInfo, []byte{tlv.ContentType, 0x01, 0x02, 0xF0, 0x00, tlv.FreshnessPeriod, 0x01, 0x0a}))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(ndn.ContentTypeKey), *metaInfo.ContentType())
+	assert.Equal(t, 10*time.Millisecond, *metaInfo.FreshnessPeriod())
+
+	// Unknown critical element inside MetaInfo is rejected
+	metaInfo, err = ndn.DecodeMetaInfo(tlv.NewBlock(tlv.MetaInfo, []byte{tlv.ContentType, 0x01, 0x02, 0xF1, 0x00}))
+	assert.Nil(t, metaInfo)
+	assert.Equal(t, tlv.ErrUnrecognizedCritical, err)
+}
+
+func TestMetaInfoSetFinalBlockID(t *testing.T) {
+	metaInfo := ndn.NewMetaInfo()
+	metaInfo.SetFinalBlockID(ndn.NewSegmentNameComponent(5))
+	assert.Equal(t, "seg=5", metaInfo.FinalBlockID().String())
+
+	// A nil pointer to a concrete component type unsets the final block ID
+	var component *ndn.GenericNameComponent
+	assert.NotPanics(t, func() { metaInfo.SetFinalBlockID(component) })
+	assert.Nil(t, metaInfo.FinalBlockID())
+
+	metaInfo.SetFinalBlockID(ndn.NewSegmentNameComponent(5))
+	metaInfo.SetFinalBlockID(nil)
+	assert.Nil(t, metaInfo.FinalBlockID())
+}
+
+func TestDataContentBlock(t *testing.T) {
+	type metadata struct {
+		_     struct{}  `tlv:"0x80"`
+		Name  *ndn.Name `tlv:"0x07"`
+		Count uint64    `tlv:"0x81,optional"`
+	}
+
+	name, _ := ndn.NameFromString("/go/ndn/32=metadata")
+	versioned, _ := ndn.NameFromString("/go/ndn/v=5")
+	encoded, err := tlv.Marshal(&metadata{Name: versioned, Count: 3})
+	assert.NoError(t, err)
+	content, err := encoded.Wire()
+	assert.NoError(t, err)
+	d := ndn.NewData(name, content)
+
+	block, err := d.ContentBlock()
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0x80), block.Type())
+	assert.True(t, block.Parse())
+	assert.Equal(t, 2, len(block.Subelements()))
+
+	var m metadata
+	assert.NoError(t, d.ContentAs(&m))
+	assert.True(t, versioned.Equals(m.Name))
+	assert.Equal(t, uint64(3), m.Count)
+
+	// Content of a different type
+	var other struct {
+		_ struct{} `tlv:"0x82"`
+	}
+	assert.Equal(t, tlv.ErrUnexpected, d.ContentAs(&other))
+
+	// Opaque content is not a TLV element
+	d.SetContent([]byte{0x01, 0x05, 0x02})
+	_, err = d.ContentBlock()
+	assert.Error(t, err)
+	assert.Error(t, d.ContentAs(&m))
+
+	// Trailing data after the element
+	d.SetContent([]byte{0x01, 0x01, 0x02, 0x03})
+	_, err = d.ContentBlock()
+	assert.Error(t, err)
+
+	// Absent content
+	d.SetContent(nil)
+	_, err = d.ContentBlock()
+	assert.Error(t, err)
+}
+
+func TestDataWriteTo(t *testing.T) {
+	d, err := ndn.DecodeData(tlv.NewBlock(tlv.Data, dataTestWire()))
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	written, err := d.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), written)
+	encoded, err := d.Encode()
+	assert.NoError(t, err)
+	wire, err := encoded.Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, wire, buf.Bytes())
+
+	allocs := testing.AllocsPerRun(100, func() {
+		d.WriteTo(ioutil.Discard)
+	})
+	assert.Equal(t, 0.0, allocs)
+}
+
+func BenchmarkDataWriteTo(b *testing.B) {
+	d, _ := ndn.DecodeData(tlv.NewBlock(tlv.Data, dataTestWire()))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		d.WriteTo(ioutil.Discard)
+	}
+}
+
+func BenchmarkDataEncodeWrite(b *testing.B) {
+	d, _ := ndn.DecodeData(tlv.NewBlock(tlv.Data, dataTestWire()))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		encoded, _ := d.Encode()
+		wire, _ := encoded.Wire()
+		ioutil.Discard.Write(wire)
+	}
+}
+
+func TestDataVerifyCanonicalEncoding(t *testing.T) {
+	// Constructed and canonically encoded Data
+	name, _ := ndn.NameFromString("/go/ndn")
+	d := ndn.NewData(name, []byte{0x01, 0x02})
+	assert.NoError(t, d.Sign(ndn.NewSha256Signer()))
+	assert.NoError(t, d.VerifyCanonicalEncoding())
+	decoded, err := ndn.DecodeData(tlv.NewBlock(tlv.Data, dataTestWire()))
+	assert.NoError(t, err)
+	assert.NoError(t, decoded.VerifyCanonicalEncoding())
+
+	for _, wire := range [][]byte{
+		// Non-minimal FreshnessPeriod
+		{tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f,
+			tlv.MetaInfo, 0x06, tlv.FreshnessPeriod, 0x04, 0x00, 0x00, 0x03, 0xe8,
+			tlv.SignatureInfo, 0x03, tlv.SignatureType, 0x01, 0x00,
+			tlv.SignatureValue, 0x00},
+		// Non-minimal TLV-LENGTH of Content
+		{tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f,
+			tlv.Content, 0xfd, 0x00, 0x01, 0xaa,
+			tlv.SignatureInfo, 0x03, tlv.SignatureType, 0x01, 0x00,
+			tlv.SignatureValue, 0x00},
+		// Content before MetaInfo
+		{tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f,
+			tlv.Content, 0x01, 0xaa,
+			tlv.MetaInfo, 0x03, tlv.ContentType, 0x01, 0x00,
+			tlv.SignatureInfo, 0x03, tlv.SignatureType, 0x01, 0x00,
+			tlv.SignatureValue, 0x00},
+		// Unrecognized non-critical element
+		{tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f,
+			tlv.Content, 0x01, 0xaa,
+			0xF0, 0x01, 0xbb,
+			tlv.SignatureInfo, 0x03, tlv.SignatureType, 0x01, 0x00,
+			tlv.SignatureValue, 0x00},
+	} {
+		d, err := ndn.DecodeData(tlv.NewBlock(tlv.Data, wire))
+		assert.NoError(t, err)
+		assert.Error(t, d.VerifyCanonicalEncoding())
+	}
+}
+
+func TestDataEncodeWithMaxSize(t *testing.T) {
+	name, _ := ndn.NameFromString("/go/ndn")
+	d := ndn.NewData(name, make([]byte, 100))
+	assert.NoError(t, d.Sign(ndn.NewSha256Signer()))
+	encoded, err := d.Encode()
+	assert.NoError(t, err)
+
+	limited, err := d.EncodeWithMaxSize(encoded.Size())
+	assert.NoError(t, err)
+	assert.Equal(t, encoded.Size(), limited.Size())
+	_, err = d.EncodeWithMaxSize(encoded.Size() - 1)
+	assert.Equal(t, util.ErrTooLong, err)
+
+	// Content that fits in a packet by itself but not with the rest of the Data
+	d = ndn.NewData(name, make([]byte, ndn.MaxPacketSize-10))
+	assert.NoError(t, d.Sign(ndn.NewSha256Signer()))
+	_, err = d.EncodeWithMaxSize(ndn.MaxPacketSize)
+	assert.Equal(t, util.ErrTooLong, err)
+	_, err = d.Encode()
+	assert.NoError(t, err)
+}
+
+func TestDataLargeContentDigests(t *testing.T) {
+	name, _ := ndn.NameFromString("/go/ndn")
+	content := bytes.Repeat([]byte{0x5a}, 4<<20)
+	d := ndn.NewData(name, content)
+	metaInfo := ndn.NewMetaInfo()
+	freshnessPeriod := time.Second
+	metaInfo.SetFreshnessPeriod(&freshnessPeriod)
+	d.SetMetaInfo(metaInfo)
+	assert.NoError(t, d.Sign(ndn.NewSha256Signer()))
+
+	// The streamed signature matches the digest of the buffered signed portion
+	input, signatureValue := signedPortion(t, d)
+	digest := sha256.Sum256(input)
+	assert.Equal(t, digest[:], signatureValue)
+
+	// The streamed implicit digest matches the digest of the buffered wire
+	encoded, err := d.Encode()
+	assert.NoError(t, err)
+	wire, err := encoded.Wire()
+	assert.NoError(t, err)
+	digest = sha256.Sum256(wire)
+	implicitDigest, err := d.ImplicitDigest()
+	assert.NoError(t, err)
+	assert.Equal(t, digest[:], implicitDigest)
+
+	// Decoded Data is hashed from its wire
+	decoded, err := ndn.DecodeData(encoded)
+	assert.NoError(t, err)
+	implicitDigest, err = decoded.ImplicitDigest()
+	assert.NoError(t, err)
+	assert.Equal(t, digest[:], implicitDigest)
+	assert.NoError(t, decoded.Verify(ndn.NewSha256Verifier()))
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	keyName, _ := ndn.NameFromString("/go/KEY/abcd")
+	assert.NoError(t, d.Sign(ndn.NewEcdsaSigner(keyName, key)))
+	input, signatureValue = signedPortion(t, d)
+	digest = sha256.Sum256(input)
+	assert.True(t, ecdsa.VerifyASN1(&key.PublicKey, digest[:], signatureValue))
+	verifier, err := ndn.NewEcdsaVerifier(&key.PublicKey)
+	assert.NoError(t, err)
+	assert.NoError(t, d.Verify(verifier))
+}
+
+func TestDataEquals(t *testing.T) {
+	name, _ := ndn.NameFromString("/go/ndn")
+	d := ndn.NewData(name, []byte{0x01, 0x02, 0x03})
+	assert.NoError(t, d.Sign(ndn.NewSha256Signer()))
+	assert.True(t, d.Equals(d))
+	assert.False(t, d.Equals(nil))
+
+	// Decoded round-trip
+	encoded, err := d.Encode()
+	assert.NoError(t, err)
+	decoded, err := ndn.DecodeData(encoded)
+	assert.NoError(t, err)
+	assert.True(t, d.Equals(decoded))
+	assert.True(t, decoded.Equals(d))
+	assert.True(t, d.SameContentAs(decoded))
+
+	// Different signature
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	keyName, _ := ndn.NameFromString("/go/KEY/abcd")
+	resigned := d.DeepCopy()
+	assert.NoError(t, resigned.Sign(ndn.NewEcdsaSigner(keyName, key)))
+	assert.False(t, d.Equals(resigned))
+	assert.True(t, d.SameContentAs(resigned))
+
+	// Different MetaInfo
+	withMetaInfo := d.DeepCopy()
+	contentType := uint64(ndn.ContentTypeKey)
+	metaInfo := ndn.NewMetaInfo()
+	metaInfo.SetContentType(&contentType)
+	withMetaInfo.SetMetaInfo(metaInfo)
+	assert.False(t, d.Equals(withMetaInfo))
+	assert.True(t, d.SameContentAs(withMetaInfo))
+
+	// Different content or name
+	otherContent := d.DeepCopy()
+	otherContent.SetContent([]byte{0x04})
+	assert.False(t, d.Equals(otherContent))
+	assert.False(t, d.SameContentAs(otherContent))
+	otherName, _ := ndn.NameFromString("/go/other")
+	renamed := d.DeepCopy()
+	renamed.SetName(otherName)
+	assert.False(t, d.Equals(renamed))
+	assert.False(t, d.SameContentAs(renamed))
+	assert.False(t, d.SameContentAs(nil))
+
+	// Data that cannot be encoded
+	unsigned := ndn.NewData(name, []byte{0x01, 0x02, 0x03})
+	assert.False(t, unsigned.Equals(d))
+	assert.False(t, d.Equals(unsigned))
+	assert.True(t, unsigned.SameContentAs(d))
+}
@@ -31,6 +31,11 @@ func NewDelegation(preference uint64, name *Name) (*Delegation, error) {
 
 // DecodeDelegation decodes a delegation from the wire.
 func DecodeDelegation(wire *tlv.Block) (*Delegation, error) {
+	return decodeDelegation(wire, tlv.DecodeOptions{})
+}
+
+// decodeDelegation decodes a delegation from the wire, checking the encoding according to the specified options.
+func decodeDelegation(wire *tlv.Block, opts tlv.DecodeOptions) (*Delegation, error) {
 	if wire == nil {
 		return nil, util.ErrNonExistent
 	}
@@ -38,15 +43,18 @@ func DecodeDelegation(wire *tlv.Block) (*Delegation, error) {
 	if wire.Find(tlv.Preference) == nil || wire.Find(tlv.Name) == nil {
 		return nil, util.ErrNonExistent
 	}
+	if err := checkElements(wire, opts); err != nil {
+		return nil, err
+	}
 
 	d := new(Delegation)
 	var err error
-	d.preference, err = tlv.DecodeNNIBlock(wire.Find(tlv.Preference))
+	d.preference, err = opts.DecodeNNIBlock(wire.Find(tlv.Preference))
 	if err != nil {
 		return nil, err
 	}
 
-	name, err := DecodeName(wire.Find(tlv.Name))
+	name, err := decodeName(wire.Find(tlv.Name), InvalidComponentError, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,70 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/eric135/go-ndn2/tlv"
+)
+
+// elementOrder tracks the elements decoded from a TLV structure by their position in its definition. Duplicate elements are always rejected, while out-of-order elements are only rejected when decoding strictly.
+type elementOrder struct {
+	strict     bool
+	mostRecent int
+	seen       uint64
+}
+
+// newElementOrder creates an elementOrder for decoding with the specified options.
+func newElementOrder(opts tlv.DecodeOptions) elementOrder {
+	return elementOrder{strict: opts.Strict}
+}
+
+// check records an element at the specified position (starting from 1), returning an error if it is duplicate or out-of-order.
+func (o *elementOrder) check(position int, elemName string) error {
+	if o.has(position) || (o.strict && position < o.mostRecent) {
+		return errors.New(elemName + " is duplicate or out-of-order")
+	}
+	o.seen |= 1 << uint(position)
+	if position > o.mostRecent {
+		o.mostRecent = position
+	}
+	return nil
+}
+
+// has returns whether an element at the specified position has been recorded.
+func (o *elementOrder) has(position int) bool {
+	return o.seen&(1<<uint(position)) != 0
+}
+
+// checkElements checks the encoding of the TLV-TYPE and TLV-LENGTH of a parsed block and its subelements according to the specified options.
+func checkElements(wire *tlv.Block, opts tlv.DecodeOptions) error {
+	if err := opts.CheckBlock(wire); err != nil {
+		return err
+	}
+	for _, elem := range wire.Subelements() {
+		if err := opts.CheckBlock(elem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compareEncodings returns an error identifying the first octet at which the canonical re-encoding of a packet differs from its original encoding, or nil if they are identical.
+func compareEncodings(original []byte, reencoded []byte) error {
+	for pos := 0; pos < len(original) && pos < len(reencoded); pos++ {
+		if original[pos] != reencoded[pos] {
+			return errors.New("Encoding is not canonical: re-encoding differs at offset " + strconv.Itoa(pos))
+		}
+	}
+	if len(original) != len(reencoded) {
+		return errors.New("Encoding is not canonical: re-encoding is " + strconv.Itoa(len(reencoded)) + " octets instead of " + strconv.Itoa(len(original)))
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn_test
+
+import (
+	"bytes"
+	"testing"
+
+	ndn "github.com/eric135/go-ndn2"
+	"github.com/eric135/go-ndn2/tlv"
+)
+
+// fuzzBlock decodes a block of the specified type from the fuzzer input, or returns nil if the input does not start with one.
+func fuzzBlock(input []byte, tlvType uint32) *tlv.Block {
+	block, _, err := tlv.DecodeBlock(input)
+	if err != nil || block.Type() != tlvType {
+		return nil
+	}
+	return block
+}
+
+// fuzzWire returns the wire encoding of a block, failing the test on error.
+func fuzzWire(t *testing.T, block *tlv.Block) []byte {
+	wire, err := block.Wire()
+	if err != nil {
+		t.Fatalf("Wire: %v", err)
+	}
+	return wire
+}
+
+func FuzzDecodeName(f *testing.F) {
+	f.Add([]byte{tlv.Name, 0x09, tlv.GenericNameComponent, 0x02, 0x67, 0x6f, tlv.SegmentNameComponent, 0x03, 0x00, 0x01, 0x00})
+	f.Add([]byte{tlv.Name, 0x00})
+	f.Add([]byte{tlv.Name, 0x06, tlv.VersionNameComponent, 0x04, 0x00, 0x00, 0x01, 0x00})
+	f.Fuzz(func(t *testing.T, input []byte) {
+		block := fuzzBlock(input, tlv.Name)
+		if block == nil {
+			return
+		}
+		n, err := ndn.DecodeName(block)
+		if err != nil {
+			return
+		}
+
+		// A fresh encoding must decode to an equal name and encode identically
+		wire := fuzzWire(t, n.DeepCopy().Encode())
+		decoded, err := ndn.DecodeName(fuzzBlock(wire, tlv.Name))
+		if err != nil {
+			t.Fatalf("Re-encoded name %s does not decode: %v", n, err)
+		}
+		if !decoded.Equals(n) {
+			t.Fatalf("Re-encoded name %s decodes to %s", n, decoded)
+		}
+		if !bytes.Equal(wire, fuzzWire(t, decoded.DeepCopy().Encode())) {
+			t.Fatalf("Name %s does not re-encode identically", n)
+		}
+	})
+}
+
+func FuzzDecodeInterest(f *testing.F) {
+	f.Add([]byte{tlv.Interest, 0x16,
+		tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f,
+		tlv.CanBePrefix, 0x00, tlv.MustBeFresh, 0x00,
+		tlv.Nonce, 0x04, 0x01, 0x02, 0x03, 0x04,
+		tlv.InterestLifetime, 0x02, 0x03, 0xe8})
+	f.Add([]byte{tlv.Interest, 0x06, tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f})
+	f.Fuzz(func(t *testing.T, input []byte) {
+		block := fuzzBlock(input, tlv.Interest)
+		if block == nil {
+			return
+		}
+		i, err := ndn.DecodeInterest(block)
+		if err != nil {
+			return
+		}
+
+		// Force a fresh encoding rather than reusing the decoded wire
+		i.SetLifetime(i.Lifetime())
+		encoded, err := i.Encode()
+		if err != nil {
+			// Decoded Interests that cannot be encoded (e.g., with an empty name) are not forwarded
+			return
+		}
+		wire := fuzzWire(t, encoded)
+		decoded, err := ndn.DecodeInterest(fuzzBlock(wire, tlv.Interest))
+		if err != nil {
+			t.Fatalf("Re-encoded Interest %s does not decode: %v", i, err)
+		}
+		decoded.SetLifetime(decoded.Lifetime())
+		reencoded, err := decoded.Encode()
+		if err != nil {
+			t.Fatalf("Decoded Interest %s does not encode: %v", decoded, err)
+		}
+		if !bytes.Equal(wire, fuzzWire(t, reencoded)) {
+			t.Fatalf("Interest %s does not re-encode identically", i)
+		}
+	})
+}
+
+func FuzzDecodeData(f *testing.F) {
+	f.Add(append([]byte{tlv.Data, byte(len(dataTestWire()))}, dataTestWire()...))
+	f.Add([]byte{tlv.Data, 0x0b,
+		tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f,
+		tlv.SignatureInfo, 0x03, tlv.SignatureType, 0x01, 0x00})
+	f.Fuzz(func(t *testing.T, input []byte) {
+		block := fuzzBlock(input, tlv.Data)
+		if block == nil {
+			return
+		}
+		d, err := ndn.DecodeData(block)
+		if err != nil {
+			return
+		}
+
+		// Force a fresh encoding rather than reusing the decoded wire
+		d.SetSignatureValue(d.SignatureValue())
+		encoded, err := d.Encode()
+		if err != nil {
+			return
+		}
+		wire := fuzzWire(t, encoded)
+		decoded, err := ndn.DecodeData(fuzzBlock(wire, tlv.Data))
+		if err != nil {
+			t.Fatalf("Re-encoded Data %s does not decode: %v", d, err)
+		}
+		decoded.SetSignatureValue(decoded.SignatureValue())
+		reencoded, err := decoded.Encode()
+		if err != nil {
+			t.Fatalf("Decoded Data %s does not encode: %v", decoded, err)
+		}
+		if !bytes.Equal(wire, fuzzWire(t, reencoded)) {
+			t.Fatalf("Data %s does not re-encode identically", d)
+		}
+	})
+}
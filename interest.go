@@ -12,6 +12,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
+	"io"
 	"math/rand"
 	"strconv"
 	"time"
@@ -20,14 +21,19 @@ import (
 	"github.com/eric135/go-ndn2/util"
 )
 
+// DefaultInterestLifetime is the lifetime of Interests that do not specify an InterestLifetime.
+const DefaultInterestLifetime = 4000 * time.Millisecond
+
 // Interest represents an NDN Interest packet.
 type Interest struct {
 	name           Name
 	canBePrefix    bool
 	mustBeFresh    bool
-	forwardingHint []Delegation
-	nonce          []byte
+	forwardingHint []*Name
+	hintWires      []*tlv.Block // decoded encoding of each hint, or nil if added or modified since
+	nonce          []byte       // nil if absent
 	lifetime       time.Duration
+	hasLifetime    bool // whether InterestLifetime is encoded
 	hopLimit       *uint8
 	parameters     []*tlv.Block
 	wire           *tlv.Block
@@ -37,91 +43,101 @@ type Interest struct {
 func NewInterest(name *Name) *Interest {
 	i := new(Interest)
 	i.name = *name.DeepCopy()
-	i.lifetime = 4000 * time.Millisecond
+	i.lifetime = DefaultInterestLifetime
 	i.ResetNonce()
 	return i
 }
 
-// DecodeInterest decodes an Interest from the wire.
+// DecodeInterest decodes an Interest from the wire, tolerating non-canonical encodings.
 func DecodeInterest(wire *tlv.Block) (*Interest, error) {
+	return DecodeInterestWithOptions(wire, tlv.DecodeOptions{})
+}
+
+// DecodeInterestWithOptions decodes an Interest from the wire, checking the encoding according to the specified options. Interests with both an ImplicitSha256DigestComponent and CanBePrefix, or with ApplicationParameters but no ParametersSha256DigestComponent, are always rejected, while a ParametersSha256DigestComponent without ApplicationParameters is only rejected if strict.
+func DecodeInterestWithOptions(wire *tlv.Block, opts tlv.DecodeOptions) (*Interest, error) {
 	if wire == nil {
 		return nil, util.ErrNonExistent
 	}
 	wire.Parse()
+	if err := checkElements(wire, opts); err != nil {
+		return nil, err
+	}
 
 	i := new(Interest)
-	i.wire = wire.DeepCopy()
-	mostRecentElem := 0
+	i.lifetime = DefaultInterestLifetime
+	order := newElementOrder(opts)
 	hasApplicationParameters := false
 	for _, elem := range wire.Subelements() {
 		switch elem.Type() {
 		case tlv.Name:
-			if mostRecentElem >= 1 {
-				return nil, errors.New("Name is duplicate or out-of-order")
+			if err := order.check(1, "Name"); err != nil {
+				return nil, err
 			}
-			name, err := DecodeName(elem)
+			nameWire, err := elem.Wire()
+			if err != nil {
+				return nil, err
+			}
+			name, _, err := decodeNameFromBuffer(nameWire, opts)
 			if err != nil {
 				return nil, err
 			}
-			mostRecentElem = 1
 			i.SetName(name)
 		case tlv.CanBePrefix:
-			if mostRecentElem >= 2 {
-				return nil, errors.New("CanBePrefix is duplicate or out-of-order")
+			if err := order.check(2, "CanBePrefix"); err != nil {
+				return nil, err
 			}
-			mostRecentElem = 2
 			i.SetCanBePrefix(true)
 		case tlv.MustBeFresh:
-			if mostRecentElem >= 3 {
-				return nil, errors.New("MustBeFresh is duplicate or out-of-order")
+			if err := order.check(3, "MustBeFresh"); err != nil {
+				return nil, err
 			}
-			mostRecentElem = 3
 			i.SetMustBeFresh(true)
 		case tlv.ForwardingHint:
-			if mostRecentElem >= 4 {
-				return nil, errors.New("ForwardingHint is duplicate or out-of-order")
+			if err := order.check(4, "ForwardingHint"); err != nil {
+				return nil, err
+			}
+			if !elem.Parse() {
+				return nil, errors.New("Error decoding ForwardingHint")
+			}
+			if len(elem.Subelements()) == 0 {
+				return nil, errors.New("ForwardingHint is empty")
 			}
-			mostRecentElem = 4
-			elem.Parse()
-			for _, delegationBlock := range elem.Subelements() {
-				delegation, err := DecodeDelegation(delegationBlock)
+			for _, hintBlock := range elem.Subelements() {
+				hint, err := decodeForwardingHintName(hintBlock, opts)
 				if err != nil {
-					return nil, errors.New("Error decoding Delegation")
+					return nil, err
 				}
-				i.forwardingHint = append(i.forwardingHint, *delegation)
+				i.forwardingHint = append(i.forwardingHint, hint)
+				i.hintWires = append(i.hintWires, hintBlock.DeepCopy())
 			}
 		case tlv.Nonce:
-			if mostRecentElem >= 5 {
-				return nil, errors.New("Nonce is duplicate or out-of-order")
+			if err := order.check(5, "Nonce"); err != nil {
+				return nil, err
 			}
-			mostRecentElem = 5
 			if i.SetNonce(elem.Value()) != nil {
 				return nil, errors.New("Error decoding Nonce")
 			}
 		case tlv.InterestLifetime:
-			if mostRecentElem >= 6 {
-				return nil, errors.New("InterestLifetime is duplicate or out-of-order")
+			if err := order.check(6, "InterestLifetime"); err != nil {
+				return nil, err
 			}
-			mostRecentElem = 6
-			lifetime, err := tlv.DecodeNNIBlock(elem)
+			lifetime, err := opts.DecodeNNIBlock(elem)
 			if err != nil {
 				return nil, errors.New("Error decoding InterestLifetime")
 			}
 			i.SetLifetime(time.Duration(lifetime) * time.Millisecond)
 		case tlv.HopLimit:
-			if mostRecentElem >= 7 {
-				return nil, errors.New("HopLimit is duplicate or out-of-order")
+			if err := order.check(7, "HopLimit"); err != nil {
+				return nil, err
 			}
-			mostRecentElem = 7
 			if len(elem.Value()) != 1 {
 				return nil, errors.New("Error decoding HopLimit")
 			}
 			i.SetHopLimit(&elem.Value()[0])
 		case tlv.ApplicationParameters:
-			if mostRecentElem >= 8 {
-				return nil, errors.New("ApplicationParameters is duplicate or out-of-order")
+			if err := order.check(8, "ApplicationParameters"); err != nil {
+				return nil, err
 			}
-			mostRecentElem = 8
 			hasApplicationParameters = true
 			i.parameters = append(i.parameters, elem.DeepCopy())
 		default:
@@ -134,6 +150,24 @@ func DecodeInterest(wire *tlv.Block) (*Interest, error) {
 		}
 	}
 
+	if err := i.name.Validate(); err != nil {
+		return nil, err
+	}
+
+	// An Interest naming a specific Data packet by its implicit digest cannot also match Data under it as a prefix
+	if i.canBePrefix {
+		if index, _ := i.name.Find(tlv.ImplicitSha256DigestComponent); index != -1 {
+			return nil, errors.New("Has both ImplicitSha256DigestComponent and CanBePrefix")
+		}
+	}
+
+	// A ParametersSha256DigestComponent without ApplicationParameters is only tolerated when decoding leniently
+	if !hasApplicationParameters && opts.Strict {
+		if index, _ := i.name.Find(tlv.ParametersSha256DigestComponent); index != -1 {
+			return nil, errors.New("Has ParametersSha256DigestComponent but missing ApplicationParameters")
+		}
+	}
+
 	// If has ApplicationParameters, verify parameters digest component
 	if hasApplicationParameters {
 		_, paramsDigest := i.name.Find(tlv.ParametersSha256DigestComponent)
@@ -157,9 +191,27 @@ func DecodeInterest(wire *tlv.Block) (*Interest, error) {
 		}
 	}
 
+	// Set after decoding the fields, since the setters clear the wire
+	i.wire = wire.DeepCopy()
 	return i, nil
 }
 
+// decodeForwardingHintName decodes a name in a ForwardingHint. As a fallback for Interests encoded before NDN packet format v0.3, a Delegation is also accepted and its name returned, while its original encoding is kept by the Interest so that forwarders pass it on unchanged.
+func decodeForwardingHintName(wire *tlv.Block, opts tlv.DecodeOptions) (*Name, error) {
+	switch wire.Type() {
+	case tlv.Name:
+		return decodeName(wire, InvalidComponentError, opts)
+	case tlv.Delegation:
+		delegation, err := decodeDelegation(wire, opts)
+		if err != nil {
+			return nil, errors.New("Error decoding Delegation")
+		}
+		return delegation.Name(), nil
+	default:
+		return nil, errors.New("ForwardingHint contains unexpected element of type 0x" + strconv.FormatUint(uint64(wire.Type()), 16))
+	}
+}
+
 func (i *Interest) String() string {
 	str := "Interest(Name=" + i.name.String()
 
@@ -171,17 +223,17 @@ func (i *Interest) String() string {
 	}
 	if len(i.forwardingHint) > 0 {
 		str += ", ForwardingHint("
-		isFirstDelegation := true
-		for _, delegation := range i.forwardingHint {
-			if !isFirstDelegation {
+		for j, hint := range i.forwardingHint {
+			if j > 0 {
 				str += ", "
 			}
-			str += delegation.String()
-			isFirstDelegation = false
+			str += hint.String()
 		}
 		str += ")"
 	}
-	str += ", Nonce=0x" + hex.EncodeToString(i.nonce)
+	if i.nonce != nil {
+		str += ", Nonce=0x" + hex.EncodeToString(i.nonce)
+	}
 	str += ", Lifetime=" + strconv.FormatInt(i.lifetime.Milliseconds(), 10) + "ms"
 	if i.hopLimit != nil {
 		str += ", HopLimit=" + strconv.FormatUint(uint64(*i.hopLimit), 10)
@@ -194,6 +246,56 @@ func (i *Interest) String() string {
 	return str
 }
 
+// Clone returns a deep copy of the Interest, including its nonce. The name, ForwardingHint, and application parameters are copied, so the clone can be modified independently of the original.
+func (i *Interest) Clone() *Interest {
+	copyI := new(Interest)
+	copyI.name = *i.name.DeepCopy()
+	copyI.canBePrefix = i.canBePrefix
+	copyI.mustBeFresh = i.mustBeFresh
+	if i.forwardingHint != nil {
+		copyI.forwardingHint = make([]*Name, 0, len(i.forwardingHint))
+		for _, hint := range i.forwardingHint {
+			copyI.forwardingHint = append(copyI.forwardingHint, hint.DeepCopy())
+		}
+	}
+	if i.hintWires != nil {
+		copyI.hintWires = make([]*tlv.Block, 0, len(i.hintWires))
+		for _, hintWire := range i.hintWires {
+			if hintWire != nil {
+				hintWire = hintWire.DeepCopy()
+			}
+			copyI.hintWires = append(copyI.hintWires, hintWire)
+		}
+	}
+	if i.nonce != nil {
+		copyI.nonce = make([]byte, len(i.nonce))
+		copy(copyI.nonce, i.nonce)
+	}
+	copyI.lifetime = i.lifetime
+	copyI.hasLifetime = i.hasLifetime
+	if i.hopLimit != nil {
+		copyI.hopLimit = new(uint8)
+		*copyI.hopLimit = *i.hopLimit
+	}
+	if i.parameters != nil {
+		copyI.parameters = make([]*tlv.Block, 0, len(i.parameters))
+		for _, param := range i.parameters {
+			copyI.parameters = append(copyI.parameters, param.DeepCopy())
+		}
+	}
+	if i.wire != nil {
+		copyI.wire = i.wire.DeepCopy()
+	}
+	return copyI
+}
+
+// CloneWithNewNonce returns a deep copy of the Interest like Clone, but with a newly generated nonce, as when retransmitting the Interest.
+func (i *Interest) CloneWithNewNonce() *Interest {
+	copyI := i.Clone()
+	copyI.ResetNonce()
+	return copyI
+}
+
 //////////////////
 // Setters/Getters
 //////////////////
@@ -209,12 +311,12 @@ func (i *Interest) SetName(name *Name) {
 	i.wire = nil
 }
 
-// CanBePrefix returns whether the Interest can be satisfied by a Data packet whos name the Interest name is a prefix of.
+// CanBePrefix returns whether the Interest can be satisfied by a Data packet whos name the Interest name is a prefix of. This defaults to false, meaning that the Data name must match the Interest name exactly, and is false for Interests decoded without a CanBePrefix element.
 func (i *Interest) CanBePrefix() bool {
 	return i.canBePrefix
 }
 
-// SetCanBePrefix sets whether the Interest can be satisfied by a Data packet whos name the Interest name is a prefix of.
+// SetCanBePrefix sets whether the Interest can be satisfied by a Data packet whos name the Interest name is a prefix of. The CanBePrefix element is only encoded if this is true.
 func (i *Interest) SetCanBePrefix(canBePrefix bool) {
 	i.canBePrefix = canBePrefix
 	i.wire = nil
@@ -231,28 +333,47 @@ func (i *Interest) SetMustBeFresh(mustBeFresh bool) {
 	i.wire = nil
 }
 
-// ForwardingHint returns a copy of the delegations in the ForwardingHint in the Interest.
-func (i *Interest) ForwardingHint() []Delegation {
-	if i.forwardingHint == nil {
-		return make([]Delegation, 0)
+// ForwardingHint returns copies of the names in the ForwardingHint in the Interest, in order of preference.
+func (i *Interest) ForwardingHint() []*Name {
+	fh := make([]*Name, 0, len(i.forwardingHint))
+	for _, hint := range i.forwardingHint {
+		fh = append(fh, hint.DeepCopy())
 	}
+	return fh
+}
 
-	fh := make([]Delegation, 0, len(i.forwardingHint))
-	for _, delegation := range i.forwardingHint {
-		fh = append(fh, delegation)
+// SetForwardingHint replaces the ForwardingHint in the Interest with copies of the specified names, in order of preference (or removes it if no names are specified).
+func (i *Interest) SetForwardingHint(hints []*Name) error {
+	fh := make([]*Name, 0, len(hints))
+	for _, hint := range hints {
+		if hint == nil {
+			return util.ErrNonExistent
+		}
+		fh = append(fh, hint.DeepCopy())
 	}
-	return fh
+	i.forwardingHint = fh
+	i.hintWires = nil
+	i.wire = nil
+	return nil
 }
 
-// AppendForwardingHint appends a delegation to the ForwardingHint in the Interest.
-func (i *Interest) AppendForwardingHint(delegation *Delegation) {
-	i.forwardingHint = append(i.forwardingHint, *delegation)
+// AppendForwardingHint appends a name to the end of the ForwardingHint in the Interest, leaving the encoding of existing hints unchanged.
+func (i *Interest) AppendForwardingHint(hint *Name) error {
+	if hint == nil {
+		return util.ErrNonExistent
+	}
+	i.forwardingHint = append(i.forwardingHint, hint.DeepCopy())
+	if i.hintWires != nil {
+		i.hintWires = append(i.hintWires, nil)
+	}
 	i.wire = nil
+	return nil
 }
 
 // ClearForwardingHints removes all forwarding hints attached to the Interest.
 func (i *Interest) ClearForwardingHints() {
-	i.forwardingHint = []Delegation{}
+	i.forwardingHint = nil
+	i.hintWires = nil
 	i.wire = nil
 }
 
@@ -263,17 +384,34 @@ func (i *Interest) EraseForwardingHint(index int) error {
 	}
 
 	i.forwardingHint = append(i.forwardingHint[:index], i.forwardingHint[index+1:]...)
+	if i.hintWires != nil {
+		i.hintWires = append(i.hintWires[:index], i.hintWires[index+1:]...)
+	}
 	i.wire = nil
 	return nil
 }
 
-// Nonce gets the nonce of the Interest.
+// Nonce gets the nonce of the Interest, or nil if the Interest has no nonce.
 func (i *Interest) Nonce() []byte {
+	if i.nonce == nil {
+		return nil
+	}
 	nonce := make([]byte, 4)
 	copy(nonce, i.nonce)
 	return nonce
 }
 
+// HasNonce returns whether the Interest has a nonce. An Interest decoded from the wire may lack a nonce, in which case one must be assigned with ResetNonce before it is forwarded.
+func (i *Interest) HasNonce() bool {
+	return i.nonce != nil
+}
+
+// UnsetNonce removes the nonce from the Interest.
+func (i *Interest) UnsetNonce() {
+	i.nonce = nil
+	i.wire = nil
+}
+
 // ResetNonce regenerates the value of the nonce.
 func (i *Interest) ResetNonce() {
 	i.nonce = make([]byte, 4)
@@ -300,9 +438,10 @@ func (i *Interest) Lifetime() time.Duration {
 	return i.lifetime
 }
 
-// SetLifetime set the lifetime of the Interest.
+// SetLifetime set the lifetime of the Interest. Once set, the InterestLifetime is encoded even if it is equal to DefaultInterestLifetime.
 func (i *Interest) SetLifetime(lifetime time.Duration) {
 	i.lifetime = lifetime
+	i.hasLifetime = true
 	i.wire = nil
 }
 
@@ -328,6 +467,21 @@ func (i *Interest) SetHopLimit(hopLimit *uint8) {
 	i.wire = nil
 }
 
+// DecrementHopLimit decrements the hop limit of the Interest, as a forwarder does upon receiving it. It returns false, leaving the Interest unchanged, if no hop limit is set or if the hop limit is already zero (in which case the Interest should be dropped). After the hop limit reaches zero, the Interest may only be forwarded to local faces, such as a local producer; use CanForwardToNonLocal to check this.
+func (i *Interest) DecrementHopLimit() bool {
+	if i.hopLimit == nil || *i.hopLimit == 0 {
+		return false
+	}
+	*i.hopLimit--
+	i.wire = nil
+	return true
+}
+
+// CanForwardToNonLocal returns whether the hop limit of the Interest permits forwarding it to another forwarder. This is true if no hop limit is set or the hop limit is greater than zero.
+func (i *Interest) CanForwardToNonLocal() bool {
+	return i.hopLimit == nil || *i.hopLimit > 0
+}
+
 // ApplicationParameters returns a copy of the application parameters of the Interest.
 func (i *Interest) ApplicationParameters() []tlv.Block {
 	params := make([]tlv.Block, 0, len(i.parameters))
@@ -337,6 +491,48 @@ func (i *Interest) ApplicationParameters() []tlv.Block {
 	return params
 }
 
+// Parameters returns copies of the parameters portion of the Interest, consisting of the ApplicationParameters element followed by any trailing parameter elements, or an empty slice if the Interest has no ApplicationParameters. Each block whose value is a sequence of TLV elements is parsed, so that structured arguments can be read from its subelements, while its value is kept so that opaque parameters are returned intact.
+func (i *Interest) Parameters() []*tlv.Block {
+	params := make([]*tlv.Block, 0, len(i.parameters))
+	for _, param := range i.parameters {
+		copyParam := param.DeepCopy()
+		if parsed := param.DeepCopy(); parsed.Parse() {
+			// Parse empties the value, so restore it alongside the subelements decoded from it
+			parsed.SetValue(param.Value())
+			copyParam = parsed
+		}
+		params = append(params, copyParam)
+	}
+	return params
+}
+
+// SetParameters replaces the parameters portion of the Interest with copies of the specified blocks, the first of which must be an ApplicationParameters element, and recomputes the ParametersSha256DigestComponent over all of them. If no blocks are specified, the parameters and the digest component are removed. If an error is returned, the Interest is left unchanged.
+func (i *Interest) SetParameters(params []*tlv.Block) error {
+	if len(params) > 0 && (params[0] == nil || params[0].Type() != tlv.ApplicationParameters) {
+		return errors.New("First parameter must be ApplicationParameters")
+	}
+	newParams := make([]*tlv.Block, 0, len(params))
+	for _, param := range params {
+		if param == nil {
+			return util.ErrNonExistent
+		}
+		copyParam := param.DeepCopy()
+		if _, err := copyParam.Wire(); err != nil {
+			return err
+		}
+		newParams = append(newParams, copyParam)
+	}
+
+	i.parameters = newParams
+	if len(i.parameters) > 0 {
+		i.recomputeParametersDigestComponent()
+	} else if digestIndex, _ := i.name.Find(tlv.ParametersSha256DigestComponent); digestIndex != -1 {
+		i.name.Erase(digestIndex)
+	}
+	i.wire = nil
+	return nil
+}
+
 // AppendApplicationParameter appends an application parameter to the Interest. If not already present (or the type of the parameter block specified), it adds an empty ApplicationParameters block before appending this block.
 func (i *Interest) AppendApplicationParameter(block *tlv.Block) {
 	if block.Type() != tlv.ApplicationParameters && len(i.parameters) == 0 {
@@ -365,27 +561,48 @@ func (i *Interest) recomputeParametersDigestComponent() {
 		// Replace existing component
 		i.name.Set(digestIndex, NewParametersSha256DigestComponent(generatedHash))
 	} else {
-		// Place according to ordering in spec (after last GenericNameComponent)
-		lastGenericComponent := -1
-		for ; lastGenericComponent < i.name.Size(); lastGenericComponent++ {
-			if i.name.At(lastGenericComponent+1).Type() != tlv.GenericNameComponent {
-				break
-			}
-		}
+		// Append as the final component, since only signed Interest components may follow it
+		i.name.Append(NewParametersSha256DigestComponent(generatedHash))
+	}
 
-		if lastGenericComponent == -1 {
-			// Insert at position 0
-			i.name.Insert(0, NewParametersSha256DigestComponent(generatedHash))
-		} else if lastGenericComponent == i.name.Size()-1 {
-			// Append
-			i.name.Append(NewParametersSha256DigestComponent(generatedHash))
-		} else {
-			// Insert after last GenericNameComponent
-			i.name.Insert(lastGenericComponent+1, NewParametersSha256DigestComponent(generatedHash))
-		}
+	i.wire = nil
+}
+
+// AppendToName appends a component to the name of the Interest. If the name contains a ParametersSha256DigestComponent, the component is inserted immediately before it instead, so that the digest remains the final component. A ParametersSha256DigestComponent cannot be appended, as it is managed by the Interest.
+func (i *Interest) AppendToName(component NameComponent) error {
+	if component == nil {
+		return util.ErrNonExistent
+	}
+	if component.Type() == tlv.ParametersSha256DigestComponent {
+		return errors.New("ParametersSha256DigestComponent is computed from the ApplicationParameters and cannot be appended")
+	}
+	if err := i.name.checkLimits(component); err != nil {
+		return err
 	}
 
+	digestIndex, _ := i.name.Find(tlv.ParametersSha256DigestComponent)
+	if digestIndex != -1 {
+		i.name.Insert(digestIndex, component)
+	} else {
+		i.name.Append(component)
+	}
 	i.wire = nil
+	return nil
+}
+
+// Finalize prepares the name of the Interest for encoding. If the Interest has ApplicationParameters, the ParametersSha256DigestComponent is recomputed over them, remaining in its existing position or, if absent, being appended as the final component. Otherwise, any ParametersSha256DigestComponent is removed. An error is returned if the name contains more than one ParametersSha256DigestComponent or its digest components are positioned illegally. Encode calls Finalize automatically.
+func (i *Interest) Finalize() error {
+	if err := i.name.Validate(); err != nil {
+		return err
+	}
+
+	if len(i.parameters) > 0 {
+		i.recomputeParametersDigestComponent()
+	} else if digestIndex, _ := i.name.Find(tlv.ParametersSha256DigestComponent); digestIndex != -1 {
+		i.name.Erase(digestIndex)
+		i.wire = nil
+	}
+	return nil
 }
 
 // ClearApplicationParameters clears all ApplicationParameters from the Interest.
@@ -394,27 +611,127 @@ func (i *Interest) ClearApplicationParameters() {
 	i.wire = nil
 }
 
+// SameAs returns whether the specified Interest has the same Name, CanBePrefix, MustBeFresh, ForwardingHint, and ApplicationParameters as this Interest, ignoring the volatile Nonce, InterestLifetime, and HopLimit fields.
+func (i *Interest) SameAs(other *Interest) bool {
+	if other == nil {
+		return false
+	}
+	if !i.name.Equals(&other.name) || i.canBePrefix != other.canBePrefix || i.mustBeFresh != other.mustBeFresh {
+		return false
+	}
+
+	if len(i.forwardingHint) != len(other.forwardingHint) {
+		return false
+	}
+	for j := range i.forwardingHint {
+		if !i.forwardingHint[j].Equals(other.forwardingHint[j]) {
+			return false
+		}
+	}
+
+	if len(i.parameters) != len(other.parameters) {
+		return false
+	}
+	for j := range i.parameters {
+		wire, err := i.parameters[j].Wire()
+		if err != nil {
+			return false
+		}
+		otherWire, err := other.parameters[j].Wire()
+		if err != nil || !bytes.Equal(wire, otherWire) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MatchesData returns whether the specified Data satisfies the Interest.
+func (i *Interest) MatchesData(d *Data) bool {
+	if d == nil {
+		return false
+	}
+
+	// If the Interest name ends in an implicit digest, match against the full name of the Data
+	dataName := &d.name
+	if last := i.name.At(i.name.Size() - 1); last != nil && last.Type() == tlv.ImplicitSha256DigestComponent {
+		fullName, err := d.FullName()
+		if err != nil {
+			return false
+		}
+		dataName = fullName
+	}
+
+	if i.canBePrefix {
+		if !i.name.PrefixOf(dataName) {
+			return false
+		}
+	} else if !i.name.Equals(dataName) {
+		return false
+	}
+
+	if i.mustBeFresh {
+		if d.metaInfo == nil || d.metaInfo.freshnessPeriod == nil || *d.metaInfo.freshnessPeriod <= 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
 ///////////
 // Encoding
 ///////////
 
 // Encode encodes the data into a block.
 func (i *Interest) Encode() (*tlv.Block, error) {
-	if i.wire != nil {
-		return i.wire.DeepCopy(), nil
+	wire, err := i.encodeWire()
+	if err != nil {
+		return nil, err
 	}
+	return wire.DeepCopy(), nil
+}
 
-	i.wire = new(tlv.Block)
-	i.wire.SetType(tlv.Interest)
+// EncodeWithMaxSize encodes the Interest into a block like Encode, but returns util.ErrTooLong if the encoded Interest is larger than the specified maximum size, such as MaxPacketSize.
+func (i *Interest) EncodeWithMaxSize(maxSize int) (*tlv.Block, error) {
+	wire, err := i.encodeWire()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPacketSize(wire, maxSize); err != nil {
+		return nil, err
+	}
+	return wire.DeepCopy(), nil
+}
+
+// WriteTo writes the wire encoding of the Interest to the specified writer. The cached wire is used if present, avoiding any allocation; otherwise, the Interest is encoded and the wire cached.
+func (i *Interest) WriteTo(w io.Writer) (int64, error) {
+	wire, err := i.encodeWire()
+	if err != nil {
+		return 0, err
+	}
+	return wire.WriteTo(w)
+}
+
+// encodeWire returns the cached wire of the Interest, encoding it first if necessary. The returned block must not be modified.
+func (i *Interest) encodeWire() (*tlv.Block, error) {
+	if i.wire != nil {
+		if _, err := i.wire.Wire(); err != nil {
+			return nil, err
+		}
+		return i.wire, nil
+	}
 
 	// Validate fields
+	if err := i.Finalize(); err != nil {
+		return nil, err
+	}
 	if i.name.Size() == 0 {
 		return nil, errors.New("Name cannot be empty")
 	}
 
-	if len(i.nonce) != 4 {
-		return nil, errors.New("Nonce must be set to encode")
-	}
+	i.wire = new(tlv.Block)
+	i.wire.SetType(tlv.Interest)
 
 	// Name
 	i.wire.Append(i.name.Encode())
@@ -429,20 +746,28 @@ func (i *Interest) Encode() (*tlv.Block, error) {
 		i.wire.Append(tlv.NewEmptyBlock(tlv.MustBeFresh))
 	}
 
-	// ForwardingHint
+	// ForwardingHint (decoded hints keep their original encoding)
 	if len(i.forwardingHint) > 0 {
 		fhBlock := tlv.NewEmptyBlock(tlv.ForwardingHint)
-		for _, delegation := range i.forwardingHint {
-			fhBlock.Append(delegation.Encode())
+		for j, hint := range i.forwardingHint {
+			if j < len(i.hintWires) && i.hintWires[j] != nil {
+				fhBlock.Append(i.hintWires[j].DeepCopy())
+			} else {
+				fhBlock.Append(hint.Encode())
+			}
 		}
 		i.wire.Append(fhBlock)
 	}
 
 	// Nonce
-	i.wire.Append(tlv.NewBlock(tlv.Nonce, i.nonce))
+	if i.nonce != nil {
+		i.wire.Append(tlv.NewBlock(tlv.Nonce, i.nonce))
+	}
 
-	// InterestLifetime
-	i.wire.Append(tlv.EncodeNNIBlock(tlv.InterestLifetime, uint64(i.lifetime.Milliseconds())))
+	// InterestLifetime (omitted if neither set nor decoded)
+	if i.hasLifetime {
+		i.wire.Append(tlv.EncodeNNIBlock(tlv.InterestLifetime, uint64(i.lifetime.Milliseconds())))
+	}
 
 	// HopLimit
 	if i.hopLimit != nil {
@@ -455,7 +780,35 @@ func (i *Interest) Encode() (*tlv.Block, error) {
 	}
 
 	i.wire.Wire()
-	return i.wire.DeepCopy(), nil
+	return i.wire, nil
+}
+
+// VerifyCanonicalEncoding checks that the wire encoding of the Interest is canonical, as Data.VerifyCanonicalEncoding does for Data packets.
+func (i *Interest) VerifyCanonicalEncoding() error {
+	wire, err := i.encodeWire()
+	if err != nil {
+		return err
+	}
+	original, err := wire.Wire()
+	if err != nil {
+		return err
+	}
+
+	decoded, err := DecodeInterestWithOptions(wire.DeepCopy(), tlv.DecodeOptions{Strict: true})
+	if err != nil {
+		return err
+	}
+	decoded = decoded.Clone()
+	decoded.wire = nil
+	reencoded, err := decoded.encodeWire()
+	if err != nil {
+		return err
+	}
+	reencodedWire, err := reencoded.Wire()
+	if err != nil {
+		return err
+	}
+	return compareEncodings(original, reencodedWire)
 }
 
 // HasWire returns whether a wire encoding exists for the Interest.
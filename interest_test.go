@@ -8,12 +8,16 @@
 package ndn_test
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/hex"
+	"io/ioutil"
 	"testing"
 	"time"
 
 	ndn "github.com/eric135/go-ndn2"
 	"github.com/eric135/go-ndn2/tlv"
+	"github.com/eric135/go-ndn2/util"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -38,7 +42,7 @@ func TestInterestDecode(t *testing.T) {
 			tlv.Name, 0x2B, tlv.GenericNameComponent, 0x02, 0x67, 0x6f, tlv.GenericNameComponent, 0x03, 0x6e, 0x64, 0x6e, tlv.ParametersSha256DigestComponent, 0x20, 0x09, 0x01, 0xA2, 0xD0, 0x4B, 0xB8, 0x8A, 0xB8, 0x19, 0x13, 0xC2, 0x32, 0xA3, 0xEF, 0xC8, 0x9F, 0xAC, 0xF8, 0xB3, 0x2D, 0xF2, 0x0E, 0x3D, 0x43, 0x53, 0x89, 0xF5, 0x50, 0x27, 0x25, 0xC0, 0x4F,
 			tlv.CanBePrefix, 0x00,
 			tlv.MustBeFresh, 0x00,
-			tlv.ForwardingHint, 0x14, tlv.Delegation, 0x12, tlv.Preference, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0A, tlv.Name, 0x06, tlv.GenericNameComponent, 0x04, 0x75, 0x63, 0x6c, 0x61,
+			tlv.ForwardingHint, 0x08, tlv.Name, 0x06, tlv.GenericNameComponent, 0x04, 0x75, 0x63, 0x6c, 0x61,
 			tlv.Nonce, 0x04, 0x01, 0x02, 0x03, 0x04,
 			tlv.InterestLifetime, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0xe8,
 			tlv.HopLimit, 0x01, 0x40,
@@ -53,13 +57,12 @@ func TestInterestDecode(t *testing.T) {
 	assert.Equal(t, true, i.CanBePrefix())
 	assert.Equal(t, true, i.MustBeFresh())
 	assert.Equal(t, 1, len(i.ForwardingHint()))
-	assert.Equal(t, uint64(0x0a), i.ForwardingHint()[0].Preference())
-	assert.Equal(t, "/ucla", i.ForwardingHint()[0].Name().String())
+	assert.Equal(t, "/ucla", i.ForwardingHint()[0].String())
 	assert.Equal(t, []byte{0x01, 0x02, 0x03, 0x04}, i.Nonce())
 	assert.Equal(t, 1000*time.Millisecond, i.Lifetime())
 	assert.Equal(t, uint8(0x40), *i.HopLimit())
 	assert.Equal(t, 3, len(i.ApplicationParameters()))
-	assert.Equal(t, "Interest(Name=/go/ndn/params-sha256=0901a2d04bb88ab81913c232a3efc89facf8b32df20e3d435389f5502725c04f, CanBePrefix, MustBeFresh, ForwardingHint(Delegation(10, /ucla)), Nonce=0x01020304, Lifetime=1000ms, HopLimit=64, ApplicationParameters)", i.String())
+	assert.Equal(t, "Interest(Name=/go/ndn/params-sha256=0901a2d04bb88ab81913c232a3efc89facf8b32df20e3d435389f5502725c04f, CanBePrefix, MustBeFresh, ForwardingHint(/ucla), Nonce=0x01020304, Lifetime=1000ms, HopLimit=64, ApplicationParameters)", i.String())
 }
 
 func TestInterestEncode(t *testing.T) {
@@ -68,9 +71,9 @@ func TestInterestEncode(t *testing.T) {
 			tlv.Name, 0x2B, tlv.GenericNameComponent, 0x02, 0x67, 0x6f, tlv.GenericNameComponent, 0x03, 0x6e, 0x64, 0x6e, tlv.ParametersSha256DigestComponent, 0x20, 0x09, 0x01, 0xA2, 0xD0, 0x4B, 0xB8, 0x8A, 0xB8, 0x19, 0x13, 0xC2, 0x32, 0xA3, 0xEF, 0xC8, 0x9F, 0xAC, 0xF8, 0xB3, 0x2D, 0xF2, 0x0E, 0x3D, 0x43, 0x53, 0x89, 0xF5, 0x50, 0x27, 0x25, 0xC0, 0x4F,
 			tlv.CanBePrefix, 0x00,
 			tlv.MustBeFresh, 0x00,
-			tlv.ForwardingHint, 0x14, tlv.Delegation, 0x12, tlv.Preference, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0A, tlv.Name, 0x06, tlv.GenericNameComponent, 0x04, 0x75, 0x63, 0x6c, 0x61,
+			tlv.ForwardingHint, 0x08, tlv.Name, 0x06, tlv.GenericNameComponent, 0x04, 0x75, 0x63, 0x6c, 0x61,
 			tlv.Nonce, 0x04, 0x01, 0x02, 0x03, 0x04,
-			tlv.InterestLifetime, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0xe8,
+			tlv.InterestLifetime, 0x02, 0x03, 0xe8,
 			tlv.HopLimit, 0x01, 0x40,
 			tlv.ApplicationParameters, 0x00,
 			0xAA, 0x04, 0xBB, 0xCC, 0xDD, 0xEE,
@@ -103,35 +106,102 @@ func TestForwardingHint(t *testing.T) {
 	name1, err := ndn.NameFromString("/ucla")
 	assert.NotNil(t, name1)
 	assert.NoError(t, err)
-	d1, err := ndn.NewDelegation(10, name1)
-	assert.NotNil(t, d1)
-	assert.NoError(t, err)
-	assert.Equal(t, 0, len(i.ForwardingHint()))
-	i.AppendForwardingHint(d1)
+	assert.NoError(t, i.AppendForwardingHint(name1))
 	assert.Equal(t, 1, len(i.ForwardingHint()))
-	assert.Equal(t, uint64(10), i.ForwardingHint()[0].Preference())
-	assert.Equal(t, "/ucla", i.ForwardingHint()[0].Name().String())
+	assert.Equal(t, "/ucla", i.ForwardingHint()[0].String())
 
 	name2, err := ndn.NameFromString("/arizona")
 	assert.NotNil(t, name2)
 	assert.NoError(t, err)
-	d2, err := ndn.NewDelegation(20, name2)
-	assert.NotNil(t, d2)
-	assert.NoError(t, err)
-	i.AppendForwardingHint(d2)
+	assert.NoError(t, i.AppendForwardingHint(name2))
 	assert.Equal(t, 2, len(i.ForwardingHint()))
-	assert.Equal(t, uint64(10), i.ForwardingHint()[0].Preference())
-	assert.Equal(t, "/ucla", i.ForwardingHint()[0].Name().String())
-	assert.Equal(t, uint64(20), i.ForwardingHint()[1].Preference())
-	assert.Equal(t, "/arizona", i.ForwardingHint()[1].Name().String())
+	assert.Equal(t, "/ucla", i.ForwardingHint()[0].String())
+	assert.Equal(t, "/arizona", i.ForwardingHint()[1].String())
+	assert.Error(t, i.AppendForwardingHint(nil))
+
+	// Returned names are copies
+	i.ForwardingHint()[0].Append(ndn.NewGenericNameComponent([]byte("x")))
+	assert.Equal(t, "/ucla", i.ForwardingHint()[0].String())
 
 	assert.NoError(t, i.EraseForwardingHint(1))
 	assert.Equal(t, 1, len(i.ForwardingHint()))
-	assert.Equal(t, uint64(10), i.ForwardingHint()[0].Preference())
-	assert.Equal(t, "/ucla", i.ForwardingHint()[0].Name().String())
+	assert.Equal(t, "/ucla", i.ForwardingHint()[0].String())
 
 	i.ClearForwardingHints()
 	assert.Equal(t, 0, len(i.ForwardingHint()))
+
+	assert.NoError(t, i.SetForwardingHint([]*ndn.Name{name2, name1}))
+	assert.Equal(t, 2, len(i.ForwardingHint()))
+	assert.Equal(t, "/arizona", i.ForwardingHint()[0].String())
+	assert.Equal(t, "/ucla", i.ForwardingHint()[1].String())
+	assert.Error(t, i.SetForwardingHint([]*ndn.Name{name1, nil}))
+	assert.Equal(t, 2, len(i.ForwardingHint()))
+}
+
+func TestForwardingHintDecode(t *testing.T) {
+	// ForwardingHint containing bare Names
+	forwardingHint := []byte{tlv.ForwardingHint, 0x12, tlv.Name, 0x06, tlv.GenericNameComponent, 0x04, 0x75, 0x63, 0x6c, 0x61, tlv.Name, 0x08, tlv.GenericNameComponent, 0x06, 0x72, 0x65, 0x6d, 0x61, 0x70, 0x31}
+	value := append([]byte{tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f}, forwardingHint...)
+	value = append(value, tlv.Nonce, 0x04, 0x01, 0x02, 0x03, 0x04)
+	i, err := ndn.DecodeInterest(tlv.NewBlock(tlv.Interest, value))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(i.ForwardingHint()))
+	assert.Equal(t, "/ucla", i.ForwardingHint()[0].String())
+	assert.Equal(t, "/remap1", i.ForwardingHint()[1].String())
+
+	i.SetLifetime(2000 * time.Millisecond)
+	encoded, err := i.Encode()
+	assert.NoError(t, err)
+	wire, err := encoded.Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, forwardingHint, wire[8:8+len(forwardingHint)])
+
+	// Non-minimal Preference encoding must be preserved when the Interest is re-encoded
+	forwardingHint = []byte{tlv.ForwardingHint, 0x14, tlv.Delegation, 0x12, tlv.Preference, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0A, tlv.Name, 0x06, tlv.GenericNameComponent, 0x04, 0x75, 0x63, 0x6c, 0x61}
+	value = append([]byte{tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f}, forwardingHint...)
+	value = append(value, tlv.Nonce, 0x04, 0x01, 0x02, 0x03, 0x04)
+	i, err = ndn.DecodeInterest(tlv.NewBlock(tlv.Interest, value))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(i.ForwardingHint()))
+	assert.Equal(t, "/ucla", i.ForwardingHint()[0].String())
+	i.SetLifetime(2000 * time.Millisecond)
+	encoded, err = i.Encode()
+	assert.NoError(t, err)
+	wire, err = encoded.Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, forwardingHint, wire[8:8+len(forwardingHint)])
+	assert.Equal(t, forwardingHint, interestWire(t, i.Clone())[8:8+len(forwardingHint)])
+
+	// Appending a hint leaves the encoding of the decoded hints unchanged
+	arizona, _ := ndn.NameFromString("/arizona")
+	assert.NoError(t, i.AppendForwardingHint(arizona))
+	wire = interestWire(t, i)
+	assert.Equal(t, append([]byte{tlv.ForwardingHint, 0x1f}, forwardingHint[2:]...), wire[8:8+len(forwardingHint)])
+	assert.Equal(t, []byte{tlv.Name, 0x09, tlv.GenericNameComponent, 0x07, 0x61, 0x72, 0x69, 0x7a, 0x6f, 0x6e, 0x61}, wire[8+len(forwardingHint):8+len(forwardingHint)+11])
+
+	// Replacing the hints re-encodes them as bare Names
+	assert.NoError(t, i.SetForwardingHint(i.ForwardingHint()[:1]))
+	assert.Equal(t, []byte{tlv.ForwardingHint, 0x08, tlv.Name, 0x06, tlv.GenericNameComponent, 0x04, 0x75, 0x63, 0x6c, 0x61}, interestWire(t, i)[8:18])
+
+	// Non-minimal Preference is rejected if strict
+	i, err = ndn.DecodeInterestWithOptions(tlv.NewBlock(tlv.Interest, value), tlv.DecodeOptions{Strict: true})
+	assert.Nil(t, i)
+	assert.Error(t, err)
+
+	// Empty ForwardingHint
+	i, err = ndn.DecodeInterest(tlv.NewBlock(tlv.Interest, []byte{tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f, tlv.ForwardingHint, 0x00, tlv.Nonce, 0x04, 0x01, 0x02, 0x03, 0x04}))
+	assert.Nil(t, i)
+	assert.Error(t, err)
+
+	// Malformed Delegation
+	i, err = ndn.DecodeInterest(tlv.NewBlock(tlv.Interest, []byte{tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f, tlv.ForwardingHint, 0x02, tlv.Delegation, 0x00, tlv.Nonce, 0x04, 0x01, 0x02, 0x03, 0x04}))
+	assert.Nil(t, i)
+	assert.Error(t, err)
+
+	// Unexpected element in ForwardingHint
+	i, err = ndn.DecodeInterest(tlv.NewBlock(tlv.Interest, []byte{tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f, tlv.ForwardingHint, 0x03, tlv.Nonce, 0x01, 0x00, tlv.Nonce, 0x04, 0x01, 0x02, 0x03, 0x04}))
+	assert.Nil(t, i)
+	assert.Error(t, err)
 }
 
 func TestApplicationParameters(t *testing.T) {
@@ -158,3 +228,576 @@ func TestApplicationParameters(t *testing.T) {
 	assert.Equal(t, uint32(tlv.ApplicationParameters), i.ApplicationParameters()[0].Type())
 	assert.Equal(t, uint32(0xAA), i.ApplicationParameters()[1].Type())
 }
+
+func TestInterestParameters(t *testing.T) {
+	name, _ := ndn.NameFromString("/go/ndn")
+	i := ndn.NewInterest(name)
+	assert.Equal(t, 0, len(i.Parameters()))
+
+	appParams := tlv.NewEmptyBlock(tlv.ApplicationParameters)
+	appParams.Append(tlv.NewBlock(0xC0, []byte{0x01, 0x02}))
+	trailing := tlv.NewBlock(0xC2, []byte{0x03})
+	assert.NoError(t, i.SetParameters([]*tlv.Block{appParams, trailing}))
+
+	// Digest covers all parameter elements as a contiguous region
+	appParamsWire, _ := appParams.Wire()
+	trailingWire, _ := trailing.Wire()
+	digest := sha256.Sum256(append(append([]byte{}, appParamsWire...), trailingWire...))
+	assert.Equal(t, digest[:], i.Name().Last().Value())
+
+	// Round trip
+	encoded, err := i.Encode()
+	assert.NoError(t, err)
+	decoded, err := ndn.DecodeInterest(encoded)
+	assert.NoError(t, err)
+	params := decoded.Parameters()
+	assert.Equal(t, 2, len(params))
+	assert.Equal(t, uint32(tlv.ApplicationParameters), params[0].Type())
+	assert.Equal(t, 1, len(params[0].Subelements()))
+	assert.Equal(t, uint32(0xC0), params[0].Subelements()[0].Type())
+	assert.Equal(t, []byte{0x01, 0x02}, params[0].Subelements()[0].Value())
+	assert.Equal(t, uint32(0xC2), params[1].Type())
+	assert.Equal(t, []byte{0x03}, params[1].Value())
+
+	// Returned blocks are copies
+	params[1].SetValue([]byte{0x04})
+	assert.Equal(t, []byte{0x03}, decoded.Parameters()[1].Value())
+
+	// Opaque parameters that happen to parse as TLV keep their value
+	opaque := tlv.NewBlock(tlv.ApplicationParameters, []byte{0x01, 0x00})
+	assert.NoError(t, i.SetParameters([]*tlv.Block{opaque, tlv.NewBlock(0xC2, []byte{0x02, 0x00})}))
+	encoded, err = i.Encode()
+	assert.NoError(t, err)
+	decoded, err = ndn.DecodeInterest(encoded)
+	assert.NoError(t, err)
+	params = decoded.Parameters()
+	assert.Equal(t, []byte{0x01, 0x00}, params[0].Value())
+	assert.Equal(t, 1, len(params[0].Subelements()))
+	assert.Equal(t, []byte{0x02, 0x00}, params[1].Value())
+	opaqueWire, _ := opaque.Wire()
+	paramWire, err := params[0].Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, opaqueWire, paramWire)
+	assert.NoError(t, decoded.SetParameters(params))
+	assert.True(t, i.Name().Equals(decoded.Name()))
+
+	// Invalid parameters leave the Interest unchanged
+	assert.NoError(t, i.SetParameters([]*tlv.Block{appParams, trailing}))
+	assert.Error(t, i.SetParameters([]*tlv.Block{trailing}))
+	assert.Error(t, i.SetParameters([]*tlv.Block{appParams, nil}))
+	assert.Equal(t, 2, len(i.Parameters()))
+
+	// Removing the parameters removes the digest
+	assert.NoError(t, i.SetParameters(nil))
+	assert.Equal(t, 0, len(i.Parameters()))
+	assert.Equal(t, "/go/ndn", i.Name().String())
+}
+
+func TestInterestAppendToName(t *testing.T) {
+	name, _ := ndn.NameFromString("/go/ndn")
+	i := ndn.NewInterest(name)
+	i.AppendApplicationParameter(tlv.NewBlock(tlv.ApplicationParameters, []byte{0x01}))
+	assert.Equal(t, tlv.ParametersSha256DigestComponent, int(i.Name().Last().Type()))
+
+	// Components are placed before the digest
+	assert.NoError(t, i.AppendToName(ndn.NewGenericNameComponent([]byte("cmd"))))
+	assert.NoError(t, i.AppendToName(ndn.NewSegmentNameComponent(1)))
+	assert.Equal(t, 5, i.Name().Size())
+	assert.Equal(t, "/go/ndn/cmd/seg=1", i.Name().Prefix(4).String())
+	assert.Equal(t, tlv.ParametersSha256DigestComponent, int(i.Name().At(4).Type()))
+
+	assert.Error(t, i.AppendToName(ndn.NewParametersSha256DigestComponent(make([]byte, 32))))
+	assert.Error(t, i.AppendToName(nil))
+
+	// The digest still covers the parameters after encoding and decoding
+	wire, err := i.Encode()
+	assert.NoError(t, err)
+	decoded, err := ndn.DecodeInterest(wire)
+	assert.NoError(t, err)
+	assert.True(t, i.Name().Equals(decoded.Name()))
+}
+
+func TestInterestFinalize(t *testing.T) {
+	name, _ := ndn.NameFromString("/go/ndn")
+	i := ndn.NewInterest(name)
+	i.AppendApplicationParameter(tlv.NewBlock(tlv.ApplicationParameters, []byte{0x01}))
+	digest := i.Name().Last()
+
+	// Replacing the name drops the digest, which Finalize restores
+	i.SetName(name)
+	assert.NoError(t, i.Finalize())
+	assert.True(t, digest.Equals(i.Name().Last()))
+
+	// A stale digest is recomputed in place
+	stale := name.DeepCopy().Append(ndn.NewSegmentNameComponent(1)).Append(ndn.NewParametersSha256DigestComponent(make([]byte, 32)))
+	i.SetName(stale)
+	wire, err := i.Encode()
+	assert.NoError(t, err)
+	assert.True(t, digest.Equals(i.Name().At(3)))
+	_, err = ndn.DecodeInterest(wire)
+	assert.NoError(t, err)
+
+	// Without parameters, the digest is removed
+	i.ClearApplicationParameters()
+	assert.NoError(t, i.Finalize())
+	assert.Equal(t, "/go/ndn/seg=1", i.Name().String())
+
+	// More than one digest
+	twice := name.DeepCopy().Append(ndn.NewParametersSha256DigestComponent(make([]byte, 32))).Append(ndn.NewParametersSha256DigestComponent(make([]byte, 32)))
+	i.SetName(twice)
+	assert.Error(t, i.Finalize())
+	_, err = i.Encode()
+	assert.Error(t, err)
+	assert.False(t, i.HasWire())
+}
+
+func TestInterestDecodeParametersDigestPosition(t *testing.T) {
+	digest := make([]byte, 32)
+
+	// More than one ParametersSha256DigestComponent
+	value := []byte{tlv.Name, 0x44, tlv.ParametersSha256DigestComponent, 0x20}
+	value = append(value, digest...)
+	value = append(value, tlv.ParametersSha256DigestComponent, 0x20)
+	value = append(value, digest...)
+	_, err := ndn.DecodeInterest(tlv.NewBlock(tlv.Interest, value))
+	assert.Error(t, err)
+
+	// ImplicitSha256DigestComponent that is not the final component
+	value = []byte{tlv.Name, 0x26, tlv.ImplicitSha256DigestComponent, 0x20}
+	value = append(value, digest...)
+	value = append(value, tlv.GenericNameComponent, 0x02, 0x67, 0x6f)
+	_, err = ndn.DecodeInterest(tlv.NewBlock(tlv.Interest, value))
+	assert.Error(t, err)
+}
+
+func TestInterestDecodeContradictions(t *testing.T) {
+	strict := tlv.DecodeOptions{Strict: true}
+	digest := make([]byte, 32)
+
+	// ImplicitSha256DigestComponent with CanBePrefix
+	value := []byte{tlv.Name, 0x26, tlv.GenericNameComponent, 0x02, 0x67, 0x6f, tlv.ImplicitSha256DigestComponent, 0x20}
+	value = append(value, digest...)
+	i, err := ndn.DecodeInterest(tlv.NewBlock(tlv.Interest, value))
+	assert.NoError(t, err)
+	assert.False(t, i.CanBePrefix())
+	_, err = ndn.DecodeInterest(tlv.NewBlock(tlv.Interest, append(value, tlv.CanBePrefix, 0x00)))
+	assert.EqualError(t, err, "Has both ImplicitSha256DigestComponent and CanBePrefix")
+	_, err = ndn.DecodeInterestWithOptions(tlv.NewBlock(tlv.Interest, append(value, tlv.CanBePrefix, 0x00)), strict)
+	assert.EqualError(t, err, "Has both ImplicitSha256DigestComponent and CanBePrefix")
+
+	// ParametersSha256DigestComponent without ApplicationParameters
+	value = []byte{tlv.Name, 0x26, tlv.GenericNameComponent, 0x02, 0x67, 0x6f, tlv.ParametersSha256DigestComponent, 0x20}
+	value = append(value, digest...)
+	_, err = ndn.DecodeInterest(tlv.NewBlock(tlv.Interest, value))
+	assert.NoError(t, err)
+	_, err = ndn.DecodeInterestWithOptions(tlv.NewBlock(tlv.Interest, value), strict)
+	assert.EqualError(t, err, "Has ParametersSha256DigestComponent but missing ApplicationParameters")
+
+	// ApplicationParameters without ParametersSha256DigestComponent
+	value = []byte{tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f, tlv.ApplicationParameters, 0x01, 0x01}
+	_, err = ndn.DecodeInterest(tlv.NewBlock(tlv.Interest, value))
+	assert.EqualError(t, err, "Has ApplicationParameters but missing ParametersSha256DigestComponent")
+	_, err = ndn.DecodeInterestWithOptions(tlv.NewBlock(tlv.Interest, value), strict)
+	assert.EqualError(t, err, "Has ApplicationParameters but missing ParametersSha256DigestComponent")
+}
+
+func TestInterestMatchesData(t *testing.T) {
+	name, _ := ndn.NameFromString("/go/ndn")
+	d := ndn.NewData(name, []byte{0x01})
+	d.SetSignatureInfo(ndn.NewSignatureInfo(ndn.SignatureDigestSha256))
+	d.SetSignatureValue([]byte{0xaa})
+
+	// Exact match
+	assert.True(t, ndn.NewInterest(name).MatchesData(d))
+	longer, _ := ndn.NameFromString("/go/ndn/data")
+	assert.False(t, ndn.NewInterest(longer).MatchesData(d))
+	prefix, _ := ndn.NameFromString("/go")
+	assert.False(t, ndn.NewInterest(prefix).MatchesData(d))
+	assert.False(t, ndn.NewInterest(name).MatchesData(nil))
+
+	// CanBePrefix
+	i := ndn.NewInterest(prefix)
+	i.SetCanBePrefix(true)
+	assert.True(t, i.MatchesData(d))
+	i = ndn.NewInterest(longer)
+	i.SetCanBePrefix(true)
+	assert.False(t, i.MatchesData(d))
+
+	// MustBeFresh
+	i = ndn.NewInterest(name)
+	i.SetMustBeFresh(true)
+	assert.False(t, i.MatchesData(d))
+	metaInfo := ndn.NewMetaInfo()
+	freshnessPeriod := time.Duration(0)
+	metaInfo.SetFreshnessPeriod(&freshnessPeriod)
+	d.SetMetaInfo(metaInfo)
+	assert.False(t, i.MatchesData(d))
+	freshnessPeriod = time.Second
+	metaInfo.SetFreshnessPeriod(&freshnessPeriod)
+	d.SetMetaInfo(metaInfo)
+	assert.True(t, i.MatchesData(d))
+
+	// Implicit digest
+	fullName, err := d.FullName()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, fullName.Size())
+	assert.Equal(t, uint16(tlv.ImplicitSha256DigestComponent), fullName.At(2).Type())
+	assert.True(t, ndn.NewInterest(fullName).MatchesData(d))
+	other := d.DeepCopy()
+	other.SetContent([]byte{0x02})
+	assert.False(t, ndn.NewInterest(fullName).MatchesData(other))
+}
+
+func TestInterestCanBePrefixDefault(t *testing.T) {
+	// Interest without CanBePrefix must match Data names exactly
+	block := tlv.NewBlock(tlv.Interest, []byte{
+		tlv.Name, 0x09, tlv.GenericNameComponent, 0x02, 0x67, 0x6f, tlv.GenericNameComponent, 0x03, 0x6e, 0x64, 0x6e,
+		tlv.Nonce, 0x04, 0x01, 0x02, 0x03, 0x04})
+	i, err := ndn.DecodeInterest(block)
+	assert.NoError(t, err)
+	assert.False(t, i.CanBePrefix())
+
+	exact, _ := ndn.NameFromString("/go/ndn")
+	longer, _ := ndn.NameFromString("/go/ndn/v=1")
+	assert.True(t, i.MatchesData(ndn.NewData(exact, nil)))
+	assert.False(t, i.MatchesData(ndn.NewData(longer, nil)))
+
+	// Re-encoding does not add a CanBePrefix element
+	i.ResetNonce()
+	encoded, err := i.Encode()
+	assert.NoError(t, err)
+	assert.True(t, encoded.Parse())
+	assert.Nil(t, encoded.Find(tlv.CanBePrefix))
+
+	// Setting and clearing CanBePrefix
+	i.SetCanBePrefix(true)
+	assert.True(t, i.MatchesData(ndn.NewData(longer, nil)))
+	encoded, err = i.Encode()
+	assert.NoError(t, err)
+	assert.True(t, encoded.Parse())
+	assert.NotNil(t, encoded.Find(tlv.CanBePrefix))
+	i.SetCanBePrefix(false)
+	encoded, err = i.Encode()
+	assert.NoError(t, err)
+	assert.True(t, encoded.Parse())
+	assert.Nil(t, encoded.Find(tlv.CanBePrefix))
+	assert.False(t, i.MatchesData(ndn.NewData(longer, nil)))
+}
+
+func TestInterestDecrementHopLimit(t *testing.T) {
+	name, _ := ndn.NameFromString("/go/ndn")
+	i := ndn.NewInterest(name)
+
+	// Absent
+	assert.False(t, i.DecrementHopLimit())
+	assert.Nil(t, i.HopLimit())
+	assert.True(t, i.CanForwardToNonLocal())
+
+	hopLimit := uint8(2)
+	i.SetHopLimit(&hopLimit)
+	_, err := i.Encode()
+	assert.NoError(t, err)
+	assert.True(t, i.DecrementHopLimit())
+	assert.False(t, i.HasWire())
+	assert.Equal(t, uint8(1), *i.HopLimit())
+	assert.True(t, i.CanForwardToNonLocal())
+
+	// Reaches zero: local faces only
+	assert.True(t, i.DecrementHopLimit())
+	assert.Equal(t, uint8(0), *i.HopLimit())
+	assert.False(t, i.CanForwardToNonLocal())
+
+	// Cannot go below zero
+	assert.False(t, i.DecrementHopLimit())
+	assert.Equal(t, uint8(0), *i.HopLimit())
+}
+
+func TestInterestNonceAbsent(t *testing.T) {
+	i, err := ndn.DecodeInterest(tlv.NewBlock(tlv.Interest, []byte{tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f}))
+	assert.NoError(t, err)
+	assert.False(t, i.HasNonce())
+	assert.Nil(t, i.Nonce())
+	assert.Equal(t, "Interest(Name=/go, Lifetime=4000ms)", i.String())
+
+	// Encoding omits the absent nonce
+	wire, err := i.Encode()
+	assert.NoError(t, err)
+	assert.True(t, wire.Parse())
+	assert.Nil(t, wire.Find(tlv.Nonce))
+
+	// A zero nonce is distinct from an absent nonce
+	i, err = ndn.DecodeInterest(tlv.NewBlock(tlv.Interest, []byte{tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f, tlv.Nonce, 0x04, 0x00, 0x00, 0x00, 0x00}))
+	assert.NoError(t, err)
+	assert.True(t, i.HasNonce())
+	assert.Equal(t, []byte{0x00, 0x00, 0x00, 0x00}, i.Nonce())
+
+	i.UnsetNonce()
+	assert.False(t, i.HasNonce())
+	i.ResetNonce()
+	assert.True(t, i.HasNonce())
+	assert.Equal(t, 4, len(i.Nonce()))
+}
+
+func TestInterestDecodeOptions(t *testing.T) {
+	strict := tlv.DecodeOptions{Strict: true}
+
+	// Non-minimal InterestLifetime
+	value := []byte{tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f, tlv.InterestLifetime, 0x02, 0x00, 0x64}
+	i, err := ndn.DecodeInterest(tlv.NewBlock(tlv.Interest, value))
+	assert.NoError(t, err)
+	assert.Equal(t, 100*time.Millisecond, i.Lifetime())
+	_, err = ndn.DecodeInterestWithOptions(tlv.NewBlock(tlv.Interest, value), strict)
+	assert.Error(t, err)
+
+	// Non-minimal TLV-LENGTH of an element
+	value = []byte{tlv.Name, 0xFD, 0x00, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f}
+	i, err = ndn.DecodeInterest(tlv.NewBlock(tlv.Interest, value))
+	assert.NoError(t, err)
+	assert.Equal(t, "/go", i.Name().String())
+	_, err = ndn.DecodeInterestWithOptions(tlv.NewBlock(tlv.Interest, value), strict)
+	assert.Error(t, err)
+
+	// Non-minimal NNI in a name component
+	value = []byte{tlv.Name, 0x04, tlv.SegmentNameComponent, 0x02, 0x00, 0x05, tlv.CanBePrefix, 0x00}
+	i, err = ndn.DecodeInterest(tlv.NewBlock(tlv.Interest, value))
+	assert.NoError(t, err)
+	assert.Equal(t, "/seg=5", i.Name().String())
+	_, err = ndn.DecodeInterestWithOptions(tlv.NewBlock(tlv.Interest, value), strict)
+	assert.Error(t, err)
+
+	// Non-minimal TLV-LENGTH of a name component
+	value = []byte{tlv.Name, 0x06, tlv.GenericNameComponent, 0xFD, 0x00, 0x02, 0x67, 0x6f}
+	i, err = ndn.DecodeInterest(tlv.NewBlock(tlv.Interest, value))
+	assert.NoError(t, err)
+	assert.Equal(t, "/go", i.Name().String())
+	_, err = ndn.DecodeInterestWithOptions(tlv.NewBlock(tlv.Interest, value), strict)
+	assert.Error(t, err)
+
+	// Out-of-order elements
+	value = []byte{tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f, tlv.MustBeFresh, 0x00, tlv.CanBePrefix, 0x00}
+	i, err = ndn.DecodeInterest(tlv.NewBlock(tlv.Interest, value))
+	assert.NoError(t, err)
+	assert.True(t, i.CanBePrefix())
+	assert.True(t, i.MustBeFresh())
+	_, err = ndn.DecodeInterestWithOptions(tlv.NewBlock(tlv.Interest, value), strict)
+	assert.Error(t, err)
+
+	// Duplicate elements are always rejected
+	value = []byte{tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f, tlv.CanBePrefix, 0x00, tlv.CanBePrefix, 0x00}
+	_, err = ndn.DecodeInterest(tlv.NewBlock(tlv.Interest, value))
+	assert.Error(t, err)
+
+	// Canonical encodings are accepted when strict
+	value = []byte{tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f, tlv.CanBePrefix, 0x00, tlv.InterestLifetime, 0x01, 0x64}
+	i, err = ndn.DecodeInterestWithOptions(tlv.NewBlock(tlv.Interest, value), strict)
+	assert.NoError(t, err)
+	assert.Equal(t, 100*time.Millisecond, i.Lifetime())
+}
+
+func TestInterestClone(t *testing.T) {
+	name, _ := ndn.NameFromString("/go/ndn")
+	i := ndn.NewInterest(name)
+	i.SetMustBeFresh(true)
+	hopLimit := uint8(10)
+	i.SetHopLimit(&hopLimit)
+	hintName, _ := ndn.NameFromString("/ucla")
+	i.AppendForwardingHint(hintName)
+	i.AppendApplicationParameter(tlv.NewBlock(0x80, []byte{0x01}))
+	encoded, err := i.Encode()
+	assert.NoError(t, err)
+	wire, err := encoded.Wire()
+	assert.NoError(t, err)
+
+	clone := i.Clone()
+	assert.Equal(t, i.Nonce(), clone.Nonce())
+	cloneEncoded, err := clone.Encode()
+	assert.NoError(t, err)
+	cloneWire, err := cloneEncoded.Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, wire, cloneWire)
+
+	// Modifying the original does not affect the clone
+	i.DecrementHopLimit()
+	i.SetName(hintName)
+	i.EraseForwardingHint(0)
+	i.AppendApplicationParameter(tlv.NewBlock(0x82, []byte{0x02}))
+	i.ResetNonce()
+	assert.Equal(t, uint8(10), *clone.HopLimit())
+	assert.Equal(t, 1, len(clone.ForwardingHint()))
+	assert.Equal(t, 2, len(clone.ApplicationParameters()))
+	assert.True(t, clone.Name().At(1).Equals(ndn.NewGenericNameComponent([]byte("ndn"))))
+	cloneEncoded, err = clone.Encode()
+	assert.NoError(t, err)
+	cloneWire, err = cloneEncoded.Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, wire, cloneWire)
+
+	// New nonce
+	renewed := clone.CloneWithNewNonce()
+	assert.True(t, renewed.HasNonce())
+	assert.NotEqual(t, clone.Nonce(), renewed.Nonce())
+	assert.Equal(t, clone.Name(), renewed.Name())
+	assert.Equal(t, clone.ForwardingHint(), renewed.ForwardingHint())
+	assert.Equal(t, *clone.HopLimit(), *renewed.HopLimit())
+
+	// Absent nonce stays absent in a plain clone
+	clone.UnsetNonce()
+	assert.False(t, clone.Clone().HasNonce())
+	assert.True(t, clone.CloneWithNewNonce().HasNonce())
+}
+
+func TestInterestSameAs(t *testing.T) {
+	name, _ := ndn.NameFromString("/go/ndn")
+	i1 := ndn.NewInterest(name)
+	i2 := ndn.NewInterest(name)
+	assert.NotEqual(t, i1.Nonce(), i2.Nonce())
+	i2.SetLifetime(1000 * time.Millisecond)
+	hopLimit := uint8(5)
+	i2.SetHopLimit(&hopLimit)
+	assert.True(t, i1.SameAs(i2))
+	assert.True(t, i2.SameAs(i1))
+	assert.False(t, i1.SameAs(nil))
+
+	other, _ := ndn.NameFromString("/go/other")
+	assert.False(t, i1.SameAs(ndn.NewInterest(other)))
+
+	i2.SetCanBePrefix(true)
+	assert.False(t, i1.SameAs(i2))
+	i1.SetCanBePrefix(true)
+	assert.True(t, i1.SameAs(i2))
+
+	i2.SetMustBeFresh(true)
+	assert.False(t, i1.SameAs(i2))
+	i1.SetMustBeFresh(true)
+	assert.True(t, i1.SameAs(i2))
+
+	ucla, _ := ndn.NameFromString("/ucla")
+	arizona, _ := ndn.NameFromString("/arizona")
+	i1.AppendForwardingHint(ucla)
+	assert.False(t, i1.SameAs(i2))
+	i2.AppendForwardingHint(arizona)
+	assert.False(t, i1.SameAs(i2))
+	i2.ClearForwardingHints()
+	i2.AppendForwardingHint(ucla)
+	assert.True(t, i1.SameAs(i2))
+
+	i1.AppendApplicationParameter(tlv.NewBlock(tlv.ApplicationParameters, []byte{0x01}))
+	assert.False(t, i1.SameAs(i2))
+	i2.AppendApplicationParameter(tlv.NewBlock(tlv.ApplicationParameters, []byte{0x02}))
+	assert.False(t, i1.SameAs(i2))
+	i2.ClearApplicationParameters()
+	i2.AppendApplicationParameter(tlv.NewBlock(tlv.ApplicationParameters, []byte{0x01}))
+	assert.True(t, i1.SameAs(i2))
+}
+
+func TestInterestWriteTo(t *testing.T) {
+	i := ndn.NewInterest(ndn.NewName().Append(ndn.NewGenericNameComponent([]byte("go"))))
+	i.AppendApplicationParameter(tlv.NewBlock(0x80, []byte{0x01}))
+
+	var buf bytes.Buffer
+	written, err := i.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), written)
+	encoded, err := i.Encode()
+	assert.NoError(t, err)
+	wire, err := encoded.Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, wire, buf.Bytes())
+
+	allocs := testing.AllocsPerRun(100, func() {
+		i.WriteTo(ioutil.Discard)
+	})
+	assert.Equal(t, 0.0, allocs)
+
+	// Invalid Interests are not written
+	i = ndn.NewInterest(ndn.NewName())
+	buf.Reset()
+	_, err = i.WriteTo(&buf)
+	assert.Error(t, err)
+	assert.Equal(t, 0, buf.Len())
+}
+
+func BenchmarkInterestWriteTo(b *testing.B) {
+	i := ndn.NewInterest(ndn.NewName().Append(ndn.NewGenericNameComponent([]byte("go"))).Append(ndn.NewGenericNameComponent([]byte("ndn"))))
+	i.WriteTo(ioutil.Discard)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		i.WriteTo(ioutil.Discard)
+	}
+}
+
+func BenchmarkInterestEncodeWrite(b *testing.B) {
+	i := ndn.NewInterest(ndn.NewName().Append(ndn.NewGenericNameComponent([]byte("go"))).Append(ndn.NewGenericNameComponent([]byte("ndn"))))
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		encoded, _ := i.Encode()
+		wire, _ := encoded.Wire()
+		ioutil.Discard.Write(wire)
+	}
+}
+
+func TestInterestVerifyCanonicalEncoding(t *testing.T) {
+	name, _ := ndn.NameFromString("/go/ndn")
+	i := ndn.NewInterest(name)
+	i.SetLifetime(time.Second)
+	i.AppendApplicationParameter(tlv.NewBlock(0x80, []byte{0x01}))
+	assert.NoError(t, i.VerifyCanonicalEncoding())
+
+	// Interest without InterestLifetime
+	i, err := ndn.DecodeInterest(tlv.NewBlock(tlv.Interest, []byte{
+		tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f,
+		tlv.Nonce, 0x04, 0x01, 0x02, 0x03, 0x04}))
+	assert.NoError(t, err)
+	assert.Equal(t, ndn.DefaultInterestLifetime, i.Lifetime())
+	assert.NoError(t, i.VerifyCanonicalEncoding())
+
+	// Explicit default InterestLifetime is kept
+	i, err = ndn.DecodeInterest(tlv.NewBlock(tlv.Interest, []byte{
+		tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f,
+		tlv.Nonce, 0x04, 0x01, 0x02, 0x03, 0x04,
+		tlv.InterestLifetime, 0x02, 0x0f, 0xa0}))
+	assert.NoError(t, err)
+	assert.Equal(t, ndn.DefaultInterestLifetime, i.Lifetime())
+	assert.NoError(t, i.VerifyCanonicalEncoding())
+	i.SetMustBeFresh(true)
+	encoded, err := i.Encode()
+	assert.NoError(t, err)
+	wire, err := encoded.Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{tlv.InterestLifetime, 0x02, 0x0f, 0xa0}, wire[len(wire)-4:])
+
+	for _, wire := range [][]byte{
+		// Non-minimal InterestLifetime
+		{tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f,
+			tlv.InterestLifetime, 0x02, 0x00, 0x64},
+		// MustBeFresh before CanBePrefix
+		{tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f,
+			tlv.MustBeFresh, 0x00, tlv.CanBePrefix, 0x00},
+	} {
+		i, err := ndn.DecodeInterest(tlv.NewBlock(tlv.Interest, wire))
+		assert.NoError(t, err)
+		assert.Error(t, i.VerifyCanonicalEncoding())
+	}
+}
+
+func TestInterestEncodeWithMaxSize(t *testing.T) {
+	name, _ := ndn.NameFromString("/go/ndn")
+	i := ndn.NewInterest(name)
+	_, err := i.EncodeWithMaxSize(ndn.MaxPacketSize)
+	assert.NoError(t, err)
+
+	i.AppendApplicationParameter(tlv.NewBlock(tlv.ApplicationParameters, make([]byte, ndn.MaxPacketSize)))
+	_, err = i.EncodeWithMaxSize(ndn.MaxPacketSize)
+	assert.Equal(t, util.ErrTooLong, err)
+	_, err = i.Encode()
+	assert.NoError(t, err)
+}
+
+// interestWire returns the wire encoding of the Interest.
+func interestWire(t *testing.T, i *ndn.Interest) []byte {
+	encoded, err := i.Encode()
+	assert.NoError(t, err)
+	wire, err := encoded.Wire()
+	assert.NoError(t, err)
+	return wire
+}
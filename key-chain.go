@@ -0,0 +1,249 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/eric135/go-ndn2/util"
+)
+
+// PEM block types used by KeyChain files.
+const (
+	keyChainPrivateKeyPEMType  = "EC PRIVATE KEY"
+	keyChainCertificatePEMType = "NDN CERTIFICATE"
+)
+
+// DefaultCertificateValidity is the validity period of self-signed certificates created by KeyChain.
+var DefaultCertificateValidity = 365 * 24 * time.Hour
+
+// KeyChain manages identities, their ECDSA P-256 key pairs, and their self-signed certificates, persisted to a directory of PEM files.
+type KeyChain struct {
+	path       string
+	identities map[string]*keyChainIdentity
+	lock       sync.RWMutex
+}
+
+type keyChainIdentity struct {
+	key         *ecdsa.PrivateKey
+	certificate *Certificate
+}
+
+// NewKeyChain opens the KeyChain stored in the specified directory, creating the directory if it does not exist.
+func NewKeyChain(path string) (*KeyChain, error) {
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return nil, err
+	}
+
+	k := new(KeyChain)
+	k.path = path
+	k.identities = make(map[string]*keyChainIdentity)
+
+	files, err := filepath.Glob(filepath.Join(path, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		identity, err := loadKeyChainIdentity(file)
+		if err != nil {
+			return nil, errors.New("Error loading " + file + ": " + err.Error())
+		}
+		k.identities[identity.certificate.Identity().String()] = identity
+	}
+	return k, nil
+}
+
+// loadKeyChainIdentity reads an identity from the specified PEM file.
+func loadKeyChainIdentity(file string) (*keyChainIdentity, error) {
+	contents, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := new(keyChainIdentity)
+	for block, rest := pem.Decode(contents); block != nil; block, rest = pem.Decode(rest) {
+		switch block.Type {
+		case keyChainPrivateKeyPEMType:
+			if identity.key, err = x509.ParseECPrivateKey(block.Bytes); err != nil {
+				return nil, err
+			}
+		case keyChainCertificatePEMType:
+			wire, _, err := tlv.DecodeBlock(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			if identity.certificate, err = DecodeCertificate(wire); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if identity.key == nil || identity.certificate == nil {
+		return nil, errors.New("File does not contain a private key and certificate")
+	}
+	return identity, nil
+}
+
+// identityFile returns the path of the PEM file for the specified identity.
+func (k *KeyChain) identityFile(name *Name) string {
+	digest := sha256.Sum256([]byte(name.String()))
+	return filepath.Join(k.path, hex.EncodeToString(digest[:])+".pem")
+}
+
+// CreateIdentity creates an identity with a new ECDSA P-256 key pair and a self-signed certificate, returning the certificate.
+func (k *KeyChain) CreateIdentity(name *Name) (*Certificate, error) {
+	if name == nil {
+		return nil, util.ErrNonExistent
+	}
+
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	if _, ok := k.identities[name.String()]; ok {
+		return nil, errors.New("Identity already exists")
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	keyID := make([]byte, 8)
+	if _, err := rand.Read(keyID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	certificateName := MakeCertificateName(name, NewGenericNameComponent(keyID), NewGenericNameComponent([]byte("self")), uint64(now.UnixNano()/int64(time.Millisecond)))
+	keyName := certificateName.Prefix(certificateName.Size() - 2)
+
+	data := NewData(certificateName, publicKey)
+	metaInfo := NewMetaInfo()
+	contentType := uint64(ContentTypeKey)
+	metaInfo.SetContentType(&contentType)
+	freshnessPeriod := time.Hour
+	metaInfo.SetFreshnessPeriod(&freshnessPeriod)
+	data.SetMetaInfo(metaInfo)
+	signatureInfo := NewSignatureInfo(SignatureSha256WithEcdsa)
+	signatureInfo.SetValidityPeriod(NewValidityPeriod(now, now.Add(DefaultCertificateValidity)))
+	data.SetSignatureInfo(signatureInfo)
+	if err := data.Sign(NewEcdsaSigner(keyName, key)); err != nil {
+		return nil, err
+	}
+	certificate, err := NewCertificate(data)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &keyChainIdentity{key: key, certificate: certificate}
+	if err := k.saveIdentity(identity); err != nil {
+		return nil, err
+	}
+	k.identities[name.String()] = identity
+	return certificate.DeepCopy(), nil
+}
+
+// saveIdentity writes the specified identity to its PEM file.
+func (k *KeyChain) saveIdentity(identity *keyChainIdentity) error {
+	privateKey, err := x509.MarshalECPrivateKey(identity.key)
+	if err != nil {
+		return err
+	}
+	certificateBlock, err := identity.certificate.Encode()
+	if err != nil {
+		return err
+	}
+	certificate, err := certificateBlock.Wire()
+	if err != nil {
+		return err
+	}
+
+	contents := pem.EncodeToMemory(&pem.Block{Type: keyChainPrivateKeyPEMType, Bytes: privateKey})
+	contents = append(contents, pem.EncodeToMemory(&pem.Block{Type: keyChainCertificatePEMType, Bytes: certificate})...)
+	return ioutil.WriteFile(k.identityFile(identity.certificate.Identity()), contents, 0600)
+}
+
+// DeleteIdentity removes the specified identity and its keys from the KeyChain.
+func (k *KeyChain) DeleteIdentity(name *Name) error {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	if _, ok := k.identities[name.String()]; !ok {
+		return util.ErrNonExistent
+	}
+	if err := os.Remove(k.identityFile(name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	delete(k.identities, name.String())
+	return nil
+}
+
+// Identities returns the names of all identities in the KeyChain, in canonical order.
+func (k *KeyChain) Identities() []*Name {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+
+	names := make([]*Name, 0, len(k.identities))
+	for _, identity := range k.identities {
+		names = append(names, identity.certificate.Identity())
+	}
+	sort.Slice(names, func(i int, j int) bool {
+		return names[i].Compare(names[j]) < 0
+	})
+	return names
+}
+
+// Certificate returns a copy of the certificate of the specified identity.
+func (k *KeyChain) Certificate(identity *Name) (*Certificate, error) {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+
+	i, ok := k.identities[identity.String()]
+	if !ok {
+		return nil, util.ErrNonExistent
+	}
+	return i.certificate.DeepCopy(), nil
+}
+
+// GetSigner returns a signer using the key of the specified identity.
+func (k *KeyChain) GetSigner(identity *Name) (Signer, error) {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+
+	i, ok := k.identities[identity.String()]
+	if !ok {
+		return nil, util.ErrNonExistent
+	}
+	return NewEcdsaSigner(i.certificate.KeyName(), i.key), nil
+}
+
+// Sign signs the specified Data with the key of the specified identity.
+func (k *KeyChain) Sign(d *Data, identity *Name) error {
+	signer, err := k.GetSigner(identity)
+	if err != nil {
+		return err
+	}
+	return d.Sign(signer)
+}
@@ -0,0 +1,79 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	ndn "github.com/eric135/go-ndn2"
+	"github.com/eric135/go-ndn2/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyChain(t *testing.T) {
+	path, err := ioutil.TempDir("", "keychain")
+	assert.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	keyChain, err := ndn.NewKeyChain(path)
+	assert.NoError(t, err)
+	assert.Empty(t, keyChain.Identities())
+
+	identity, _ := ndn.NameFromString("/go/ndn")
+	certificate, err := keyChain.CreateIdentity(identity)
+	assert.NoError(t, err)
+	assert.True(t, identity.Equals(certificate.Identity()))
+	assert.Equal(t, "self", certificate.Issuer().String())
+	assert.Equal(t, uint64(ndn.ContentTypeKey), *certificate.MetaInfo().ContentType())
+	assert.True(t, certificate.ValidityPeriod().IsValid(time.Now()))
+	assert.True(t, certificate.KeyName().Equals(certificate.SignatureInfo().KeyLocator().Name()))
+	_, err = keyChain.CreateIdentity(identity)
+	assert.Error(t, err)
+
+	// Sign Data and verify with the certificate's public key
+	name, _ := ndn.NameFromString("/go/ndn/data")
+	d := ndn.NewData(name, []byte{0x01, 0x02})
+	assert.NoError(t, keyChain.Sign(d, identity))
+	assert.True(t, certificate.KeyName().Equals(d.SignatureInfo().KeyLocator().Name()))
+	publicKey, err := certificate.PublicKey()
+	assert.NoError(t, err)
+	input, signatureValue := signedPortion(t, d)
+	digest := sha256.Sum256(input)
+	assert.True(t, ecdsa.VerifyASN1(publicKey.(*ecdsa.PublicKey), digest[:], signatureValue))
+
+	unknown, _ := ndn.NameFromString("/unknown")
+	_, err = keyChain.GetSigner(unknown)
+	assert.Equal(t, util.ErrNonExistent, err)
+	assert.Error(t, keyChain.Sign(d, unknown))
+
+	// Reload from disk
+	reloaded, err := ndn.NewKeyChain(path)
+	assert.NoError(t, err)
+	assert.Len(t, reloaded.Identities(), 1)
+	assert.True(t, identity.Equals(reloaded.Identities()[0]))
+	reloadedCertificate, err := reloaded.Certificate(identity)
+	assert.NoError(t, err)
+	assert.True(t, certificate.Name().Equals(reloadedCertificate.Name()))
+	assert.NoError(t, reloaded.Sign(d, identity))
+	input, signatureValue = signedPortion(t, d)
+	digest = sha256.Sum256(input)
+	assert.True(t, ecdsa.VerifyASN1(publicKey.(*ecdsa.PublicKey), digest[:], signatureValue))
+
+	// Delete
+	assert.NoError(t, reloaded.DeleteIdentity(identity))
+	assert.Empty(t, reloaded.Identities())
+	assert.Equal(t, util.ErrNonExistent, reloaded.DeleteIdentity(identity))
+	reloaded, err = ndn.NewKeyChain(path)
+	assert.NoError(t, err)
+	assert.Empty(t, reloaded.Identities())
+}
@@ -0,0 +1,131 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn
+
+import (
+	"encoding/hex"
+	"errors"
+
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/eric135/go-ndn2/util"
+)
+
+// KeyLocator identifies the key used to sign a packet, either by name or by digest.
+type KeyLocator struct {
+	name   *Name
+	digest []byte
+}
+
+// NewKeyLocatorName creates a KeyLocator containing the specified key name.
+func NewKeyLocatorName(name *Name) *KeyLocator {
+	k := new(KeyLocator)
+	k.name = name.DeepCopy()
+	return k
+}
+
+// NewKeyLocatorDigest creates a KeyLocator containing the specified key digest.
+func NewKeyLocatorDigest(digest []byte) *KeyLocator {
+	k := new(KeyLocator)
+	k.digest = make([]byte, len(digest))
+	copy(k.digest, digest)
+	return k
+}
+
+// DecodeKeyLocator decodes a KeyLocator from the wire.
+func DecodeKeyLocator(wire *tlv.Block) (*KeyLocator, error) {
+	return decodeKeyLocator(wire, tlv.DecodeOptions{})
+}
+
+// decodeKeyLocator decodes a KeyLocator from the wire, checking the encoding according to the specified options.
+func decodeKeyLocator(wire *tlv.Block, opts tlv.DecodeOptions) (*KeyLocator, error) {
+	if wire == nil {
+		return nil, util.ErrNonExistent
+	}
+	if !wire.Parse() {
+		return nil, tlv.ErrBufferTooShort
+	}
+	if err := checkElements(wire, opts); err != nil {
+		return nil, err
+	}
+
+	k := new(KeyLocator)
+	for _, elem := range wire.Subelements() {
+		switch elem.Type() {
+		case tlv.Name:
+			if k.name != nil || k.digest != nil {
+				return nil, errors.New("KeyLocator contains more than one key identifier")
+			}
+			name, err := decodeName(elem, InvalidComponentError, opts)
+			if err != nil {
+				return nil, err
+			}
+			k.name = name
+		case tlv.KeyDigest:
+			if k.name != nil || k.digest != nil {
+				return nil, errors.New("KeyLocator contains more than one key identifier")
+			}
+			k.digest = make([]byte, len(elem.Value()))
+			copy(k.digest, elem.Value())
+		default:
+			if tlv.IsCritical(elem.Type()) {
+				return nil, tlv.ErrUnrecognizedCritical
+			}
+			// If non-critical, ignore
+		}
+	}
+
+	if k.name == nil && k.digest == nil {
+		return nil, errors.New("KeyLocator is empty")
+	}
+	return k, nil
+}
+
+func (k *KeyLocator) String() string {
+	if k.name != nil {
+		return "KeyLocator(Name=" + k.name.String() + ")"
+	}
+	return "KeyLocator(KeyDigest=0x" + hex.EncodeToString(k.digest) + ")"
+}
+
+// DeepCopy returns a deep copy of the KeyLocator.
+func (k *KeyLocator) DeepCopy() *KeyLocator {
+	if k.name != nil {
+		return NewKeyLocatorName(k.name)
+	}
+	return NewKeyLocatorDigest(k.digest)
+}
+
+// Name returns a copy of the key name in the KeyLocator, or nil if the KeyLocator contains a digest.
+func (k *KeyLocator) Name() *Name {
+	if k.name == nil {
+		return nil
+	}
+	return k.name.DeepCopy()
+}
+
+// Digest returns a copy of the key digest in the KeyLocator, or nil if the KeyLocator contains a name.
+func (k *KeyLocator) Digest() []byte {
+	if k.digest == nil {
+		return nil
+	}
+	digest := make([]byte, len(k.digest))
+	copy(digest, k.digest)
+	return digest
+}
+
+// Encode encodes the KeyLocator into a block.
+func (k *KeyLocator) Encode() *tlv.Block {
+	wire := tlv.NewEmptyBlock(tlv.KeyLocator)
+	if k.name != nil {
+		wire.Append(k.name.Encode())
+	} else {
+		wire.Append(tlv.NewBlock(tlv.KeyDigest, k.digest))
+	}
+	wire.Wire()
+	return wire
+}
@@ -0,0 +1,211 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/eric135/go-ndn2/util"
+)
+
+// Content types.
+const (
+	ContentTypeBlob = 0
+	ContentTypeLink = 1
+	ContentTypeKey  = 2
+	ContentTypeNack = 3
+)
+
+// MetaInfo contains the metadata of a Data packet.
+type MetaInfo struct {
+	contentType     *uint64
+	freshnessPeriod *time.Duration
+	finalBlockID    NameComponent
+}
+
+// NewMetaInfo creates an empty MetaInfo.
+func NewMetaInfo() *MetaInfo {
+	return new(MetaInfo)
+}
+
+// DecodeMetaInfo decodes a MetaInfo from the wire.
+func DecodeMetaInfo(wire *tlv.Block) (*MetaInfo, error) {
+	return decodeMetaInfo(wire, tlv.DecodeOptions{})
+}
+
+// decodeMetaInfo decodes a MetaInfo from the wire, checking the encoding according to the specified options.
+func decodeMetaInfo(wire *tlv.Block, opts tlv.DecodeOptions) (*MetaInfo, error) {
+	if wire == nil {
+		return nil, util.ErrNonExistent
+	}
+	if !wire.Parse() {
+		return nil, tlv.ErrBufferTooShort
+	}
+
+	m := new(MetaInfo)
+	order := newElementOrder(opts)
+	for _, elem := range wire.Subelements() {
+		switch elem.Type() {
+		case tlv.ContentType:
+			if err := order.check(1, "ContentType"); err != nil {
+				return nil, err
+			}
+			contentType, err := opts.DecodeNNIBlock(elem)
+			if err != nil {
+				return nil, errors.New("Error decoding ContentType")
+			}
+			m.SetContentType(&contentType)
+		case tlv.FreshnessPeriod:
+			if err := order.check(2, "FreshnessPeriod"); err != nil {
+				return nil, err
+			}
+			freshnessPeriod, err := opts.DecodeNNIBlock(elem)
+			if err != nil {
+				return nil, errors.New("Error decoding FreshnessPeriod")
+			}
+			freshness := time.Duration(freshnessPeriod) * time.Millisecond
+			m.SetFreshnessPeriod(&freshness)
+		case tlv.FinalBlockID:
+			if err := order.check(3, "FinalBlockId"); err != nil {
+				return nil, err
+			}
+			if err := opts.CheckBlock(elem); err != nil {
+				return nil, errors.New("Error decoding FinalBlockId")
+			}
+			componentBlock, componentLen, err := opts.DecodeBlock(elem.Value())
+			if err != nil || componentLen != uint64(len(elem.Value())) {
+				return nil, errors.New("Error decoding FinalBlockId")
+			}
+			finalBlockID, err := decodeNameComponent(componentBlock.Type(), componentBlock.Value(), opts)
+			if err != nil {
+				return nil, errors.New("Error decoding FinalBlockId")
+			}
+			m.finalBlockID = finalBlockID
+		default:
+			if tlv.IsCritical(elem.Type()) {
+				return nil, tlv.ErrUnrecognizedCritical
+			}
+			// If non-critical, ignore
+		}
+	}
+
+	return m, nil
+}
+
+func (m *MetaInfo) String() string {
+	str := "MetaInfo("
+	isFirstField := true
+	if m.contentType != nil {
+		str += "ContentType=" + strconv.FormatUint(*m.contentType, 10)
+		isFirstField = false
+	}
+	if m.freshnessPeriod != nil {
+		if !isFirstField {
+			str += ", "
+		}
+		str += "FreshnessPeriod=" + strconv.FormatInt(m.freshnessPeriod.Milliseconds(), 10) + "ms"
+		isFirstField = false
+	}
+	if m.finalBlockID != nil {
+		if !isFirstField {
+			str += ", "
+		}
+		str += "FinalBlockId=" + m.finalBlockID.String()
+	}
+	str += ")"
+	return str
+}
+
+// DeepCopy returns a deep copy of the MetaInfo.
+func (m *MetaInfo) DeepCopy() *MetaInfo {
+	copyM := new(MetaInfo)
+	copyM.SetContentType(m.contentType)
+	copyM.SetFreshnessPeriod(m.freshnessPeriod)
+	copyM.SetFinalBlockID(m.finalBlockID)
+	return copyM
+}
+
+// ContentType returns the content type or nil if no content type is set.
+func (m *MetaInfo) ContentType() *uint64 {
+	if m.contentType == nil {
+		return nil
+	}
+
+	contentType := new(uint64)
+	*contentType = *m.contentType
+	return contentType
+}
+
+// SetContentType sets the content type (or unsets it if nil is specified).
+func (m *MetaInfo) SetContentType(contentType *uint64) {
+	if contentType == nil {
+		m.contentType = nil
+	} else {
+		m.contentType = new(uint64)
+		*m.contentType = *contentType
+	}
+}
+
+// FreshnessPeriod returns the freshness period or nil if no freshness period is set.
+func (m *MetaInfo) FreshnessPeriod() *time.Duration {
+	if m.freshnessPeriod == nil {
+		return nil
+	}
+
+	freshnessPeriod := new(time.Duration)
+	*freshnessPeriod = *m.freshnessPeriod
+	return freshnessPeriod
+}
+
+// SetFreshnessPeriod sets the freshness period (or unsets it if nil is specified).
+func (m *MetaInfo) SetFreshnessPeriod(freshnessPeriod *time.Duration) {
+	if freshnessPeriod == nil {
+		m.freshnessPeriod = nil
+	} else {
+		m.freshnessPeriod = new(time.Duration)
+		*m.freshnessPeriod = *freshnessPeriod
+	}
+}
+
+// FinalBlockID returns a copy of the final block ID or nil if no final block ID is set.
+func (m *MetaInfo) FinalBlockID() NameComponent {
+	if m.finalBlockID == nil {
+		return nil
+	}
+	return m.finalBlockID.DeepCopy()
+}
+
+// SetFinalBlockID sets the final block ID (or unsets it if nil is specified, including a nil pointer to a concrete component type).
+func (m *MetaInfo) SetFinalBlockID(finalBlockID NameComponent) {
+	if isNilComponent(finalBlockID) {
+		m.finalBlockID = nil
+	} else {
+		m.finalBlockID = finalBlockID.DeepCopy()
+	}
+}
+
+// Encode encodes the MetaInfo into a block.
+func (m *MetaInfo) Encode() *tlv.Block {
+	wire := tlv.NewEmptyBlock(tlv.MetaInfo)
+	if m.contentType != nil {
+		wire.Append(tlv.EncodeNNIBlock(tlv.ContentType, *m.contentType))
+	}
+	if m.freshnessPeriod != nil {
+		wire.Append(tlv.EncodeNNIBlock(tlv.FreshnessPeriod, uint64(m.freshnessPeriod.Milliseconds())))
+	}
+	if m.finalBlockID != nil {
+		finalBlockID := tlv.NewEmptyBlock(tlv.FinalBlockID)
+		finalBlockID.Append(m.finalBlockID.Encode())
+		wire.Append(finalBlockID)
+	}
+	wire.Wire()
+	return wire
+}
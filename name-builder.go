@@ -0,0 +1,106 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn
+
+import (
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/eric135/go-ndn2/util"
+)
+
+// NameBuilder builds names consisting of a fixed base followed by per-packet suffix components, such as the segments of a stream under a common prefix. The base is encoded once when the builder is created, and its encoding is reused by every built name instead of being re-encoded. Only the encoding is saved: each built name still receives its own copies of the base components, since names are mutable and the components returned by At can be changed in place.
+type NameBuilder struct {
+	base      *Name
+	baseValue []byte
+	suffix    []NameComponent
+}
+
+// NewNameBuilder creates a NameBuilder with the specified base. The base is copied, so later changes to it do not affect the builder.
+func NewNameBuilder(base *Name) *NameBuilder {
+	b := new(NameBuilder)
+	if base == nil {
+		b.base = NewName()
+	} else {
+		b.base = base.DeepCopy()
+	}
+	b.base.encodeWire()
+	b.baseValue, _ = b.base.wireValue()
+	return b
+}
+
+// Base returns a copy of the base of the builder.
+func (b *NameBuilder) Base() *Name {
+	return b.base.DeepCopy()
+}
+
+// Append adds the specified name component to the end of the suffix. If the component is nil, the suffix is left unchanged, as with Name.Append.
+func (b *NameBuilder) Append(component NameComponent) *NameBuilder {
+	if isNilComponent(component) {
+		return b
+	}
+	b.suffix = append(b.suffix, component.DeepCopy())
+	return b
+}
+
+// AppendSegment adds a SegmentNameComponent with the specified segment number to the end of the suffix.
+func (b *NameBuilder) AppendSegment(segment uint64) *NameBuilder {
+	return b.Append(NewSegmentNameComponent(segment))
+}
+
+// AppendVersion adds a VersionNameComponent with the specified version number to the end of the suffix.
+func (b *NameBuilder) AppendVersion(version uint64) *NameBuilder {
+	return b.Append(NewVersionNameComponent(version))
+}
+
+// Reset removes all components from the suffix, leaving the base, so that the builder can be reused for the next name.
+func (b *NameBuilder) Reset() {
+	b.suffix = b.suffix[:0]
+}
+
+// Build returns the base followed by the suffix as a new name, whose wire is the cached encoding of the base concatenated with the freshly-encoded suffix. util.ErrTooLong is returned if the name would exceed the limits returned by NameLimits.
+func (b *NameBuilder) Build() (*Name, error) {
+	maxComponents, maxSize := NameLimits()
+	if b.base.Size()+len(b.suffix) > maxComponents {
+		return nil, util.ErrTooLong
+	}
+
+	suffixWires := make([][]byte, len(b.suffix))
+	valueSize := len(b.baseValue)
+	for i, component := range b.suffix {
+		wire, err := component.Encode().Wire()
+		if err != nil {
+			return nil, err
+		}
+		suffixWires[i] = wire
+		valueSize += len(wire)
+	}
+	if tlv.SizeOfVarNumber(tlv.Name)+tlv.SizeOfVarNumber(uint64(valueSize))+valueSize > maxSize {
+		return nil, util.ErrTooLong
+	}
+
+	value := make([]byte, 0, valueSize)
+	value = append(value, b.baseValue...)
+	for _, wire := range suffixWires {
+		value = append(value, wire...)
+	}
+
+	n := new(Name)
+	n.components = make([]NameComponent, 0, b.base.Size()+len(b.suffix))
+	for _, component := range b.base.components {
+		n.components = append(n.components, component.DeepCopy())
+	}
+	for _, component := range b.suffix {
+		n.components = append(n.components, component.DeepCopy())
+	}
+	n.valueSize = valueSize
+	n.wire = tlv.NewBlock(tlv.Name, value)
+	if _, err := n.wire.Wire(); err != nil {
+		return nil, err
+	}
+	n.canonicalWire = true
+	return n, nil
+}
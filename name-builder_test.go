@@ -0,0 +1,93 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn_test
+
+import (
+	"testing"
+
+	ndn "github.com/eric135/go-ndn2"
+	"github.com/eric135/go-ndn2/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNameBuilder(t *testing.T) {
+	base := mustName(t, "/go/ndn/v=3")
+	b := ndn.NewNameBuilder(base)
+	base.AppendGeneric([]byte("changed"))
+	assert.Equal(t, "/go/ndn/v=3", b.Base().String())
+
+	for segment := uint64(0); segment < 3; segment++ {
+		b.Reset()
+		name, err := b.AppendSegment(segment).Build()
+		assert.NoError(t, err)
+		assert.True(t, name.HasWire())
+
+		expected := mustName(t, "/go/ndn/v=3").AppendSegment(segment)
+		assert.True(t, expected.Equals(name))
+		expectedWire, err := expected.Encode().Wire()
+		assert.NoError(t, err)
+		wire, err := name.Encode().Wire()
+		assert.NoError(t, err)
+		assert.Equal(t, expectedWire, wire)
+	}
+
+	// Built names are independent of the builder and of each other
+	b.Reset()
+	name, err := b.Append(ndn.NewGenericNameComponent([]byte("a"))).Build()
+	assert.NoError(t, err)
+	name.At(0).(*ndn.GenericNameComponent).SetValue([]byte("GO"))
+	assert.Equal(t, "/GO/ndn/v=3/a", name.String())
+	other, err := b.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "/go/ndn/v=3/a", other.String())
+
+	// Empty base
+	name, err = ndn.NewNameBuilder(nil).AppendVersion(1).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "/v=1", name.String())
+
+	// Nil components are ignored
+	b.Reset()
+	name, err = b.Append(nil).Append(ndn.NewGenericNameComponent(nil)).AppendSegment(1).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "/go/ndn/v=3/seg=1", name.String())
+}
+
+func TestNameBuilderTooLong(t *testing.T) {
+	b := ndn.NewNameBuilder(ndn.NewName().AppendGeneric(make([]byte, ndn.DefaultMaxNameSize-10)))
+	_, err := b.Build()
+	assert.NoError(t, err)
+	_, err = b.Append(ndn.NewGenericNameComponent(make([]byte, 10))).Build()
+	assert.Equal(t, util.ErrTooLong, err)
+
+	b = ndn.NewNameBuilder(nil)
+	for i := 0; i <= ndn.DefaultMaxNameComponents; i++ {
+		b.AppendSegment(uint64(i))
+	}
+	_, err = b.Build()
+	assert.Equal(t, util.ErrTooLong, err)
+}
+
+func BenchmarkNameBuilder(b *testing.B) {
+	base, _ := ndn.NameFromString("/go/ndn/edu/ucla/stream/v=1")
+	builder := ndn.NewNameBuilder(base)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		builder.Reset()
+		builder.AppendSegment(uint64(i)).Build()
+	}
+}
+
+func BenchmarkNameBuilderAppendEncode(b *testing.B) {
+	base, _ := ndn.NameFromString("/go/ndn/edu/ucla/stream/v=1")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		name := base.DeepCopy().AppendSegment(uint64(i))
+		name.Encode()
+	}
+}
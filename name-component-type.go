@@ -0,0 +1,102 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/eric135/go-ndn2/util"
+)
+
+// NameComponentFactory creates a name component of an application-defined type from its TLV value.
+type NameComponentFactory func(value []byte) (NameComponent, error)
+
+// NameComponentStringer returns the string representation of the TLV value of a name component of an application-defined type.
+type NameComponentStringer func(value []byte) string
+
+// registeredNameComponentType holds the functions registered for an application-defined name component type.
+type registeredNameComponentType struct {
+	factory  NameComponentFactory
+	stringer NameComponentStringer
+}
+
+var (
+	nameComponentTypes     = make(map[uint16]registeredNameComponentType)
+	nameComponentAliases   = make(map[uint16]uint16)
+	nameComponentTypesLock sync.RWMutex
+)
+
+// isBuiltinNameComponentType returns whether the specified TLV type is a name component type implemented by this package.
+func isBuiltinNameComponentType(tlvType uint16) bool {
+	switch tlvType {
+	case tlv.ImplicitSha256DigestComponent, tlv.ParametersSha256DigestComponent, tlv.GenericNameComponent, tlv.KeywordNameComponent,
+		tlv.SegmentNameComponent, tlv.ByteOffsetNameComponent, tlv.VersionNameComponent, tlv.TimestampNameComponent, tlv.SequenceNumNameComponent:
+		return true
+	default:
+		return false
+	}
+}
+
+// RegisterNameComponentType registers an application-defined name component type. When a component of this type is decoded, the factory is called to create it (if nil, a BaseNameComponent is created). The stringer, if not nil, is used in place of the default "<type>=<value>" representation by BaseNameComponent. Built-in types, type 0, and types that are already registered cannot be registered.
+func RegisterNameComponentType(tlvType uint16, factory NameComponentFactory, stringer NameComponentStringer) error {
+	if tlvType == 0 {
+		return util.ErrOutOfRange
+	}
+	if isBuiltinNameComponentType(tlvType) {
+		return errors.New("Cannot override built-in name component type " + strconv.FormatUint(uint64(tlvType), 10))
+	}
+
+	nameComponentTypesLock.Lock()
+	defer nameComponentTypesLock.Unlock()
+	if _, ok := nameComponentTypes[tlvType]; ok {
+		return errors.New("Name component type " + strconv.FormatUint(uint64(tlvType), 10) + " is already registered")
+	}
+	nameComponentTypes[tlvType] = registeredNameComponentType{factory: factory, stringer: stringer}
+	return nil
+}
+
+// lookupNameComponentType returns the functions registered for the specified name component type, if any.
+func lookupNameComponentType(tlvType uint16) (registeredNameComponentType, bool) {
+	nameComponentTypesLock.RLock()
+	defer nameComponentTypesLock.RUnlock()
+	registered, ok := nameComponentTypes[tlvType]
+	return registered, ok
+}
+
+// SetComponentAlias causes BaseNameComponents of the specified type to be displayed by String as if they were of the built-in type displayAs, such as for types emitted by legacy encoders that should be shown as GenericNameComponents. Only the string representation is affected: the TLV type of the components is unchanged. Components whose values are not valid for displayAs, and types with a registered stringer, keep their usual representation. If displayAs is 0, any alias for the type is removed. Built-in types and type 0 cannot be aliased.
+func SetComponentAlias(from uint16, displayAs uint16) error {
+	if from == 0 {
+		return util.ErrOutOfRange
+	}
+	if isBuiltinNameComponentType(from) {
+		return errors.New("Cannot alias built-in name component type " + strconv.FormatUint(uint64(from), 10))
+	}
+	if displayAs != 0 && !isBuiltinNameComponentType(displayAs) {
+		return errors.New("Name component type " + strconv.FormatUint(uint64(from), 10) + " can only be displayed as a built-in type")
+	}
+
+	nameComponentTypesLock.Lock()
+	defer nameComponentTypesLock.Unlock()
+	if displayAs == 0 {
+		delete(nameComponentAliases, from)
+	} else {
+		nameComponentAliases[from] = displayAs
+	}
+	return nil
+}
+
+// lookupNameComponentAlias returns the built-in type that the specified name component type is displayed as, if any.
+func lookupNameComponentAlias(tlvType uint16) (uint16, bool) {
+	nameComponentTypesLock.RLock()
+	defer nameComponentTypesLock.RUnlock()
+	displayAs, ok := nameComponentAliases[tlvType]
+	return displayAs, ok
+}
@@ -0,0 +1,154 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn_test
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	ndn "github.com/eric135/go-ndn2"
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/stretchr/testify/assert"
+)
+
+const testRecordTypeComponent = 0x7f00
+
+// recordTypeComponent is an application-defined name component holding an upper-case record type.
+type recordTypeComponent struct {
+	*ndn.BaseNameComponent
+}
+
+func (c *recordTypeComponent) String() string {
+	return "rr=" + string(c.Value())
+}
+
+func (c *recordTypeComponent) DeepCopy() ndn.NameComponent {
+	return &recordTypeComponent{ndn.NewBaseNameComponent(c.Type(), c.Value())}
+}
+
+func TestRegisterNameComponentType(t *testing.T) {
+	assert.NoError(t, ndn.RegisterNameComponentType(testRecordTypeComponent, func(value []byte) (ndn.NameComponent, error) {
+		if strings.ToUpper(string(value)) != string(value) {
+			return nil, errors.New("Record type must be upper-case")
+		}
+		return &recordTypeComponent{ndn.NewBaseNameComponent(testRecordTypeComponent, value)}, nil
+	}, nil))
+
+	// Decoding dispatches to the factory
+	component, err := ndn.DecodeNameComponent(tlv.NewBlock(testRecordTypeComponent, []byte("NS")))
+	assert.NoError(t, err)
+	assert.IsType(t, &recordTypeComponent{}, component)
+	assert.Equal(t, "rr=NS", component.String())
+	_, err = ndn.DecodeNameComponent(tlv.NewBlock(testRecordTypeComponent, []byte("ns")))
+	assert.Error(t, err)
+
+	name, err := ndn.DecodeName(tlv.NewBlock(tlv.Name, []byte{0x08, 0x02, 0x67, 0x6f, 0xfd, 0x7f, 0x00, 0x01, 0x41}))
+	assert.NoError(t, err)
+	assert.Equal(t, "/go/rr=A", name.String())
+
+	// Parsing a numeric type also dispatches to the factory
+	name, err = ndn.NameFromString("/go/32512=TXT")
+	assert.NoError(t, err)
+	assert.IsType(t, &recordTypeComponent{}, name.At(1))
+	_, err = ndn.NameFromString("/go/32512=txt")
+	assert.Error(t, err)
+
+	// Already registered
+	assert.Error(t, ndn.RegisterNameComponentType(testRecordTypeComponent, nil, nil))
+}
+
+func TestRegisterNameComponentTypeStringer(t *testing.T) {
+	assert.NoError(t, ndn.RegisterNameComponentType(0x7f01, nil, func(value []byte) string {
+		return "label=" + string(value)
+	}))
+
+	component, err := ndn.DecodeNameComponent(tlv.NewBlock(0x7f01, []byte("www")))
+	assert.NoError(t, err)
+	assert.IsType(t, &ndn.BaseNameComponent{}, component)
+	assert.Equal(t, "label=www", component.String())
+	assert.Equal(t, "label=www", ndn.NewBaseNameComponent(0x7f01, []byte("www")).String())
+
+	// Unregistered types keep the default representation
+	assert.Equal(t, "32514=www", ndn.NewBaseNameComponent(0x7f02, []byte("www")).String())
+}
+
+func TestRegisterNameComponentTypeInvalid(t *testing.T) {
+	factory := func(value []byte) (ndn.NameComponent, error) {
+		return ndn.NewGenericNameComponent(value), nil
+	}
+	for _, builtin := range []uint16{tlv.ImplicitSha256DigestComponent, tlv.ParametersSha256DigestComponent, tlv.GenericNameComponent,
+		tlv.KeywordNameComponent, tlv.SegmentNameComponent, tlv.ByteOffsetNameComponent, tlv.VersionNameComponent,
+		tlv.TimestampNameComponent, tlv.SequenceNumNameComponent} {
+		assert.Error(t, ndn.RegisterNameComponentType(builtin, factory, nil))
+	}
+	assert.Error(t, ndn.RegisterNameComponentType(0, factory, nil))
+
+	// Factories must create components of the registered type
+	assert.NoError(t, ndn.RegisterNameComponentType(0x7f03, factory, nil))
+	_, err := ndn.DecodeNameComponent(tlv.NewBlock(0x7f03, []byte("www")))
+	assert.Error(t, err)
+}
+
+func TestSetComponentAlias(t *testing.T) {
+	defer ndn.SetComponentAlias(0x7f20, 0)
+	defer ndn.SetComponentAlias(0x7f21, 0)
+
+	assert.NoError(t, ndn.SetComponentAlias(0x7f20, tlv.GenericNameComponent))
+	assert.NoError(t, ndn.SetComponentAlias(0x7f21, tlv.SegmentNameComponent))
+
+	component, err := ndn.DecodeNameComponent(tlv.NewBlock(0x7f20, []byte("www")))
+	assert.NoError(t, err)
+	assert.IsType(t, &ndn.BaseNameComponent{}, component)
+	assert.Equal(t, uint16(0x7f20), component.Type())
+	assert.Equal(t, "www", component.String())
+	assert.Equal(t, "seg=256", ndn.NewBaseNameComponent(0x7f21, []byte{0x01, 0x00}).String())
+
+	// Values that are not valid for the aliased type keep the default representation
+	assert.Equal(t, "32545=abcdefghi", ndn.NewBaseNameComponent(0x7f21, []byte("abcdefghi")).String())
+
+	// Removing the alias
+	assert.NoError(t, ndn.SetComponentAlias(0x7f20, 0))
+	assert.Equal(t, "32544=www", component.String())
+
+	// Registered stringers take precedence
+	assert.NoError(t, ndn.RegisterNameComponentType(0x7f22, nil, func(value []byte) string {
+		return "label=" + string(value)
+	}))
+	assert.NoError(t, ndn.SetComponentAlias(0x7f22, tlv.GenericNameComponent))
+	assert.Equal(t, "label=www", ndn.NewBaseNameComponent(0x7f22, []byte("www")).String())
+}
+
+func TestSetComponentAliasInvalid(t *testing.T) {
+	assert.Error(t, ndn.SetComponentAlias(0, tlv.GenericNameComponent))
+	assert.Error(t, ndn.SetComponentAlias(tlv.SegmentNameComponent, tlv.GenericNameComponent))
+	assert.Error(t, ndn.SetComponentAlias(0x7f23, 0x7f24))
+}
+
+func TestRegisterNameComponentTypeConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	errs := make([]error, 16)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = ndn.RegisterNameComponentType(0x7f10, nil, nil)
+			assert.NotEmpty(t, ndn.NewBaseNameComponent(0x7f10, []byte("www")).String())
+		}(i)
+	}
+	wg.Wait()
+
+	registered := 0
+	for _, err := range errs {
+		if err == nil {
+			registered++
+		}
+	}
+	assert.Equal(t, 1, registered)
+}
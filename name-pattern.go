@@ -0,0 +1,183 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn
+
+import (
+	"errors"
+	"strings"
+)
+
+type namePatternTokenKind int
+
+const (
+	patternLiteral namePatternTokenKind = iota
+	patternAnyOne
+	patternAnyMany
+	patternGroupStart
+	patternGroupEnd
+)
+
+// namePatternToken is an element of a compiled NamePattern.
+type namePatternToken struct {
+	kind      namePatternTokenKind
+	component NameComponent // For patternLiteral
+	group     int           // For patternGroupStart and patternGroupEnd
+}
+
+// NamePattern is a compiled pattern that names can be matched against, similar to NameRegex in ndn-cxx. A pattern is a sequence of elements separated by "/", where "<>" matches any one component, "<>*" matches zero or more components, and any other element matches a component equal to it, written as in NameFromString (e.g., "KEY" or "v=1"). One or more elements can be enclosed in parentheses to form a capture group (e.g., "/ndn/(<>/<>*)/KEY"), and groups may be nested. A leading "^" anchors the pattern to the start of the name and a trailing "$" to the end; otherwise, the pattern may match any consecutive run of components. For example, "^/ndn/<>*/KEY/<>$" matches any name under /ndn whose second-to-last component is KEY.
+type NamePattern struct {
+	pattern     string
+	tokens      []namePatternToken
+	numGroups   int
+	anchorStart bool
+	anchorEnd   bool
+}
+
+// NewNamePattern compiles a NamePattern from its string representation.
+func NewNamePattern(pattern string) (*NamePattern, error) {
+	p := new(NamePattern)
+	p.pattern = pattern
+
+	str := pattern
+	if strings.HasPrefix(str, "^") {
+		p.anchorStart = true
+		str = str[1:]
+	}
+	if strings.HasSuffix(str, "$") {
+		p.anchorEnd = true
+		str = str[:len(str)-1]
+	}
+	str = strings.TrimPrefix(str, "/")
+	if len(str) == 0 {
+		return p, nil
+	}
+
+	var openGroups []int
+	for _, elem := range strings.Split(str, "/") {
+		for strings.HasPrefix(elem, "(") {
+			openGroups = append(openGroups, p.numGroups)
+			p.tokens = append(p.tokens, namePatternToken{kind: patternGroupStart, group: p.numGroups})
+			p.numGroups++
+			elem = elem[1:]
+		}
+		closed := 0
+		for strings.HasSuffix(elem, ")") {
+			closed++
+			elem = elem[:len(elem)-1]
+		}
+
+		switch elem {
+		case "":
+			return nil, errors.New("Name pattern contains empty element")
+		case "<>":
+			p.tokens = append(p.tokens, namePatternToken{kind: patternAnyOne})
+		case "<>*":
+			p.tokens = append(p.tokens, namePatternToken{kind: patternAnyMany})
+		default:
+			if strings.ContainsAny(elem, "<>()^$") {
+				return nil, errors.New("Name pattern contains invalid element " + elem)
+			}
+			literal, err := NameFromString(elem)
+			if err != nil {
+				return nil, err
+			}
+			if literal.Size() != 1 {
+				return nil, errors.New("Name pattern contains invalid element " + elem)
+			}
+			p.tokens = append(p.tokens, namePatternToken{kind: patternLiteral, component: literal.At(0)})
+		}
+
+		for ; closed > 0; closed-- {
+			if len(openGroups) == 0 {
+				return nil, errors.New("Name pattern contains unbalanced parentheses")
+			}
+			p.tokens = append(p.tokens, namePatternToken{kind: patternGroupEnd, group: openGroups[len(openGroups)-1]})
+			openGroups = openGroups[:len(openGroups)-1]
+		}
+	}
+	if len(openGroups) > 0 {
+		return nil, errors.New("Name pattern contains unbalanced parentheses")
+	}
+	return p, nil
+}
+
+func (p *NamePattern) String() string {
+	return p.pattern
+}
+
+// NumGroups returns the number of capture groups in the pattern.
+func (p *NamePattern) NumGroups() int {
+	return p.numGroups
+}
+
+// Match returns whether the name matches the pattern.
+func (p *NamePattern) Match(name *Name) bool {
+	_, ok := p.FindCaptures(name)
+	return ok
+}
+
+// FindCaptures matches the name against the pattern, returning the components matched by each capture group in order of their opening parentheses, or false if the name does not match. Where several matches are possible, earlier <>* elements match as many components as possible.
+func (p *NamePattern) FindCaptures(name *Name) ([]*Name, bool) {
+	if name == nil {
+		return nil, false
+	}
+
+	starts := make([]int, p.numGroups)
+	ends := make([]int, p.numGroups)
+	lastStart := 0
+	if !p.anchorStart {
+		lastStart = name.Size()
+	}
+	for start := 0; start <= lastStart; start++ {
+		if p.match(name, 0, start, starts, ends) {
+			captures := make([]*Name, p.numGroups)
+			for i := range captures {
+				captures[i] = NewName()
+				for j := starts[i]; j < ends[i]; j++ {
+					captures[i].Append(name.components[j])
+				}
+			}
+			return captures, true
+		}
+	}
+	return nil, false
+}
+
+// match recursively matches the tokens starting at the specified token index against the components starting at the specified component index, recording the bounds of capture groups.
+func (p *NamePattern) match(name *Name, tokenIndex int, componentIndex int, starts []int, ends []int) bool {
+	if tokenIndex == len(p.tokens) {
+		return !p.anchorEnd || componentIndex == name.Size()
+	}
+
+	token := p.tokens[tokenIndex]
+	switch token.kind {
+	case patternLiteral:
+		return componentIndex < name.Size() && token.component.Equals(name.components[componentIndex]) &&
+			p.match(name, tokenIndex+1, componentIndex+1, starts, ends)
+	case patternAnyOne:
+		return componentIndex < name.Size() && p.match(name, tokenIndex+1, componentIndex+1, starts, ends)
+	case patternAnyMany:
+		for end := name.Size(); end >= componentIndex; end-- {
+			if p.match(name, tokenIndex+1, end, starts, ends) {
+				return true
+			}
+		}
+		return false
+	case patternGroupStart:
+		starts[token.group] = componentIndex
+		return p.match(name, tokenIndex+1, componentIndex, starts, ends)
+	default: // patternGroupEnd
+		ends[token.group] = componentIndex
+		return p.match(name, tokenIndex+1, componentIndex, starts, ends)
+	}
+}
+
+// Matches returns whether the name matches the specified pattern.
+func (n *Name) Matches(pattern *NamePattern) bool {
+	return pattern != nil && pattern.Match(n)
+}
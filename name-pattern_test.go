@@ -0,0 +1,90 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn_test
+
+import (
+	"testing"
+
+	ndn "github.com/eric135/go-ndn2"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustName(t *testing.T, str string) *ndn.Name {
+	name, err := ndn.NameFromString(str)
+	assert.NoError(t, err)
+	return name
+}
+
+func TestNamePatternMatch(t *testing.T) {
+	p, err := ndn.NewNamePattern("^/ndn/<>*/KEY/<>$")
+	assert.NoError(t, err)
+	assert.Equal(t, "^/ndn/<>*/KEY/<>$", p.String())
+	assert.True(t, p.Match(mustName(t, "/ndn/KEY/abcd")))
+	assert.True(t, p.Match(mustName(t, "/ndn/edu/ucla/KEY/abcd")))
+	assert.False(t, p.Match(mustName(t, "/ndn/edu/ucla/KEY")))
+	assert.False(t, p.Match(mustName(t, "/ndn/edu/KEY/abcd/v=1")))
+	assert.False(t, p.Match(mustName(t, "/edu/ndn/KEY/abcd")))
+	assert.False(t, p.Match(nil))
+	assert.True(t, mustName(t, "/ndn/KEY/abcd").Matches(p))
+	assert.False(t, mustName(t, "/ndn/KEY/abcd").Matches(nil))
+
+	// Typed literals
+	p, err = ndn.NewNamePattern("^/<>/v=1$")
+	assert.NoError(t, err)
+	assert.True(t, p.Match(mustName(t, "/go/v=1")))
+	assert.False(t, p.Match(mustName(t, "/go/1")))
+
+	// Unanchored patterns match anywhere in the name
+	p, err = ndn.NewNamePattern("/KEY/<>")
+	assert.NoError(t, err)
+	assert.True(t, p.Match(mustName(t, "/ndn/KEY/abcd/self/v=1")))
+	assert.False(t, p.Match(mustName(t, "/ndn/KEY")))
+	p, err = ndn.NewNamePattern("^/ndn")
+	assert.NoError(t, err)
+	assert.True(t, p.Match(mustName(t, "/ndn/edu")))
+	assert.False(t, p.Match(mustName(t, "/edu/ndn")))
+
+	// Empty patterns
+	p, err = ndn.NewNamePattern("^$")
+	assert.NoError(t, err)
+	assert.True(t, p.Match(ndn.NewName()))
+	assert.False(t, p.Match(mustName(t, "/ndn")))
+	p, err = ndn.NewNamePattern("^/<>*$")
+	assert.NoError(t, err)
+	assert.True(t, p.Match(ndn.NewName()))
+	assert.True(t, p.Match(mustName(t, "/ndn/edu")))
+}
+
+func TestNamePatternCaptures(t *testing.T) {
+	p, err := ndn.NewNamePattern("^(<>*)/KEY/(<>)/(<>/(<>))$")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, p.NumGroups())
+
+	captures, ok := p.FindCaptures(mustName(t, "/ndn/edu/KEY/abcd/self/v=1"))
+	assert.True(t, ok)
+	assert.Equal(t, 4, len(captures))
+	assert.Equal(t, "/ndn/edu", captures[0].String())
+	assert.Equal(t, "/abcd", captures[1].String())
+	assert.Equal(t, "/self/v=1", captures[2].String())
+	assert.Equal(t, "/v=1", captures[3].String())
+
+	// Empty capture
+	captures, ok = p.FindCaptures(mustName(t, "/KEY/abcd/self/v=1"))
+	assert.True(t, ok)
+	assert.Equal(t, 0, captures[0].Size())
+
+	_, ok = p.FindCaptures(mustName(t, "/ndn/KEY/abcd"))
+	assert.False(t, ok)
+}
+
+func TestNamePatternInvalid(t *testing.T) {
+	for _, pattern := range []string{"/ndn//KEY", "/(ndn", "/ndn)", "/(ndn))", "/<", "/<>+", "/a<>", "/ndn/x/^", "/seg=abc"} {
+		_, err := ndn.NewNamePattern(pattern)
+		assert.Error(t, err, pattern)
+	}
+}
@@ -0,0 +1,236 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn
+
+import (
+	"bytes"
+	"math"
+
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/eric135/go-ndn2/util"
+)
+
+// nameViewComponent locates a name component within the wire of a NameView.
+type nameViewComponent struct {
+	tlvType    uint16
+	start      int
+	valueStart int
+	end        int
+}
+
+// NameView is a read-only view of a wire-encoded name that references the underlying buffer instead of copying it, intended for lookups on the forwarding fast path.
+//
+// The buffer passed to DecodeFrom must not be modified for as long as the view, or any slice returned by it, is in use. Use Materialize to obtain an owned Name that remains valid after the buffer is reused. A NameView may be reused by calling DecodeFrom again, which does not allocate once the view has grown to hold the number of components in the name.
+type NameView struct {
+	wire       []byte
+	components []nameViewComponent
+}
+
+// DecodeFrom decodes the name at the start of the buffer into the view. Any bytes following the name are ignored. On error, the view is left empty.
+func (v *NameView) DecodeFrom(buf []byte) error {
+	v.wire = nil
+	v.components = v.components[:0]
+
+	tlvType, typeLen, err := tlv.DecodeVarNum(buf)
+	if err != nil {
+		return err
+	}
+	if tlvType != tlv.Name {
+		return tlv.ErrUnexpected
+	}
+	if typeLen == len(buf) {
+		return tlv.ErrMissingLength
+	}
+	length, lengthLen, err := tlv.DecodeVarNum(buf[typeLen:])
+	if err != nil {
+		return err
+	}
+	if length > uint64(len(buf)-typeLen-lengthLen) {
+		return tlv.ErrBufferTooShort
+	}
+	end := typeLen + lengthLen + int(length)
+	maxComponents, maxSize := NameLimits()
+	if end > maxSize {
+		return util.ErrTooLong
+	}
+
+	for pos := typeLen + lengthLen; pos < end; {
+		component, err := decodeNameViewComponent(buf[:end], pos)
+		if err != nil {
+			v.components = v.components[:0]
+			return err
+		}
+		if len(v.components) == maxComponents {
+			v.components = v.components[:0]
+			return util.ErrTooLong
+		}
+		v.components = append(v.components, component)
+		pos = component.end
+	}
+	v.wire = buf[:end]
+	return nil
+}
+
+// decodeNameViewComponent locates and validates the name component starting at the specified position in the buffer.
+func decodeNameViewComponent(buf []byte, pos int) (nameViewComponent, error) {
+	tlvType, typeLen, err := tlv.DecodeVarNum(buf[pos:])
+	if err != nil {
+		return nameViewComponent{}, err
+	}
+	if tlvType > math.MaxUint16 {
+		return nameViewComponent{}, util.ErrOutOfRange
+	}
+	if pos+typeLen == len(buf) {
+		return nameViewComponent{}, tlv.ErrMissingLength
+	}
+	length, lengthLen, err := tlv.DecodeVarNum(buf[pos+typeLen:])
+	if err != nil {
+		return nameViewComponent{}, err
+	}
+	if length > uint64(len(buf)-pos-typeLen-lengthLen) {
+		return nameViewComponent{}, tlv.ErrBufferTooShort
+	}
+
+	c := nameViewComponent{
+		tlvType:    uint16(tlvType),
+		start:      pos,
+		valueStart: pos + typeLen + lengthLen,
+		end:        pos + typeLen + lengthLen + int(length),
+	}
+	value := buf[c.valueStart:c.end]
+	if len(value) == 0 {
+		return nameViewComponent{}, tlv.ErrBufferTooShort
+	}
+
+	// Apply the same validity rules as DecodeNameComponent
+	switch c.tlvType {
+	case tlv.ImplicitSha256DigestComponent, tlv.ParametersSha256DigestComponent:
+		if len(value) != 32 {
+			return nameViewComponent{}, util.ErrDecodeNameComponent
+		}
+	case tlv.SegmentNameComponent, tlv.ByteOffsetNameComponent, tlv.VersionNameComponent, tlv.TimestampNameComponent, tlv.SequenceNumNameComponent:
+		if _, err := tlv.DecodeNNI(value); err != nil {
+			return nameViewComponent{}, err
+		}
+	}
+	return c, nil
+}
+
+// String returns the URI representation of the name. This allocates and is intended for debugging.
+func (v *NameView) String() string {
+	n := v.Materialize()
+	if n == nil {
+		return "/"
+	}
+	return n.String()
+}
+
+// Size returns the number of components in the name.
+func (v *NameView) Size() int {
+	return len(v.components)
+}
+
+// Wire returns the wire encoding of the name, which references the underlying buffer. Converting it to a string inside a map index expression (m[string(v.Wire())]) does not allocate, making it suitable as a lookup key.
+func (v *NameView) Wire() []byte {
+	return v.wire
+}
+
+// ComponentType returns the TLV type of the component at the specified index, or 0 if the index is out of range.
+func (v *NameView) ComponentType(index int) uint16 {
+	if index < 0 || index >= len(v.components) {
+		return 0
+	}
+	return v.components[index].tlvType
+}
+
+// ComponentValue returns the value of the component at the specified index, which references the underlying buffer, or nil if the index is out of range.
+func (v *NameView) ComponentValue(index int) []byte {
+	if index < 0 || index >= len(v.components) {
+		return nil
+	}
+	c := v.components[index]
+	return v.wire[c.valueStart:c.end:c.end]
+}
+
+// ComponentWire returns the wire encoding of the component at the specified index, which references the underlying buffer, or nil if the index is out of range.
+func (v *NameView) ComponentWire(index int) []byte {
+	if index < 0 || index >= len(v.components) {
+		return nil
+	}
+	c := v.components[index]
+	return v.wire[c.start:c.end:c.end]
+}
+
+// Equals returns whether the view and the specified name are the same name.
+func (v *NameView) Equals(other *Name) bool {
+	if other == nil || len(v.components) != other.Size() {
+		return false
+	}
+	for i, c := range v.components {
+		component := other.At(i)
+		if c.tlvType != component.Type() || !bytes.Equal(v.wire[c.valueStart:c.end], componentValueRef(component)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Materialize returns an owned copy of the name that does not reference the underlying buffer, or nil if the view is empty because it has not been successfully decoded.
+func (v *NameView) Materialize() *Name {
+	n, err := v.toName(tlv.DecodeOptions{})
+	if err != nil {
+		return nil
+	}
+	return n
+}
+
+// toName constructs a Name directly from the components located in the view, checking their encoding according to the specified options, and caches a copy of its wire.
+func (v *NameView) toName(opts tlv.DecodeOptions) (*Name, error) {
+	if v.wire == nil {
+		return nil, util.ErrNonExistent
+	}
+
+	headerLen := len(v.wire)
+	if len(v.components) > 0 {
+		headerLen = v.components[0].start
+	}
+	canonical := headerLen == tlv.SizeOfVarNumber(tlv.Name)+tlv.SizeOfVarNumber(uint64(len(v.wire)-headerLen))
+	if opts.Strict && !canonical {
+		return nil, util.ErrOutOfRange
+	}
+
+	n := new(Name)
+	n.components = make([]NameComponent, 0, len(v.components))
+	for _, c := range v.components {
+		value := v.wire[c.valueStart:c.end]
+		minimalHeader := c.valueStart-c.start == tlv.SizeOfVarNumber(uint64(c.tlvType))+tlv.SizeOfVarNumber(uint64(len(value)))
+		if opts.Strict && !minimalHeader {
+			return nil, util.ErrOutOfRange
+		}
+		component, err := decodeNameComponent(uint32(c.tlvType), value, opts)
+		if err != nil {
+			return nil, err
+		}
+		n.components = append(n.components, component)
+		n.valueSize += componentSize(component)
+		canonical = canonical && minimalHeader && len(componentValueRef(component)) == len(value)
+	}
+
+	if canonical {
+		// The minimal encoding of the value is identical to the wire, so there is no need to decode it again
+		n.wire = tlv.NewBlock(tlv.Name, v.wire[headerLen:])
+	} else {
+		wire, _, err := tlv.DecodeBlock(v.wire)
+		if err != nil {
+			return nil, err
+		}
+		n.wire = wire
+	}
+	n.canonicalWire = canonical
+	return n, nil
+}
@@ -0,0 +1,128 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn_test
+
+import (
+	"testing"
+
+	ndn "github.com/eric135/go-ndn2"
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/stretchr/testify/assert"
+)
+
+func nameViewTestWire(t testing.TB) []byte {
+	name, err := ndn.NameFromString("/go/ndn/v=3/seg=10")
+	assert.NoError(t, err)
+	wire, err := name.Encode().Wire()
+	assert.NoError(t, err)
+	return wire
+}
+
+func TestNameViewDecodeFrom(t *testing.T) {
+	wire := nameViewTestWire(t)
+	buf := append(append([]byte{}, wire...), tlv.Nonce, 0x04, 0x01, 0x02, 0x03, 0x04)
+
+	var view ndn.NameView
+	assert.NoError(t, view.DecodeFrom(buf))
+	assert.Equal(t, 4, view.Size())
+	assert.Equal(t, wire, view.Wire())
+	assert.Equal(t, uint16(tlv.GenericNameComponent), view.ComponentType(0))
+	assert.Equal(t, []byte("ndn"), view.ComponentValue(1))
+	assert.Equal(t, uint16(tlv.VersionNameComponent), view.ComponentType(2))
+	assert.Equal(t, []byte{tlv.SegmentNameComponent, 0x01, 0x0a}, view.ComponentWire(3))
+	assert.Equal(t, uint16(0), view.ComponentType(4))
+	assert.Nil(t, view.ComponentValue(-1))
+	assert.Nil(t, view.ComponentWire(4))
+	assert.Equal(t, "/go/ndn/v=3/seg=10", view.String())
+
+	// References the underlying buffer without copying
+	buf[4] = 'G'
+	assert.Equal(t, []byte("Go"), view.ComponentValue(0))
+
+	// Materialized name is independent of the buffer
+	materialized := view.Materialize()
+	assert.NotNil(t, materialized)
+	assert.Equal(t, "/Go/ndn/v=3/seg=10", materialized.String())
+	buf[4] = 'g'
+	assert.Equal(t, "/Go/ndn/v=3/seg=10", materialized.String())
+
+	other, err := ndn.NameFromString("/go/ndn/v=3/seg=10")
+	assert.NoError(t, err)
+	assert.True(t, view.Equals(other))
+	other, err = ndn.NameFromString("/go/ndn/v=3/seg=11")
+	assert.NoError(t, err)
+	assert.False(t, view.Equals(other))
+	assert.False(t, view.Equals(other.Prefix(3)))
+	assert.False(t, view.Equals(nil))
+
+	// Empty name
+	assert.NoError(t, view.DecodeFrom([]byte{tlv.Name, 0x00}))
+	assert.Equal(t, 0, view.Size())
+	assert.True(t, view.Equals(ndn.NewName()))
+}
+
+func TestNameViewDecodeFromInvalid(t *testing.T) {
+	var view ndn.NameView
+	assert.Error(t, view.DecodeFrom(nil))
+	assert.Equal(t, tlv.ErrUnexpected, view.DecodeFrom([]byte{tlv.Interest, 0x00}))
+	assert.Error(t, view.DecodeFrom([]byte{tlv.Name}))
+	assert.Equal(t, tlv.ErrBufferTooShort, view.DecodeFrom([]byte{tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67}))
+
+	// Truncated component
+	assert.Error(t, view.DecodeFrom([]byte{tlv.Name, 0x03, tlv.GenericNameComponent, 0x02, 0x67}))
+	// Empty component
+	assert.Error(t, view.DecodeFrom([]byte{tlv.Name, 0x02, tlv.GenericNameComponent, 0x00}))
+	// Digest component of the wrong length
+	assert.Error(t, view.DecodeFrom([]byte{tlv.Name, 0x03, tlv.ImplicitSha256DigestComponent, 0x01, 0x00}))
+	// Typed number component of invalid length
+	assert.Error(t, view.DecodeFrom([]byte{tlv.Name, 0x05, tlv.SegmentNameComponent, 0x03, 0x01, 0x02, 0x03}))
+
+	// Failed decoding leaves the view empty
+	assert.NoError(t, view.DecodeFrom(nameViewTestWire(t)))
+	assert.Error(t, view.DecodeFrom([]byte{tlv.Name, 0x02, tlv.GenericNameComponent, 0x00}))
+	assert.Equal(t, 0, view.Size())
+	assert.Nil(t, view.Wire())
+	assert.Nil(t, view.Materialize())
+}
+
+func TestNameViewDecodeFromAllocs(t *testing.T) {
+	wire := nameViewTestWire(t)
+	var view ndn.NameView
+	assert.NoError(t, view.DecodeFrom(wire))
+	allocs := testing.AllocsPerRun(100, func() {
+		view.DecodeFrom(wire)
+	})
+	assert.Equal(t, 0.0, allocs)
+}
+
+func BenchmarkDecodeName(b *testing.B) {
+	wire := nameViewTestWire(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		block, _, err := tlv.DecodeBlock(wire)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := ndn.DecodeName(block); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkNameViewDecodeFrom(b *testing.B) {
+	wire := nameViewTestWire(b)
+	var view ndn.NameView
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := view.DecodeFrom(wire); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
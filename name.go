@@ -9,12 +9,16 @@ package ndn
 
 import (
 	"bytes"
-	"encoding/binary"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"io"
 	"math"
+	"net/url"
+	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/eric135/go-ndn2/tlv"
 	"github.com/eric135/go-ndn2/util"
@@ -27,6 +31,8 @@ type NameComponent interface {
 	Type() uint16
 	Value() []byte
 	Encode() *tlv.Block
+	Equals(other NameComponent) bool
+	Compare(other NameComponent) int
 }
 
 // DecodeNameComponent decodes a name component from the wire.
@@ -34,37 +40,68 @@ func DecodeNameComponent(wire *tlv.Block) (NameComponent, error) {
 	if wire == nil {
 		return nil, util.ErrNonExistent
 	}
-	if len(wire.Value()) == 0 {
+	return decodeNameComponent(wire.Type(), wire.Value(), tlv.DecodeOptions{})
+}
+
+// decodeNameComponent decodes a name component from its TLV type and value, checking the encoding of numeric components according to the specified options.
+func decodeNameComponent(tlvType uint32, value []byte, opts tlv.DecodeOptions) (NameComponent, error) {
+	if len(value) == 0 {
 		return nil, tlv.ErrBufferTooShort
 	}
 
 	var n NameComponent
 	var err error
-	switch wire.Type() {
+	switch tlvType {
 	case tlv.ImplicitSha256DigestComponent:
-		n = NewImplicitSha256DigestComponent(wire.Value())
+		if len(value) == 32 {
+			n = NewImplicitSha256DigestComponent(value)
+		}
 	case tlv.ParametersSha256DigestComponent:
-		n = NewParametersSha256DigestComponent(wire.Value())
+		if len(value) == 32 {
+			n = NewParametersSha256DigestComponent(value)
+		}
 	case tlv.GenericNameComponent:
-		n = NewGenericNameComponent(wire.Value())
+		n = NewGenericNameComponent(value)
 	case tlv.KeywordNameComponent:
-		n = NewKeywordNameComponent(wire.Value())
+		n = NewKeywordNameComponent(value)
 	case tlv.SegmentNameComponent:
-		n = NewSegmentNameComponent(binary.BigEndian.Uint64(wire.Value()))
+		var number uint64
+		if number, err = opts.DecodeNNI(value); err == nil {
+			n = NewSegmentNameComponent(number)
+		}
 	case tlv.ByteOffsetNameComponent:
-		n = NewByteOffsetNameComponent(binary.BigEndian.Uint64(wire.Value()))
+		var number uint64
+		if number, err = opts.DecodeNNI(value); err == nil {
+			n = NewByteOffsetNameComponent(number)
+		}
 	case tlv.VersionNameComponent:
-		n = NewVersionNameComponent(binary.BigEndian.Uint64(wire.Value()))
+		var number uint64
+		if number, err = opts.DecodeNNI(value); err == nil {
+			n = NewVersionNameComponent(number)
+		}
 	case tlv.TimestampNameComponent:
-		n = NewTimestampNameComponent(binary.BigEndian.Uint64(wire.Value()))
+		var number uint64
+		if number, err = opts.DecodeNNI(value); err == nil {
+			n = NewTimestampNameComponent(number)
+		}
 	case tlv.SequenceNumNameComponent:
-		n = NewSequenceNumNameComponent(binary.BigEndian.Uint64(wire.Value()))
+		var number uint64
+		if number, err = opts.DecodeNNI(value); err == nil {
+			n = NewSequenceNumNameComponent(number)
+		}
 	default:
-		if wire.Type() > math.MaxUint16 {
+		if tlvType > math.MaxUint16 {
 			n = nil
 			err = util.ErrOutOfRange
+		} else if registered, ok := lookupNameComponentType(uint16(tlvType)); ok && registered.factory != nil {
+			if n, err = registered.factory(value); err != nil {
+				return nil, err
+			}
+			if n != nil && n.Type() != uint16(tlvType) {
+				n = nil
+			}
 		} else {
-			n = NewBaseNameComponent(uint16(wire.Type()), wire.Value())
+			n = NewBaseNameComponent(uint16(tlvType), value)
 		}
 	}
 
@@ -83,6 +120,8 @@ type BaseNameComponent struct {
 	tlvType uint16
 	value   []byte
 	wire    *tlv.Block
+	// modified indicates that the value has changed since the component was last encoded as part of a name
+	modified bool
 }
 
 // NewBaseNameComponent creates a name component of an arbitrary type.
@@ -99,6 +138,14 @@ func NewBaseNameComponent(tlvType uint16, value []byte) *BaseNameComponent {
 }
 
 func (n *BaseNameComponent) String() string {
+	if registered, ok := lookupNameComponentType(n.tlvType); ok && registered.stringer != nil {
+		return registered.stringer(n.value)
+	}
+	if displayAs, ok := lookupNameComponentAlias(n.tlvType); ok {
+		if alias, err := decodeNameComponent(uint32(displayAs), n.value, tlv.DecodeOptions{}); err == nil {
+			return alias.String()
+		}
+	}
 	return strconv.FormatUint(uint64(n.tlvType), 10) + "=" + string(n.value)
 }
 
@@ -116,11 +163,77 @@ func (n *BaseNameComponent) Type() uint16 {
 	return n.tlvType
 }
 
-// Value returns the TLV value of the name component.
+// Value returns a copy of the TLV value of the name component.
 func (n *BaseNameComponent) Value() []byte {
+	value := make([]byte, len(n.value))
+	copy(value, n.value)
+	return value
+}
+
+// valueRef returns the TLV value of the name component without copying it. The returned slice must not be modified.
+func (n *BaseNameComponent) valueRef() []byte {
 	return n.value
 }
 
+// componentValueRef returns the TLV value of the specified name component, avoiding a copy if the component is implemented in this package.
+func componentValueRef(component NameComponent) []byte {
+	if ref, ok := component.(interface{ valueRef() []byte }); ok {
+		return ref.valueRef()
+	}
+	return component.Value()
+}
+
+// Equals returns whether the specified name component has the same type and value as this name component.
+func (n *BaseNameComponent) Equals(other NameComponent) bool {
+	return other != nil && n.tlvType == other.Type() && bytes.Equal(n.value, componentValueRef(other))
+}
+
+// Compare returns the canonical order of this name component against the specified other name component, ordering by type, then value length, then value.
+func (n *BaseNameComponent) Compare(other NameComponent) int {
+	if n.tlvType < other.Type() {
+		return -1
+	} else if n.tlvType > other.Type() {
+		return 1
+	}
+	otherValue := componentValueRef(other)
+	if len(n.value) < len(otherValue) {
+		return -1
+	} else if len(n.value) > len(otherValue) {
+		return 1
+	}
+	return bytes.Compare(n.value, otherValue)
+}
+
+// nniValue returns the value of the name component decoded as a non-negative integer, or 0 if it is not a valid NNI.
+func (n *BaseNameComponent) nniValue() uint64 {
+	value, err := tlv.DecodeNNI(n.value)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// setWire sets the cached wire of the name component to a block already known to be its minimal encoding.
+func (n *BaseNameComponent) setWire(wire *tlv.Block) {
+	n.wire = wire
+}
+
+// invalidate discards the cached wire after the value of the name component is changed.
+func (n *BaseNameComponent) invalidate() {
+	n.wire = nil
+	n.modified = true
+}
+
+// isModified returns whether the value of the name component has changed since it was last encoded as part of a name.
+func (n *BaseNameComponent) isModified() bool {
+	return n.modified
+}
+
+// clearModified marks the name component as unmodified after it is encoded as part of a name.
+func (n *BaseNameComponent) clearModified() {
+	n.modified = false
+}
+
 // Encode encodes the name component into a block.
 func (n *BaseNameComponent) Encode() *tlv.Block {
 	if n.wire == nil {
@@ -168,7 +281,7 @@ func (n *ImplicitSha256DigestComponent) SetValue(value []byte) error {
 	}
 	n.value = make([]byte, 32)
 	copy(n.value, value)
-	n.wire = nil
+	n.invalidate()
 	return nil
 }
 
@@ -210,7 +323,7 @@ func (n *ParametersSha256DigestComponent) SetValue(value []byte) error {
 	}
 	n.value = make([]byte, 32)
 	copy(n.value, value)
-	n.wire = nil
+	n.invalidate()
 	return nil
 }
 
@@ -249,7 +362,7 @@ func (n *GenericNameComponent) DeepCopy() NameComponent {
 func (n *GenericNameComponent) SetValue(value []byte) {
 	n.value = make([]byte, len(value))
 	copy(n.value, value)
-	n.wire = nil
+	n.invalidate()
 }
 
 ///////////////////////
@@ -275,7 +388,7 @@ func NewKeywordNameComponent(value []byte) *KeywordNameComponent {
 }
 
 func (n *KeywordNameComponent) String() string {
-	return string(n.value)
+	return strconv.FormatUint(uint64(n.tlvType), 10) + "=" + string(n.value)
 }
 
 // DeepCopy creates a deep copy of the name component.
@@ -287,7 +400,7 @@ func (n *KeywordNameComponent) DeepCopy() NameComponent {
 func (n *KeywordNameComponent) SetValue(value []byte) {
 	n.value = make([]byte, len(value))
 	copy(n.value, value)
-	n.wire = nil
+	n.invalidate()
 }
 
 ///////////////////////
@@ -303,13 +416,12 @@ type SegmentNameComponent struct {
 func NewSegmentNameComponent(value uint64) *SegmentNameComponent {
 	n := new(SegmentNameComponent)
 	n.tlvType = tlv.SegmentNameComponent
-	n.value = make([]byte, 8)
-	binary.BigEndian.PutUint64(n.value, value)
+	n.value = tlv.EncodeNNI(value)
 	return n
 }
 
 func (n *SegmentNameComponent) String() string {
-	return "seg=" + strconv.FormatUint(binary.BigEndian.Uint64(n.value), 10)
+	return "seg=" + strconv.FormatUint(n.nniValue(), 10)
 }
 
 // DeepCopy creates a deep copy of the name component.
@@ -317,15 +429,10 @@ func (n *SegmentNameComponent) DeepCopy() NameComponent {
 	return &SegmentNameComponent{BaseNameComponent: *n.BaseNameComponent.DeepCopy().(*BaseNameComponent)}
 }
 
-// Encode encodes a SegmentNameComponent.
-func (n *SegmentNameComponent) Encode() *tlv.Block {
-	return tlv.EncodeNNIBlock(uint32(n.tlvType), binary.BigEndian.Uint64(n.value))
-}
-
-// SetValue sets the value of a KeywordNameComponent.
+// SetValue sets the value of a SegmentNameComponent.
 func (n *SegmentNameComponent) SetValue(value uint64) {
-	binary.BigEndian.PutUint64(n.value, value)
-	n.wire = nil
+	n.value = tlv.EncodeNNI(value)
+	n.invalidate()
 }
 
 //////////////////////////
@@ -341,13 +448,12 @@ type ByteOffsetNameComponent struct {
 func NewByteOffsetNameComponent(value uint64) *ByteOffsetNameComponent {
 	n := new(ByteOffsetNameComponent)
 	n.tlvType = tlv.ByteOffsetNameComponent
-	n.value = make([]byte, 8)
-	binary.BigEndian.PutUint64(n.value, value)
+	n.value = tlv.EncodeNNI(value)
 	return n
 }
 
 func (n *ByteOffsetNameComponent) String() string {
-	return "off=" + strconv.FormatUint(binary.BigEndian.Uint64(n.value), 10)
+	return "off=" + strconv.FormatUint(n.nniValue(), 10)
 }
 
 // DeepCopy creates a deep copy of the name component.
@@ -355,15 +461,10 @@ func (n *ByteOffsetNameComponent) DeepCopy() NameComponent {
 	return &ByteOffsetNameComponent{BaseNameComponent: *n.BaseNameComponent.DeepCopy().(*BaseNameComponent)}
 }
 
-// Encode encodes a ByteOffsetNameComponent.
-func (n *ByteOffsetNameComponent) Encode() *tlv.Block {
-	return tlv.EncodeNNIBlock(uint32(n.tlvType), binary.BigEndian.Uint64(n.value))
-}
-
 // SetValue sets the value of a ByteOffsetNameComponent.
 func (n *ByteOffsetNameComponent) SetValue(value uint64) {
-	binary.BigEndian.PutUint64(n.value, value)
-	n.wire = nil
+	n.value = tlv.EncodeNNI(value)
+	n.invalidate()
 }
 
 ///////////////////////
@@ -379,13 +480,12 @@ type VersionNameComponent struct {
 func NewVersionNameComponent(value uint64) *VersionNameComponent {
 	n := new(VersionNameComponent)
 	n.tlvType = tlv.VersionNameComponent
-	n.value = make([]byte, 8)
-	binary.BigEndian.PutUint64(n.value, value)
+	n.value = tlv.EncodeNNI(value)
 	return n
 }
 
 func (n *VersionNameComponent) String() string {
-	return "v=" + strconv.FormatUint(binary.BigEndian.Uint64(n.value), 10)
+	return "v=" + strconv.FormatUint(n.nniValue(), 10)
 }
 
 // DeepCopy creates a deep copy of the name component.
@@ -393,15 +493,10 @@ func (n *VersionNameComponent) DeepCopy() NameComponent {
 	return &VersionNameComponent{BaseNameComponent: *n.BaseNameComponent.DeepCopy().(*BaseNameComponent)}
 }
 
-// Encode encodes a VersionNameComponent.
-func (n *VersionNameComponent) Encode() *tlv.Block {
-	return tlv.EncodeNNIBlock(uint32(n.tlvType), binary.BigEndian.Uint64(n.value))
-}
-
 // SetValue sets the value of a VersionNameComponent.
 func (n *VersionNameComponent) SetValue(value uint64) {
-	binary.BigEndian.PutUint64(n.value, value)
-	n.wire = nil
+	n.value = tlv.EncodeNNI(value)
+	n.invalidate()
 }
 
 /////////////////////////
@@ -417,13 +512,12 @@ type TimestampNameComponent struct {
 func NewTimestampNameComponent(value uint64) *TimestampNameComponent {
 	n := new(TimestampNameComponent)
 	n.tlvType = tlv.TimestampNameComponent
-	n.value = make([]byte, 8)
-	binary.BigEndian.PutUint64(n.value, value)
+	n.value = tlv.EncodeNNI(value)
 	return n
 }
 
 func (n *TimestampNameComponent) String() string {
-	return "t=" + strconv.FormatUint(binary.BigEndian.Uint64(n.value), 10)
+	return "t=" + strconv.FormatUint(n.nniValue(), 10)
 }
 
 // DeepCopy creates a deep copy of the name component.
@@ -431,15 +525,10 @@ func (n *TimestampNameComponent) DeepCopy() NameComponent {
 	return &TimestampNameComponent{BaseNameComponent: *n.BaseNameComponent.DeepCopy().(*BaseNameComponent)}
 }
 
-// Encode encodes a TimestampNameComponent.
-func (n *TimestampNameComponent) Encode() *tlv.Block {
-	return tlv.EncodeNNIBlock(uint32(n.tlvType), binary.BigEndian.Uint64(n.value))
-}
-
 // SetValue sets the value of a TimestampNameComponent.
 func (n *TimestampNameComponent) SetValue(value uint64) {
-	binary.BigEndian.PutUint64(n.value, value)
-	n.wire = nil
+	n.value = tlv.EncodeNNI(value)
+	n.invalidate()
 }
 
 ///////////////////////////
@@ -455,13 +544,12 @@ type SequenceNumNameComponent struct {
 func NewSequenceNumNameComponent(value uint64) *SequenceNumNameComponent {
 	n := new(SequenceNumNameComponent)
 	n.tlvType = tlv.SequenceNumNameComponent
-	n.value = make([]byte, 8)
-	binary.BigEndian.PutUint64(n.value, value)
+	n.value = tlv.EncodeNNI(value)
 	return n
 }
 
 func (n *SequenceNumNameComponent) String() string {
-	return "seq=" + strconv.FormatUint(binary.BigEndian.Uint64(n.value), 10)
+	return "seq=" + strconv.FormatUint(n.nniValue(), 10)
 }
 
 // DeepCopy creates a deep copy of the name component.
@@ -469,25 +557,58 @@ func (n *SequenceNumNameComponent) DeepCopy() NameComponent {
 	return &SequenceNumNameComponent{BaseNameComponent: *n.BaseNameComponent.DeepCopy().(*BaseNameComponent)}
 }
 
-// Encode encodes a SequenceNumNameComponent.
-func (n *SequenceNumNameComponent) Encode() *tlv.Block {
-	return tlv.EncodeNNIBlock(uint32(n.tlvType), binary.BigEndian.Uint64(n.value))
-}
-
 // SetValue sets the value of a SequenceNumNameComponent.
 func (n *SequenceNumNameComponent) SetValue(value uint64) {
-	binary.BigEndian.PutUint64(n.value, value)
-	n.wire = nil
+	n.value = tlv.EncodeNNI(value)
+	n.invalidate()
 }
 
 ///////
 // Name
 ///////
 
+// Default limits on the size of names, which guard against adversarially large names. The limits in effect are returned by NameLimits and can be changed with SetNameLimits.
+const (
+	// DefaultMaxNameComponents is the default maximum number of components in a name.
+	DefaultMaxNameComponents = 1024
+	// DefaultMaxNameSize is the default maximum size of the wire encoding of a name, in bytes.
+	DefaultMaxNameSize = 8800
+)
+
+var (
+	maxNameComponents = DefaultMaxNameComponents
+	maxNameSize       = DefaultMaxNameSize
+	nameLimitsLock    sync.RWMutex
+)
+
+// NameLimits returns the maximum number of components in a name and the maximum size of the wire encoding of a name, in bytes. These are enforced by DecodeName, NameView, NameBuilder, TryAppend, AppendName, AppendPath, and Insert.
+func NameLimits() (maxComponents int, maxSize int) {
+	nameLimitsLock.RLock()
+	defer nameLimitsLock.RUnlock()
+	return maxNameComponents, maxNameSize
+}
+
+// SetNameLimits changes the limits returned by NameLimits, returning util.ErrOutOfRange if either is not positive. It is safe to call while names are being decoded or built on other goroutines, although existing names are not re-checked against the new limits.
+func SetNameLimits(maxComponents int, maxSize int) error {
+	if maxComponents <= 0 || maxSize <= 0 {
+		return util.ErrOutOfRange
+	}
+
+	nameLimitsLock.Lock()
+	defer nameLimitsLock.Unlock()
+	maxNameComponents = maxComponents
+	maxNameSize = maxSize
+	return nil
+}
+
 // Name represents an NDN name.
 type Name struct {
 	components []NameComponent
-	wire       *tlv.Block
+	// valueSize is the running total of the encoded sizes of the components, which is the TLV-LENGTH of the name
+	valueSize int
+	wire      *tlv.Block
+	// canonicalWire indicates that the cached wire is identical to the minimal re-encoding of the components
+	canonicalWire bool
 }
 
 // NewName constructs an empty name.
@@ -496,83 +617,220 @@ func NewName() *Name {
 	return n
 }
 
-// NameFromString decodes a name from a string.
+// NameParseError describes an error encountered while parsing the string representation of a name, including where in the string it occurred.
+type NameParseError struct {
+	// Index is the index of the name component containing the error.
+	Index int
+	// Offset is the byte offset of the problematic text within the parsed string.
+	Offset int
+	// Text is the problematic text, such as an invalid percent-escape or the entire component.
+	Text string
+	// Err describes the error.
+	Err error
+}
+
+func (e *NameParseError) Error() string {
+	return e.Err.Error() + " in component " + strconv.Itoa(e.Index) + " at offset " + strconv.Itoa(e.Offset) + ": " + strconv.Quote(e.Text)
+}
+
+// Unwrap returns the underlying error.
+func (e *NameParseError) Unwrap() error {
+	return e.Err
+}
+
+// NameFromString decodes a name from a string. If the string is invalid, a *NameParseError is returned that identifies the offending component and its position in the string.
 func NameFromString(str string) (*Name, error) {
 	n := new(Name)
 
-	if len(str) == 0 {
+	offset := 0
+	if strings.HasPrefix(str, "/") {
+		offset = 1
+	}
+	if offset == len(str) {
 		// Empty name
 		return n, nil
 	}
 
-	components := strings.Split(str, "/")[1:] // Skip first since empty
-	for _, component := range components {
-		var c NameComponent
-		if strings.Contains(component, "=") {
-			componentSplit := strings.Split(component, "=")
-			if len(componentSplit) != 2 {
-				return nil, errors.New("Name component has extraneous =")
-			}
-			switch componentSplit[0] {
-			case "sha256digest":
-				digest, err := hex.DecodeString(componentSplit[1])
-				if err != nil {
-					return nil, errors.New("ImplicitSha256DigestComponent is not a hex string")
-				}
-				c = NewImplicitSha256DigestComponent(digest)
-			case "params-sha256":
-				digest, err := hex.DecodeString(componentSplit[1])
-				if err != nil {
-					return nil, errors.New("ParametersSha256DigestComponent is not a hex string")
-				}
-				c = NewParametersSha256DigestComponent(digest)
-			case "8":
-				c = NewGenericNameComponent([]byte(componentSplit[1]))
-			case "seg":
-				seg, err := strconv.ParseUint(componentSplit[1], 10, 64)
-				if err != nil {
-					return nil, errors.New("SegmentNameComponent is not a decimal string")
-				}
-				c = NewSegmentNameComponent(seg)
-			case "off":
-				off, err := strconv.ParseUint(componentSplit[1], 10, 64)
-				if err != nil {
-					return nil, errors.New("ByteOffsetNameComponent is not a decimal string")
-				}
-				c = NewByteOffsetNameComponent(off)
-			case "v":
-				v, err := strconv.ParseUint(componentSplit[1], 10, 64)
-				if err != nil {
-					return nil, errors.New("VersionNameComponent is not a decimal string")
-				}
-				c = NewByteOffsetNameComponent(v)
-			case "t":
-				t, err := strconv.ParseUint(componentSplit[1], 10, 64)
-				if err != nil {
-					return nil, errors.New("TimestampNameComponent is not a decimal string")
-				}
-				c = NewTimestampNameComponent(t)
-			case "seq":
-				seq, err := strconv.ParseUint(componentSplit[1], 10, 64)
-				if err != nil {
-					return nil, errors.New("SequenceNumNameComponent is not a decimal string")
-				}
-				c = NewSequenceNumNameComponent(seq)
-			default:
-				return nil, errors.New("Unknown name component " + componentSplit[0])
-			}
-		} else {
-			// Treat as GenericNameComponent
-			c = NewGenericNameComponent([]byte(component))
+	for i, component := range strings.Split(str[offset:], "/") {
+		c, err := componentFromString(component)
+		if err != nil {
+			err.Index = i
+			err.Offset += offset
+			return nil, err
 		}
 		n.Append(c)
+		offset += len(component) + 1
 	}
 
 	return n, nil
 }
 
-// DecodeName decodes a name from wire encoding.,
+// componentFromString decodes a name component from its string representation. The Offset of a returned error is relative to the start of the component and its Index is not set.
+func componentFromString(component string) (NameComponent, *NameParseError) {
+	if len(component) == 0 {
+		return nil, &NameParseError{Err: errors.New("Name contains empty component")}
+	}
+	invalid := func(message string) *NameParseError {
+		return &NameParseError{Text: component, Err: errors.New(message)}
+	}
+
+	if !strings.Contains(component, "=") {
+		// Treat as GenericNameComponent
+		value, err := unescapeComponentValue(component)
+		if err != nil {
+			return nil, err
+		}
+		return NewGenericNameComponent(value), nil
+	}
+
+	componentSplit := strings.SplitN(component, "=", 2)
+	switch componentSplit[0] {
+	case "sha256digest":
+		digest, err := hex.DecodeString(componentSplit[1])
+		if err != nil || len(digest) != 32 {
+			return nil, invalid("ImplicitSha256DigestComponent is not a 32-byte hex string")
+		}
+		return NewImplicitSha256DigestComponent(digest), nil
+	case "params-sha256":
+		digest, err := hex.DecodeString(componentSplit[1])
+		if err != nil || len(digest) != 32 {
+			return nil, invalid("ParametersSha256DigestComponent is not a 32-byte hex string")
+		}
+		return NewParametersSha256DigestComponent(digest), nil
+	case "seg":
+		seg, err := strconv.ParseUint(componentSplit[1], 10, 64)
+		if err != nil {
+			return nil, invalid("SegmentNameComponent is not a decimal string")
+		}
+		return NewSegmentNameComponent(seg), nil
+	case "off":
+		off, err := strconv.ParseUint(componentSplit[1], 10, 64)
+		if err != nil {
+			return nil, invalid("ByteOffsetNameComponent is not a decimal string")
+		}
+		return NewByteOffsetNameComponent(off), nil
+	case "v":
+		v, err := strconv.ParseUint(componentSplit[1], 10, 64)
+		if err != nil {
+			return nil, invalid("VersionNameComponent is not a decimal string")
+		}
+		return NewVersionNameComponent(v), nil
+	case "t":
+		t, err := strconv.ParseUint(componentSplit[1], 10, 64)
+		if err != nil {
+			return nil, invalid("TimestampNameComponent is not a decimal string")
+		}
+		return NewTimestampNameComponent(t), nil
+	case "seq":
+		seq, err := strconv.ParseUint(componentSplit[1], 10, 64)
+		if err != nil {
+			return nil, invalid("SequenceNumNameComponent is not a decimal string")
+		}
+		return NewSequenceNumNameComponent(seq), nil
+	}
+
+	tlvType, err := strconv.ParseUint(componentSplit[0], 10, 16)
+	if err != nil {
+		return nil, &NameParseError{Text: componentSplit[0], Err: errors.New("Unknown name component type")}
+	}
+	value, parseErr := unescapeComponentValue(componentSplit[1])
+	if parseErr != nil {
+		parseErr.Offset += len(componentSplit[0]) + 1
+		if parseErr.Text == "" {
+			parseErr.Text = component
+		}
+		return nil, parseErr
+	}
+	switch tlvType {
+	case tlv.GenericNameComponent:
+		return NewGenericNameComponent(value), nil
+	case tlv.KeywordNameComponent:
+		return NewKeywordNameComponent(value), nil
+	}
+	if registered, ok := lookupNameComponentType(uint16(tlvType)); ok && registered.factory != nil {
+		c, err := decodeNameComponent(uint32(tlvType), value, tlv.DecodeOptions{})
+		if err != nil {
+			return nil, &NameParseError{Text: component, Err: err}
+		}
+		return c, nil
+	}
+	return NewBaseNameComponent(uint16(tlvType), value), nil
+}
+
+// unescapeComponentValue percent-decodes the string representation of a name component value. The Offset of a returned error is relative to the start of the string and its Index is not set.
+func unescapeComponentValue(str string) ([]byte, *NameParseError) {
+	for i := 0; i < len(str); i++ {
+		if str[i] != '%' {
+			continue
+		}
+		if i+2 >= len(str) || !isHexDigit(str[i+1]) || !isHexDigit(str[i+2]) {
+			end := i + 3
+			if end > len(str) {
+				end = len(str)
+			}
+			return nil, &NameParseError{Offset: i, Text: str[i:end], Err: errors.New("Name component contains invalid percent-encoding")}
+		}
+	}
+	value, err := url.PathUnescape(str)
+	if err != nil {
+		return nil, &NameParseError{Text: str, Err: errors.New("Name component contains invalid percent-encoding")}
+	}
+	if len(value) == 0 {
+		return nil, &NameParseError{Err: errors.New("Name contains empty component")}
+	}
+	return []byte(value), nil
+}
+
+// isHexDigit returns whether the character is a hexadecimal digit.
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// InvalidComponentPolicy determines how DecodeNameLenient handles name components that cannot be decoded.
+type InvalidComponentPolicy int
+
+const (
+	// InvalidComponentError causes decoding to fail on the first invalid name component.
+	InvalidComponentError InvalidComponentPolicy = iota
+	// InvalidComponentSkip causes invalid name components to be omitted from the decoded name.
+	InvalidComponentSkip
+)
+
+// DecodeName decodes a name from wire encoding.
 func DecodeName(b *tlv.Block) (*Name, error) {
+	return decodeName(b, InvalidComponentError, tlv.DecodeOptions{})
+}
+
+// DecodeNameWithOptions decodes a name from wire encoding, checking the encoding according to the specified options.
+func DecodeNameWithOptions(b *tlv.Block, opts tlv.DecodeOptions) (*Name, error) {
+	return decodeName(b, InvalidComponentError, opts)
+}
+
+// DecodeNameFromBuffer decodes a name from the start of a buffer, such as a name embedded in a larger packet, returning the name and the number of bytes it occupies. The leading TLV element must be a Name. Bytes following the name are ignored.
+func DecodeNameFromBuffer(buf []byte) (*Name, int, error) {
+	return decodeNameFromBuffer(buf, tlv.DecodeOptions{})
+}
+
+// decodeNameFromBuffer decodes a name from the start of a buffer, checking the encoding according to the specified options. The components are located in place with a NameView, so the buffer is not split into blocks first.
+func decodeNameFromBuffer(buf []byte, opts tlv.DecodeOptions) (*Name, int, error) {
+	var view NameView
+	if err := view.DecodeFrom(buf); err != nil {
+		return nil, 0, err
+	}
+	n, err := view.toName(opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	return n, len(view.wire), nil
+}
+
+// DecodeNameLenient decodes a name from wire encoding, handling name components that cannot be decoded according to the specified policy. Malformed TLV structures always cause decoding to fail.
+func DecodeNameLenient(b *tlv.Block, policy InvalidComponentPolicy) (*Name, error) {
+	return decodeName(b, policy, tlv.DecodeOptions{})
+}
+
+// decodeName decodes a name from wire encoding according to the specified policy and options.
+func decodeName(b *tlv.Block, policy InvalidComponentPolicy, opts tlv.DecodeOptions) (*Name, error) {
 	if b == nil {
 		return nil, util.ErrNonExistent
 	}
@@ -583,21 +841,89 @@ func DecodeName(b *tlv.Block) (*Name, error) {
 	if b.Type() != tlv.Name {
 		return nil, tlv.ErrUnexpected
 	}
+	if err := opts.CheckBlock(b); err != nil {
+		return nil, err
+	}
+
+	maxComponents, maxSize := NameLimits()
+	if b.Size() > maxSize {
+		return nil, util.ErrTooLong
+	}
+
+	elems := b.Subelements()
+	if len(elems) == 0 {
+		value := b.Value()
+		for pos := uint64(0); pos < uint64(len(value)); {
+			elem, elemLen, err := opts.DecodeBlock(value[pos:])
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, elem)
+			pos += elemLen
+		}
+	}
+
+	if len(elems) > maxComponents {
+		return nil, util.ErrTooLong
+	}
 
 	n := new(Name)
-	b.Parse()
-	for _, elem := range b.Subelements() {
-		component, err := DecodeNameComponent(elem)
+	canonical := isCanonicalBlock(b)
+	for _, elem := range elems {
+		if err := opts.CheckBlock(elem); err != nil {
+			return nil, err
+		}
+		component, err := decodeNameComponent(elem.Type(), elem.Value(), opts)
 		if err != nil {
+			if policy == InvalidComponentSkip {
+				canonical = false
+				continue
+			}
 			return nil, err
 		}
 		n.Append(component)
+		canonical = canonical && isCanonicalBlock(elem) && len(componentValueRef(component)) == len(elem.Value())
 	}
 	n.wire = b.DeepCopy()
 	n.wire.Wire()
+	n.canonicalWire = canonical
 	return n, nil
 }
 
+// isCanonicalBlock returns whether the TLV-TYPE and TLV-LENGTH of the block are encoded in the minimal number of octets.
+func isCanonicalBlock(b *tlv.Block) bool {
+	wire, err := b.Wire()
+	if err != nil {
+		return false
+	}
+	tlvType, typeLen, err := tlv.DecodeVarNum(wire)
+	if err != nil || typeLen != tlv.SizeOfVarNumber(tlvType) {
+		return false
+	}
+	length, lengthLen, err := tlv.DecodeVarNum(wire[typeLen:])
+	return err == nil && lengthLen == tlv.SizeOfVarNumber(length)
+}
+
+// wireValue returns the TLV-VALUE of the cached wire of the name without copying it, or false if the name has no cached wire in canonical encoding.
+func (n *Name) wireValue() ([]byte, bool) {
+	if n == nil || !n.HasWire() || !n.canonicalWire || !n.wire.HasWire() {
+		return nil, false
+	}
+	wire, err := n.wire.Wire()
+	if err != nil {
+		return nil, false
+	}
+	_, typeLen, err := tlv.DecodeVarNum(wire)
+	if err != nil {
+		return nil, false
+	}
+	_, lengthLen, err := tlv.DecodeVarNum(wire[typeLen:])
+	if err != nil {
+		return nil, false
+	}
+	return wire[typeLen+lengthLen:], true
+}
+
 func (n *Name) String() string {
 	if n.Size() == 0 {
 		return "/"
@@ -610,14 +936,223 @@ func (n *Name) String() string {
 	return out
 }
 
-// Append adds the specified name component to the end of the name.
+// StringURI returns the URI representation of the name, percent-encoding all bytes in component values other than unreserved characters (letters, digits, '-', '.', '_', and '~'). Unlike String, the result is safe for printing binary components and can be parsed by NameFromString.
+func (n *Name) StringURI() string {
+	if n.Size() == 0 {
+		return "/"
+	}
+
+	var out strings.Builder
+	for _, component := range n.components {
+		out.WriteByte('/')
+		switch component.Type() {
+		case tlv.ImplicitSha256DigestComponent, tlv.ParametersSha256DigestComponent, tlv.SegmentNameComponent, tlv.ByteOffsetNameComponent,
+			tlv.VersionNameComponent, tlv.TimestampNameComponent, tlv.SequenceNumNameComponent:
+			// These representations only contain URI-safe characters
+			out.WriteString(component.String())
+		case tlv.GenericNameComponent:
+			escapeComponentValue(&out, componentValueRef(component))
+		default:
+			out.WriteString(strconv.FormatUint(uint64(component.Type()), 10))
+			out.WriteByte('=')
+			escapeComponentValue(&out, componentValueRef(component))
+		}
+	}
+	return out.String()
+}
+
+// escapeComponentValue writes the percent-encoded form of a name component value.
+func escapeComponentValue(out *strings.Builder, value []byte) {
+	const hexDigits = "0123456789ABCDEF"
+	for _, b := range value {
+		if (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9') || b == '-' || b == '.' || b == '_' || b == '~' {
+			out.WriteByte(b)
+		} else {
+			out.WriteByte('%')
+			out.WriteByte(hexDigits[b>>4])
+			out.WriteByte(hexDigits[b&0x0F])
+		}
+	}
+}
+
+// Append adds the specified name component to the end of the name. If the component is nil, as returned by the component constructors for an empty value, the name is left unchanged. This does not enforce the limits returned by NameLimits; use TryAppend when appending untrusted components.
 func (n *Name) Append(component NameComponent) *Name {
+	if isNilComponent(component) {
+		return n
+	}
 	n.components = append(n.components, component.DeepCopy())
+	n.valueSize += componentSize(component)
 	n.wire = nil
 	return n
 }
 
-// At returns the name component at the specified index. If out of range, nil is returned.
+// AppendBlock adds a pre-encoded name component to the end of the name, avoiding the need to decode it separately first. The block must have a TLV type that is valid for a name component (between 1 and 65535, excluding Name itself), otherwise tlv.ErrUnexpected is returned. If the block is minimally encoded, a copy of it is kept as the cached encoding of the component instead of being re-encoded. As with Append, this does not enforce the limits returned by NameLimits.
+func (n *Name) AppendBlock(b *tlv.Block) error {
+	if b == nil {
+		return util.ErrNonExistent
+	}
+	if b.Type() == 0 || b.Type() > math.MaxUint16 || b.Type() == tlv.Name {
+		return tlv.ErrUnexpected
+	}
+	b = b.DeepCopy()
+	wire, err := b.Wire()
+	if err != nil {
+		return err
+	}
+	_, typeLen, err := tlv.DecodeVarNum(wire)
+	if err != nil {
+		return err
+	}
+	_, lengthLen, err := tlv.DecodeVarNum(wire[typeLen:])
+	if err != nil {
+		return err
+	}
+	value := wire[typeLen+lengthLen:]
+	component, err := decodeNameComponent(b.Type(), value, tlv.DecodeOptions{})
+	if err != nil {
+		return err
+	}
+	if cacheable, ok := component.(interface{ setWire(*tlv.Block) }); ok && isCanonicalBlock(b) && len(componentValueRef(component)) == len(value) {
+		cacheable.setWire(b)
+	}
+	n.components = append(n.components, component)
+	n.valueSize += componentSize(component)
+	n.wire = nil
+	return nil
+}
+
+// TryAppend adds the specified name component to the end of the name, returning util.ErrTooLong and leaving the name unchanged if this would exceed the limits returned by NameLimits.
+func (n *Name) TryAppend(component NameComponent) error {
+	if isNilComponent(component) {
+		return util.ErrNonExistent
+	}
+	if err := n.checkLimits(component); err != nil {
+		return err
+	}
+	n.Append(component)
+	return nil
+}
+
+// isNilComponent returns whether the name component is nil, including a nil pointer to a concrete component type.
+func isNilComponent(component NameComponent) bool {
+	if component == nil {
+		return true
+	}
+	value := reflect.ValueOf(component)
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
+
+// AppendName appends deep copies of all components of the specified name to the end of the name, which may be the name itself. If this would exceed the limits returned by NameLimits, util.ErrTooLong is returned and the name is left unchanged.
+func (n *Name) AppendName(other *Name) error {
+	if other == nil {
+		return util.ErrNonExistent
+	}
+	// Copy the component list first, in case other is the same name
+	added := make([]NameComponent, len(other.components))
+	copy(added, other.components)
+	if err := n.checkLimits(added...); err != nil {
+		return err
+	}
+	for _, component := range added {
+		n.components = append(n.components, component.DeepCopy())
+		n.valueSize += componentSize(component)
+	}
+	n.wire = nil
+	return nil
+}
+
+// checkLimits returns util.ErrTooLong if adding the specified components to the name would exceed the limits returned by NameLimits. Only the added components are measured, since the size of the existing components is kept as a running total.
+func (n *Name) checkLimits(added ...NameComponent) error {
+	maxComponents, maxSize := NameLimits()
+	if len(n.components)+len(added) > maxComponents {
+		return util.ErrTooLong
+	}
+
+	valueSize := n.valueSize
+	for _, component := range added {
+		valueSize += componentSize(component)
+	}
+	if tlv.SizeOfVarNumber(tlv.Name)+tlv.SizeOfVarNumber(uint64(valueSize))+valueSize > maxSize {
+		return util.ErrTooLong
+	}
+	return nil
+}
+
+// componentSize returns the size of the wire encoding of the name component, without encoding it.
+func componentSize(component NameComponent) int {
+	length := len(componentValueRef(component))
+	return tlv.SizeOfVarNumber(uint64(component.Type())) + tlv.SizeOfVarNumber(uint64(length)) + length
+}
+
+// AppendPath appends each segment of a slash-delimited path to the end of the name as a percent-decoded GenericNameComponent. A single leading slash is permitted, but empty segments are rejected. If an error is returned, the name is left unchanged; errors in the path are returned as a *NameParseError.
+func (n *Name) AppendPath(path string) error {
+	if len(path) == 0 {
+		return nil
+	}
+
+	offset := 0
+	if strings.HasPrefix(path, "/") {
+		offset = 1
+	}
+	components := make([]NameComponent, 0)
+	for i, segment := range strings.Split(path[offset:], "/") {
+		value, err := unescapeComponentValue(segment)
+		if err != nil {
+			err.Index = i
+			err.Offset += offset
+			return err
+		}
+		components = append(components, NewGenericNameComponent(value))
+		offset += len(segment) + 1
+	}
+	if err := n.checkLimits(components...); err != nil {
+		return err
+	}
+
+	for _, component := range components {
+		n.components = append(n.components, component)
+		n.valueSize += componentSize(component)
+	}
+	n.wire = nil
+	return nil
+}
+
+// AppendGeneric appends a GenericNameComponent with the specified value to the end of the name. If the value is empty, the name is left unchanged, as with Append; use TryAppend with NewGenericNameComponent to have this reported as util.ErrNonExistent.
+func (n *Name) AppendGeneric(value []byte) *Name {
+	return n.Append(NewGenericNameComponent(value))
+}
+
+// AppendKeyword appends a KeywordNameComponent with the specified value to the end of the name. If the value is empty, the name is left unchanged, as with Append; use TryAppend with NewKeywordNameComponent to have this reported as util.ErrNonExistent.
+func (n *Name) AppendKeyword(value string) *Name {
+	return n.Append(NewKeywordNameComponent([]byte(value)))
+}
+
+// AppendNumber appends a GenericNameComponent whose value is the specified number encoded as an NNI in the minimal number of octets (1, 2, 4, or 8). Unlike AppendSegment, AppendVersion, and the other typed helpers, the component is untyped, and is shown by StringURI as its escaped octets (e.g., "%01%00" for 256) rather than as a number; this is intended for application protocols that use plain generic numeric components.
+func (n *Name) AppendNumber(number uint64) *Name {
+	return n.Append(NewGenericNameComponent(tlv.EncodeNNI(number)))
+}
+
+// AppendSegment appends a SegmentNameComponent with the specified segment number to the end of the name.
+func (n *Name) AppendSegment(segment uint64) *Name {
+	return n.Append(NewSegmentNameComponent(segment))
+}
+
+// AppendVersion appends a VersionNameComponent with the specified version number to the end of the name.
+func (n *Name) AppendVersion(version uint64) *Name {
+	return n.Append(NewVersionNameComponent(version))
+}
+
+// AppendTimestamp appends a TimestampNameComponent with the specified timestamp to the end of the name.
+func (n *Name) AppendTimestamp(timestamp uint64) *Name {
+	return n.Append(NewTimestampNameComponent(timestamp))
+}
+
+// AppendSequenceNum appends a SequenceNumNameComponent with the specified sequence number to the end of the name.
+func (n *Name) AppendSequenceNum(seq uint64) *Name {
+	return n.Append(NewSequenceNumNameComponent(seq))
+}
+
+// At returns the name component at the specified index. If out of range, nil is returned. Changing the value of the returned component with SetValue invalidates the wire encoding of the name.
 func (n *Name) At(index int) NameComponent {
 	if index < 0 || index >= len(n.components) {
 		return nil
@@ -625,48 +1160,52 @@ func (n *Name) At(index int) NameComponent {
 	return n.components[index]
 }
 
+// Get returns the name component at the specified index, where negative indices count back from the end of the name (e.g., -1 is the final component). If out of range in either direction, nil is returned. As with At, the returned component is not a copy.
+func (n *Name) Get(index int) NameComponent {
+	if index < 0 {
+		index += len(n.components)
+	}
+	return n.At(index)
+}
+
+// AtFromEnd returns the name component at the specified index counting back from the end of the name, where 0 is the final component. If out of range, nil is returned. As with At, the returned component is not a copy.
+func (n *Name) AtFromEnd(index int) NameComponent {
+	if index < 0 {
+		return nil
+	}
+	return n.At(len(n.components) - 1 - index)
+}
+
 // Clear erases all components from the name.
 func (n *Name) Clear() {
 	if len(n.components) > 0 {
 		n.components = make([]NameComponent, 0)
+		n.valueSize = 0
 		n.wire = nil
 	}
 }
 
+// Components returns a shallow copy of the components of the name, suitable for ranging over. The behavior of mutating the returned components is undefined.
+func (n *Name) Components() []NameComponent {
+	components := make([]NameComponent, len(n.components))
+	copy(components, n.components)
+	return components
+}
+
 // Compare returns the canonical order of this name against the the specified other name.
 func (n *Name) Compare(other *Name) int {
-	if n.Equals(other) {
-		return 0
-	} else if n.PrefixOf(other) {
-		return -1
-	} else if other.PrefixOf(n) {
-		return 1
-	}
-
-	for i := 0; i < int(math.Min(float64(n.Size()), float64(other.Size()))); i++ {
-		if n.At(i).Type() < other.At(i).Type() {
-			return -1
-		} else if n.At(i).Type() > other.At(i).Type() {
-			return 1
-		} else if len(n.At(i).Value()) < len(other.At(i).Value()) {
-			return -1
-		} else if len(n.At(i).Value()) > len(other.At(i).Value()) {
-			return 1
-		} else if !bytes.Equal(n.At(i).Value(), other.At(i).Value()) {
-			// Do byte-by-byte comparison
-			nValue := n.At(i).Value()
-			otherValue := other.At(i).Value()
-			for j := 0; j < len(nValue); j++ {
-				if nValue[j] < otherValue[j] {
-					return -1
-				} else if nValue[j] > otherValue[j] {
-					return 1
-				}
-			}
+	for i := 0; i < n.Size() && i < other.Size(); i++ {
+		if result := n.At(i).Compare(other.At(i)); result != 0 {
+			return result
 		}
 	}
 
-	// The only possibility left is that they exactly match.
+	// One name is a prefix of the other (or they are equal), so the shorter name comes first.
+	if n.Size() < other.Size() {
+		return -1
+	} else if n.Size() > other.Size() {
+		return 1
+	}
 	return 0
 }
 
@@ -677,17 +1216,43 @@ func (n *Name) DeepCopy() *Name {
 	for _, component := range n.components {
 		newN.components = append(newN.components, component.DeepCopy())
 	}
+	newN.valueSize = n.valueSize
 	return newN
 }
 
+// Each calls the specified function on each component of the name in order, stopping early if the function returns false. The behavior of mutating the visited components is undefined.
+func (n *Name) Each(f func(int, NameComponent) bool) {
+	for i, component := range n.components {
+		if !f(i, component) {
+			return
+		}
+	}
+}
+
+// EachReverse calls the specified function on each component of the name in reverse order, starting from the final component, stopping early if the function returns false. The index passed to the function is the index of the component from the start of the name. The behavior of mutating the visited components is undefined.
+func (n *Name) EachReverse(f func(int, NameComponent) bool) {
+	for i := len(n.components) - 1; i >= 0; i-- {
+		if !f(i, n.components[i]) {
+			return
+		}
+	}
+}
+
 // Equals returns whether the specified name is equal to this name.
 func (n *Name) Equals(other *Name) bool {
 	if n.Size() != other.Size() {
 		return false
 	}
 
+	// Canonically encoded wires are identical if and only if the names are equal
+	if nValue, ok := n.wireValue(); ok {
+		if otherValue, ok := other.wireValue(); ok {
+			return bytes.Equal(nValue, otherValue)
+		}
+	}
+
 	for i := 0; i < n.Size(); i++ {
-		if n.At(i).Type() != other.At(i).Type() || !bytes.Equal(n.At(i).Value(), other.At(i).Value()) {
+		if !n.At(i).Equals(other.At(i)) {
 			return false
 		}
 	}
@@ -701,6 +1266,7 @@ func (n *Name) Erase(index int) error {
 		return util.ErrOutOfRange
 	}
 
+	n.valueSize -= componentSize(n.components[index])
 	copy(n.components[index:], n.components[index+1:])
 	n.components = n.components[:len(n.components)-1]
 	n.wire = nil
@@ -718,9 +1284,30 @@ func (n *Name) Find(tlvType uint16) (int, NameComponent) {
 	return -1, nil
 }
 
+// FindLast returns the last name component with the specified type, as well as its index.
+func (n *Name) FindLast(tlvType uint16) (int, NameComponent) {
+	for i := len(n.components) - 1; i >= 0; i-- {
+		if n.components[i].Type() == tlvType {
+			return i, n.components[i]
+		}
+	}
+
+	return -1, nil
+}
+
 // HasWire returns whether the name has a wire encoding.
 func (n *Name) HasWire() bool {
-	return n.wire != nil
+	return n.wire != nil && !n.componentsModified()
+}
+
+// componentsModified returns whether the value of any component has been changed in place (e.g., via SetValue on a component returned by At) since the name was last encoded.
+func (n *Name) componentsModified() bool {
+	for _, component := range n.components {
+		if modifiable, ok := component.(interface{ isModified() bool }); ok && modifiable.isModified() {
+			return true
+		}
+	}
+	return false
 }
 
 // Insert inserts a name component at the specified index.
@@ -728,34 +1315,124 @@ func (n *Name) Insert(index int, component NameComponent) error {
 	if index < 0 || index >= n.Size() {
 		return util.ErrOutOfRange
 	}
+	if err := n.checkLimits(component); err != nil {
+		return err
+	}
 
 	n.components = append(n.components[:index], append([]NameComponent{component.DeepCopy()}, n.components[index:]...)...)
+	n.valueSize += componentSize(component)
 	n.wire = nil
 	return nil
 }
 
+// Last returns the final component of the name, or nil if the name is empty.
+func (n *Name) Last() NameComponent {
+	return n.At(len(n.components) - 1)
+}
+
+// numberAt returns the NNI value of the component at the specified index if it has the specified type.
+func (n *Name) numberAt(index int, tlvType uint16) (uint64, bool) {
+	component := n.At(index)
+	if component == nil || component.Type() != tlvType {
+		return 0, false
+	}
+	value, err := tlv.DecodeNNI(component.Value())
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// ByteOffset returns the byte offset in the component at the specified index, or false if it is not a ByteOffsetNameComponent.
+func (n *Name) ByteOffset(index int) (uint64, bool) {
+	return n.numberAt(index, tlv.ByteOffsetNameComponent)
+}
+
+// Segment returns the segment number in the component at the specified index, or false if it is not a SegmentNameComponent.
+func (n *Name) Segment(index int) (uint64, bool) {
+	return n.numberAt(index, tlv.SegmentNameComponent)
+}
+
+// SequenceNum returns the sequence number in the component at the specified index, or false if it is not a SequenceNumNameComponent.
+func (n *Name) SequenceNum(index int) (uint64, bool) {
+	return n.numberAt(index, tlv.SequenceNumNameComponent)
+}
+
+// Timestamp returns the timestamp in the component at the specified index, or false if it is not a TimestampNameComponent.
+func (n *Name) Timestamp(index int) (uint64, bool) {
+	return n.numberAt(index, tlv.TimestampNameComponent)
+}
+
+// Version returns the version number in the component at the specified index, or false if it is not a VersionNameComponent.
+func (n *Name) Version(index int) (uint64, bool) {
+	return n.numberAt(index, tlv.VersionNameComponent)
+}
+
 // Prefix returns a name prefix of the specified number of components. If greater than or equal to the size of the name, this returns a copy of the name.
 func (n *Name) Prefix(size int) *Name {
 	prefix := *n
 	// We have to deep copy this
 	prefix.components = make([]NameComponent, 0, len(n.components))
-	for i := 0; i < size; i++ {
+	prefix.valueSize = 0
+	for i := 0; i < size && i < len(n.components); i++ {
 		//prefix.components = append(prefix.components, reflect.New(reflect.ValueOf(component).Elem().Type()).Interface().(NameComponent))
 		prefix.components = append(prefix.components, n.components[i].DeepCopy())
+		prefix.valueSize += componentSize(n.components[i])
 	}
 	// Reset wire
-	prefix.wire = new(tlv.Block)
+	prefix.wire = nil
 	return &prefix
 }
 
+// CommonPrefix returns the longest prefix shared by the two specified names. If the names share no components, an empty name is returned.
+func CommonPrefix(a *Name, b *Name) *Name {
+	size := 0
+	for size < a.Size() && size < b.Size() {
+		if !a.At(size).Equals(b.At(size)) {
+			break
+		}
+		size++
+	}
+	return a.Prefix(size)
+}
+
+// LongestSuffixPrefix returns the number of components in the longest suffix of name a that is also a prefix of name b, or 0 if there is none (e.g., 2 for /a/b/c and /b/c/d). No names are allocated.
+func LongestSuffixPrefix(a *Name, b *Name) int {
+	if a == nil || b == nil {
+		return 0
+	}
+	size := len(a.components)
+	if len(b.components) < size {
+		size = len(b.components)
+	}
+	for ; size > 0; size-- {
+		offset := len(a.components) - size
+		matches := true
+		for i := 0; i < size && matches; i++ {
+			matches = a.components[offset+i].Equals(b.components[i])
+		}
+		if matches {
+			return size
+		}
+	}
+	return 0
+}
+
 // PrefixOf returns whether this name is a prefix of the specified name.
 func (n *Name) PrefixOf(other *Name) bool {
 	if other == nil || n.Size() > other.Size() {
 		return false
 	}
 
+	// With canonical encoding, a name is a prefix of another if and only if its TLV-VALUE is a prefix of the other's TLV-VALUE
+	if nValue, ok := n.wireValue(); ok {
+		if otherValue, ok := other.wireValue(); ok {
+			return bytes.HasPrefix(otherValue, nValue)
+		}
+	}
+
 	for i := 0; i < n.Size(); i++ {
-		if n.At(i).Type() != other.At(i).Type() || !bytes.Equal(n.At(i).Value(), other.At(i).Value()) {
+		if !n.At(i).Equals(other.At(i)) {
 			return false
 		}
 	}
@@ -763,6 +1440,15 @@ func (n *Name) PrefixOf(other *Name) bool {
 	return true
 }
 
+// HasPrefix returns whether the name begins with the name represented by the specified URI, as parsed by NameFromString. If the URI cannot be parsed, false is returned.
+func (n *Name) HasPrefix(uri string) bool {
+	prefix, err := NameFromString(uri)
+	if err != nil {
+		return false
+	}
+	return prefix.PrefixOf(n)
+}
+
 // Set replaces the component at the specified index with the specified component.
 func (n *Name) Set(index int, component NameComponent) error {
 	if index < 0 || index >= len(n.components) {
@@ -770,6 +1456,7 @@ func (n *Name) Set(index int, component NameComponent) error {
 	}
 
 	//n.components[index] = reflect.New(reflect.ValueOf(component).Elem().Type()).Interface().(NameComponent)
+	n.valueSize += componentSize(component) - componentSize(n.components[index])
 	n.components[index] = component.DeepCopy()
 	n.wire = nil
 	return nil
@@ -780,17 +1467,171 @@ func (n *Name) Size() int {
 	return len(n.components)
 }
 
+// Validate checks that digest components are positioned legally within the name. An ImplicitSha256DigestComponent may only be the final component, while a name may contain at most one ParametersSha256DigestComponent, which may only be followed by the timestamp, nonce, SignatureInfo, and SignatureValue components of a legacy signed command Interest.
+func (n *Name) Validate() error {
+	parametersDigest := -1
+	for i, component := range n.components {
+		switch component.Type() {
+		case tlv.ImplicitSha256DigestComponent:
+			if i != len(n.components)-1 {
+				return errors.New("ImplicitSha256DigestComponent at index " + strconv.Itoa(i) + " is not the final component")
+			}
+		case tlv.ParametersSha256DigestComponent:
+			if parametersDigest != -1 {
+				return errors.New("ParametersSha256DigestComponent at index " + strconv.Itoa(i) + " follows another ParametersSha256DigestComponent")
+			}
+			parametersDigest = i
+		}
+	}
+
+	if parametersDigest != -1 && parametersDigest != len(n.components)-1 {
+		if len(n.components)-parametersDigest-1 != legacyCommandComponents {
+			return errors.New("ParametersSha256DigestComponent at index " + strconv.Itoa(parametersDigest) + " is not followed by signed Interest components")
+		}
+		if _, _, _, err := parseLegacyCommandName(n); err != nil {
+			return errors.New("ParametersSha256DigestComponent at index " + strconv.Itoa(parametersDigest) + " is not followed by signed Interest components: " + err.Error())
+		}
+	}
+	return nil
+}
+
+// singleInstanceComponents contains the typed name component types that a name is expected to contain at most one of, in the order they are reported by CheckDuplicateComponents.
+var singleInstanceComponents = []struct {
+	tlvType uint16
+	name    string
+}{
+	{tlv.VersionNameComponent, "VersionNameComponent"},
+	{tlv.TimestampNameComponent, "TimestampNameComponent"},
+	{tlv.ParametersSha256DigestComponent, "ParametersSha256DigestComponent"},
+	{tlv.ImplicitSha256DigestComponent, "ImplicitSha256DigestComponent"},
+}
+
+// CheckDuplicateComponents is a debugging aid that returns an error listing the indices of any typed components that usually appear at most once in a name, such as two VersionNameComponents or two ParametersSha256DigestComponents. Apart from a second ParametersSha256DigestComponent, which Validate rejects, such names are legal, so this is not checked when appending components; producers can call it to catch components appended twice by mistake.
+func (n *Name) CheckDuplicateComponents() error {
+	var problems []string
+	for _, single := range singleInstanceComponents {
+		var indices []string
+		for i, component := range n.components {
+			if component.Type() == single.tlvType {
+				indices = append(indices, strconv.Itoa(i))
+			}
+		}
+		if len(indices) > 1 {
+			problems = append(problems, "multiple "+single.name+"s at indices "+strings.Join(indices, ", "))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New("Name has " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
 // Encode encodes the name into a bock.
 func (n *Name) Encode() *tlv.Block {
-	if n.wire == nil {
+	return n.encodeWire().DeepCopy()
+}
+
+// WriteTo writes the wire encoding of the name to the specified writer. The cached wire is used if present, avoiding any allocation; otherwise, the name is encoded and the wire cached.
+func (n *Name) WriteTo(w io.Writer) (int64, error) {
+	return n.encodeWire().WriteTo(w)
+}
+
+// encodeWire returns the cached wire of the name, encoding it first if necessary. The returned block must not be modified.
+func (n *Name) encodeWire() *tlv.Block {
+	if !n.HasWire() {
 		n.wire = new(tlv.Block)
 		n.wire.SetType(tlv.Name)
 
+		// Recompute the running size, since components may have been changed in place
+		n.valueSize = 0
 		for _, component := range n.components {
+			n.valueSize += componentSize(component)
 			n.wire.Append(component.Encode())
+			if modifiable, ok := component.(interface{ clearModified() }); ok {
+				modifiable.clearModified()
+			}
 		}
 
-		n.wire.Wire()
+		n.canonicalWire = true
 	}
-	return n.wire.DeepCopy()
+	n.wire.Wire()
+	return n.wire
+}
+
+/////////////
+// Marshaling
+/////////////
+
+// MarshalText encodes the name as its percent-encoded URI string (see StringURI). A nil name is encoded as "/".
+func (n *Name) MarshalText() ([]byte, error) {
+	if n == nil {
+		return []byte("/"), nil
+	}
+	return []byte(n.StringURI()), nil
+}
+
+// UnmarshalText decodes the name from its URI string.
+func (n *Name) UnmarshalText(text []byte) error {
+	decoded, err := NameFromString(string(text))
+	if err != nil {
+		return err
+	}
+	*n = *decoded
+	return nil
+}
+
+// MarshalJSON encodes the name as a JSON string containing its URI.
+func (n *Name) MarshalJSON() ([]byte, error) {
+	text, err := n.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON decodes the name from a JSON string containing its URI.
+func (n *Name) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	return n.UnmarshalText([]byte(text))
+}
+
+// MarshalBinary encodes the name into its TLV wire encoding.
+func (n *Name) MarshalBinary() ([]byte, error) {
+	return n.Encode().Wire()
+}
+
+// UnmarshalBinary decodes the name from its TLV wire encoding.
+func (n *Name) UnmarshalBinary(data []byte) error {
+	block, blockLen, err := tlv.DecodeBlock(data)
+	if err != nil {
+		return err
+	}
+	if blockLen != uint64(len(data)) {
+		return errors.New("Trailing data after Name")
+	}
+
+	decoded, err := DecodeName(block)
+	if err != nil {
+		return err
+	}
+	*n = *decoded
+	return nil
+}
+
+// MarshalTLV encodes the name into a block, allowing names to be used as fields with tlv.Marshal.
+func (n *Name) MarshalTLV() (*tlv.Block, error) {
+	return n.Encode(), nil
+}
+
+// UnmarshalTLV decodes the name from a block, allowing names to be used as fields with tlv.Unmarshal.
+func (n *Name) UnmarshalTLV(b *tlv.Block) error {
+	decoded, err := DecodeName(b)
+	if err != nil {
+		return err
+	}
+	*n = *decoded
+	return nil
 }
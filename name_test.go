@@ -8,10 +8,17 @@
 package ndn_test
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"strconv"
 	"testing"
 
 	. "github.com/eric135/go-ndn2"
 	"github.com/eric135/go-ndn2/tlv"
+	"github.com/eric135/go-ndn2/util"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -131,6 +138,17 @@ func TestNameComponents(t *testing.T) {
 	assert.Equal(t, -1, index)
 	assert.Nil(t, matching)
 
+	// Test finding last name component of type
+	index, matching = n.FindLast(tlv.GenericNameComponent)
+	assert.Equal(t, 2, index)
+	assert.Equal(t, "go", matching.String())
+	index, matching = n.Find(tlv.GenericNameComponent)
+	assert.Equal(t, 0, index)
+	assert.Equal(t, "go", matching.String())
+	index, matching = n.FindLast(tlv.ImplicitSha256DigestComponent)
+	assert.Equal(t, -1, index)
+	assert.Nil(t, matching)
+
 	// Test removal
 	assert.NoError(t, n.Erase(1))
 	assert.Equal(t, 3, n.Size())
@@ -150,6 +168,48 @@ func TestNameComponents(t *testing.T) {
 	assert.Nil(t, n.At(0))
 }
 
+func TestNameGet(t *testing.T) {
+	n, err := NameFromString("/go/ndn/v=3/seg=7")
+	assert.NoError(t, err)
+	assert.True(t, n.Get(0).Equals(n.At(0)))
+	assert.True(t, n.Get(3).Equals(NewSegmentNameComponent(7)))
+	assert.True(t, n.Get(-1).Equals(NewSegmentNameComponent(7)))
+	assert.True(t, n.Get(-2).Equals(NewVersionNameComponent(3)))
+	assert.True(t, n.Get(-4).Equals(NewGenericNameComponent([]byte("go"))))
+	assert.Nil(t, n.Get(4))
+	assert.Nil(t, n.Get(-5))
+	assert.Nil(t, NewName().Get(-1))
+	assert.Nil(t, NewName().Get(0))
+}
+
+func TestNameAtFromEnd(t *testing.T) {
+	n := mustName(t, "/go/ndn/v=3/seg=7")
+	assert.True(t, n.AtFromEnd(0).Equals(NewSegmentNameComponent(7)))
+	assert.True(t, n.AtFromEnd(1).Equals(NewVersionNameComponent(3)))
+	assert.True(t, n.AtFromEnd(3).Equals(n.At(0)))
+	assert.Nil(t, n.AtFromEnd(4))
+	assert.Nil(t, n.AtFromEnd(-1))
+	assert.Nil(t, NewName().AtFromEnd(0))
+
+	visited := []string{}
+	n.EachReverse(func(i int, c NameComponent) bool {
+		visited = append(visited, strconv.Itoa(i)+":"+c.String())
+		return i > 2
+	})
+	assert.Equal(t, []string{"3:seg=7", "2:v=3"}, visited)
+}
+
+func TestLongestSuffixPrefix(t *testing.T) {
+	assert.Equal(t, 2, LongestSuffixPrefix(mustName(t, "/a/b/c"), mustName(t, "/b/c/d")))
+	assert.Equal(t, 3, LongestSuffixPrefix(mustName(t, "/a/b/c"), mustName(t, "/a/b/c")))
+	assert.Equal(t, 1, LongestSuffixPrefix(mustName(t, "/a/b/c"), mustName(t, "/c/b")))
+	assert.Equal(t, 2, LongestSuffixPrefix(mustName(t, "/a/a/a"), mustName(t, "/a/a")))
+	assert.Equal(t, 0, LongestSuffixPrefix(mustName(t, "/a/b/c"), mustName(t, "/b")))
+	assert.Equal(t, 0, LongestSuffixPrefix(mustName(t, "/a/seg=1"), mustName(t, "/1")))
+	assert.Equal(t, 0, LongestSuffixPrefix(NewName(), mustName(t, "/a")))
+	assert.Equal(t, 0, LongestSuffixPrefix(mustName(t, "/a"), nil))
+}
+
 func TestNameComparison(t *testing.T) {
 	n, err := DecodeName(tlv.NewBlock(0x07, []byte{0x08, 0x02, 0x67, 0x6f, 0x08, 0x03, 0x6e, 0x64, 0x6e, 0x21, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xAA}))
 	assert.NotNil(t, n)
@@ -194,6 +254,18 @@ func TestNameComparison(t *testing.T) {
 	assert.False(t, n1.Equals(n))
 }
 
+func TestNameHasPrefix(t *testing.T) {
+	n := mustName(t, "/localhost/nfd/rib/register")
+	assert.True(t, n.HasPrefix("/localhost/nfd"))
+	assert.True(t, n.HasPrefix("/localhost/nfd/rib/register"))
+	assert.True(t, n.HasPrefix("/"))
+	assert.False(t, n.HasPrefix("/localhost/nfd/fib"))
+	assert.False(t, n.HasPrefix("/localhost/nfd/rib/register/extra"))
+	assert.False(t, n.HasPrefix("/localhost/seg=abc"))
+	assert.True(t, mustName(t, "/go/seg=1").HasPrefix("/go/seg=1"))
+	assert.False(t, mustName(t, "/go/seg=1").HasPrefix("/go/1"))
+}
+
 func TestNameEncode(t *testing.T) {
 	n, err := DecodeName(tlv.NewBlock(0x07, []byte{0x08, 0x02, 0x67, 0x6f, 0x08, 0x03, 0x6e, 0x64, 0x6e, 0x21, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xAA}))
 	assert.NotNil(t, n)
@@ -214,7 +286,7 @@ func TestNameEncode(t *testing.T) {
 	assert.True(t, n.HasWire())
 	wire, err = b.Wire()
 	assert.NoError(t, err)
-	assert.Equal(t, []byte{0x07, 0x12, 0x08, 0x02, 0x67, 0x6f, 0x08, 0x02, 0x67, 0x6f, 0x21, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xAA}, wire)
+	assert.Equal(t, []byte{0x07, 0x0b, 0x08, 0x02, 0x67, 0x6f, 0x08, 0x02, 0x67, 0x6f, 0x21, 0x01, 0xAA}, wire)
 }
 
 func TestNameCompare(t *testing.T) {
@@ -253,3 +325,921 @@ func TestNameCompare(t *testing.T) {
 	assert.Equal(t, -1, n2.Compare(n3))
 	assert.Equal(t, 1, n3.Compare(n2))
 }
+
+func TestNameComponentDecodeShortNNI(t *testing.T) {
+	types := []uint32{tlv.SegmentNameComponent, tlv.ByteOffsetNameComponent, tlv.VersionNameComponent, tlv.TimestampNameComponent, tlv.SequenceNumNameComponent}
+	values := [][]byte{
+		{0xAA},
+		{0xAA, 0xBB},
+		{0xAA, 0xBB, 0xCC},
+		{0xAA, 0xBB, 0xCC, 0xDD},
+		{0xAA, 0xBB, 0xCC, 0xDD, 0xEE},
+		{0xAA, 0xBB, 0xCC, 0xDD, 0xEE, 0xFF},
+		{0xAA, 0xBB, 0xCC, 0xDD, 0xEE, 0xFF, 0x11},
+		{0xAA, 0xBB, 0xCC, 0xDD, 0xEE, 0xFF, 0x11, 0x22},
+	}
+	expected := map[int]uint64{
+		1: 0xAA,
+		2: 0xAABB,
+		4: 0xAABBCCDD,
+		8: 0xAABBCCDDEEFF1122,
+	}
+
+	for _, tlvType := range types {
+		for _, value := range values {
+			c, err := DecodeNameComponent(tlv.NewBlock(tlvType, value))
+			if number, ok := expected[len(value)]; ok {
+				assert.NoError(t, err)
+				assert.NotNil(t, c)
+				assert.Equal(t, uint16(tlvType), c.Type())
+				decoded, err := tlv.DecodeNNI(c.Value())
+				assert.NoError(t, err)
+				assert.Equal(t, number, decoded)
+			} else {
+				assert.Error(t, err)
+				assert.Nil(t, c)
+			}
+		}
+	}
+
+	n, err := DecodeName(tlv.NewBlock(tlv.Name, []byte{0x08, 0x02, 0x67, 0x6f, 0x21, 0x02, 0x01, 0x00}))
+	assert.NoError(t, err)
+	assert.NotNil(t, n)
+	assert.Equal(t, "/go/seg=256", n.String())
+}
+
+func TestNameAppendTyped(t *testing.T) {
+	n := NewName().AppendGeneric([]byte("go")).AppendKeyword("ndn").AppendVersion(3).AppendSegment(7).AppendTimestamp(1000).AppendSequenceNum(42)
+	assert.Equal(t, 6, n.Size())
+	assert.Equal(t, uint16(tlv.GenericNameComponent), n.At(0).Type())
+	assert.Equal(t, uint16(tlv.KeywordNameComponent), n.At(1).Type())
+	assert.Equal(t, uint16(tlv.VersionNameComponent), n.At(2).Type())
+	assert.Equal(t, uint16(tlv.SegmentNameComponent), n.At(3).Type())
+	assert.Equal(t, uint16(tlv.TimestampNameComponent), n.At(4).Type())
+	assert.Equal(t, uint16(tlv.SequenceNumNameComponent), n.At(5).Type())
+	assert.Equal(t, "/go/32=ndn/v=3/seg=7/t=1000/seq=42", n.String())
+
+	n.Encode()
+	assert.True(t, n.HasWire())
+	n.AppendSegment(8)
+	assert.False(t, n.HasWire())
+	assert.Equal(t, "seg=8", n.At(6).String())
+}
+
+func TestNameAppendEmpty(t *testing.T) {
+	n := NewName().AppendGeneric([]byte("go"))
+	assert.Equal(t, "/go", n.AppendGeneric(nil).AppendGeneric([]byte{}).AppendKeyword("").String())
+	assert.Equal(t, 1, n.Size())
+	assert.Equal(t, "/go", n.Append(nil).Append(NewGenericNameComponent(nil)).String())
+
+	assert.Equal(t, util.ErrNonExistent, n.TryAppend(NewGenericNameComponent(nil)))
+	assert.Equal(t, util.ErrNonExistent, n.TryAppend(NewKeywordNameComponent([]byte{})))
+	assert.Equal(t, util.ErrNonExistent, n.TryAppend(nil))
+	assert.Equal(t, "/go", n.String())
+}
+
+func TestNameAppendNumber(t *testing.T) {
+	n := NewName().AppendGeneric([]byte("go")).AppendNumber(0).AppendNumber(256).AppendNumber(0x10000).AppendNumber(0x100000000)
+	assert.Equal(t, 5, n.Size())
+	assert.Equal(t, uint16(tlv.GenericNameComponent), n.At(1).Type())
+	assert.Equal(t, []byte{0x00}, n.At(1).Value())
+	assert.Equal(t, []byte{0x01, 0x00}, n.At(2).Value())
+	assert.Equal(t, []byte{0x00, 0x01, 0x00, 0x00}, n.At(3).Value())
+	assert.Equal(t, []byte{0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00}, n.At(4).Value())
+	assert.Equal(t, "/go/%00/%01%00/%00%01%00%00/%00%00%00%01%00%00%00%00", n.StringURI())
+
+	// Distinct from the typed SegmentNameComponent
+	assert.False(t, NewName().AppendNumber(5).Equals(NewName().AppendSegment(5)))
+}
+
+func TestNameAppendBlock(t *testing.T) {
+	n := NewName().AppendGeneric([]byte("go"))
+	assert.NoError(t, n.AppendBlock(tlv.NewBlock(tlv.SegmentNameComponent, []byte{0x01, 0x00})))
+	assert.NoError(t, n.AppendBlock(tlv.NewBlock(0xFC00, []byte("abc"))))
+	assert.Equal(t, "/go/seg=256/64512=abc", n.String())
+	assert.IsType(t, &SegmentNameComponent{}, n.At(1))
+
+	assert.Equal(t, tlv.ErrUnexpected, n.AppendBlock(tlv.NewBlock(tlv.Name, []byte{0x08, 0x01, 0x61})))
+	assert.Equal(t, tlv.ErrUnexpected, n.AppendBlock(tlv.NewBlock(0, []byte("abc"))))
+	assert.Equal(t, tlv.ErrUnexpected, n.AppendBlock(tlv.NewBlock(0x10000, []byte("abc"))))
+	assert.Error(t, n.AppendBlock(tlv.NewBlock(tlv.GenericNameComponent, []byte{})))
+	assert.Equal(t, util.ErrNonExistent, n.AppendBlock(nil))
+	assert.Equal(t, 3, n.Size())
+
+	// The wire of the block is kept as the encoding of the component
+	block, _, err := tlv.DecodeBlock([]byte{tlv.GenericNameComponent, 0x03, 0x6e, 0x64, 0x6e})
+	assert.NoError(t, err)
+	blockWire, err := block.Wire()
+	assert.NoError(t, err)
+	assert.NoError(t, n.AppendBlock(block))
+	componentWire, err := n.At(3).Encode().Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, blockWire, componentWire)
+	assert.Equal(t, "/go/seg=256/64512=abc/ndn", n.String())
+
+	// Modifying the block afterwards does not affect the name
+	block.SetValue([]byte("xyz"))
+	componentWire, err = n.At(3).Encode().Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{tlv.GenericNameComponent, 0x03, 0x6e, 0x64, 0x6e}, componentWire)
+	assert.Equal(t, "/go/seg=256/64512=abc/ndn", n.String())
+	decoded, err := DecodeName(n.Encode())
+	assert.NoError(t, err)
+	assert.Equal(t, "/go/seg=256/64512=abc/ndn", decoded.String())
+
+	// A non-minimal encoding is not kept
+	block, _, err = tlv.DecodeBlock([]byte{tlv.SegmentNameComponent, 0x02, 0x00, 0x05})
+	assert.NoError(t, err)
+	assert.NoError(t, n.AppendBlock(block))
+	componentWire, err = n.At(4).Encode().Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{tlv.SegmentNameComponent, 0x01, 0x05}, componentWire)
+	decoded, err = DecodeName(n.Encode())
+	assert.NoError(t, err)
+	assert.True(t, n.Equals(decoded))
+}
+
+func TestNameAppendName(t *testing.T) {
+	n := mustName(t, "/go")
+	suffix := mustName(t, "/ndn/seg=1")
+	n.Encode()
+	assert.NoError(t, n.AppendName(suffix))
+	assert.False(t, n.HasWire())
+	assert.Equal(t, "/go/ndn/seg=1", n.String())
+
+	// Components are copied
+	suffix.At(0).(*GenericNameComponent).SetValue([]byte("edu"))
+	assert.Equal(t, "/go/ndn/seg=1", n.String())
+
+	// Appending to itself
+	assert.NoError(t, n.AppendName(n))
+	assert.Equal(t, "/go/ndn/seg=1/go/ndn/seg=1", n.String())
+
+	assert.NoError(t, n.AppendName(NewName()))
+	assert.Equal(t, 6, n.Size())
+	assert.Equal(t, util.ErrNonExistent, n.AppendName(nil))
+
+	// Limits
+	long := NewName()
+	for long.Size() < DefaultMaxNameComponents {
+		long.AppendGeneric([]byte("a"))
+	}
+	assert.Equal(t, util.ErrTooLong, n.AppendName(long))
+	assert.Equal(t, 6, n.Size())
+}
+
+func TestNameAppendPath(t *testing.T) {
+	n := NewName().AppendGeneric([]byte("go"))
+	assert.NoError(t, n.AppendPath("/ndn/a%2Fb"))
+	assert.Equal(t, 3, n.Size())
+	assert.Equal(t, uint16(tlv.GenericNameComponent), n.At(2).Type())
+	assert.Equal(t, []byte("a/b"), n.At(2).Value())
+
+	assert.NoError(t, n.AppendPath("c/d"))
+	assert.Equal(t, 5, n.Size())
+	assert.Equal(t, "c", n.At(3).String())
+	assert.Equal(t, "d", n.At(4).String())
+
+	assert.Error(t, n.AppendPath("//e"))
+	assert.Error(t, n.AppendPath("/e//f"))
+	assert.Error(t, n.AppendPath("/e/f/"))
+	assert.Error(t, n.AppendPath("/e/%zz"))
+	assert.Equal(t, 5, n.Size())
+}
+
+func TestNameFromString(t *testing.T) {
+	n, err := NameFromString("/")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n.Size())
+
+	n, err = NameFromString("/go/8=ndn/32=metadata/221=x/v=3/seg=7/a%2Fb")
+	assert.NoError(t, err)
+	assert.Equal(t, 7, n.Size())
+	assert.Equal(t, uint16(tlv.GenericNameComponent), n.At(1).Type())
+	assert.Equal(t, uint16(tlv.KeywordNameComponent), n.At(2).Type())
+	assert.Equal(t, uint16(221), n.At(3).Type())
+	assert.Equal(t, uint16(tlv.VersionNameComponent), n.At(4).Type())
+	assert.Equal(t, uint16(tlv.SegmentNameComponent), n.At(5).Type())
+	assert.Equal(t, []byte("a/b"), n.At(6).Value())
+
+	_, err = NameFromString("/go//ndn")
+	assert.Error(t, err)
+	_, err = NameFromString("/sha256digest=0102")
+	assert.Error(t, err)
+	_, err = NameFromString("/unknown=0102")
+	assert.Error(t, err)
+}
+
+func TestNameFromStringErrorPosition(t *testing.T) {
+	for _, test := range []struct {
+		str    string
+		index  int
+		offset int
+		text   string
+	}{
+		{"/go/ndn/a%zzb", 2, 9, "%zz"},
+		{"go/ndn/a%2", 2, 8, "%2"},
+		{"/go/8=nd%G0n", 1, 8, "%G0"},
+		{"/go//ndn", 1, 4, ""},
+		{"/go/seg=abc", 1, 4, "seg=abc"},
+		{"/go/unknown=1", 1, 4, "unknown"},
+	} {
+		_, err := NameFromString(test.str)
+		var parseErr *NameParseError
+		if assert.True(t, errors.As(err, &parseErr), test.str) {
+			assert.Equal(t, test.index, parseErr.Index, test.str)
+			assert.Equal(t, test.offset, parseErr.Offset, test.str)
+			assert.Equal(t, test.text, parseErr.Text, test.str)
+			assert.Equal(t, test.text, test.str[parseErr.Offset:parseErr.Offset+len(test.text)], test.str)
+		}
+	}
+
+	_, err := NameFromString("/go/ndn/a%zzb")
+	assert.EqualError(t, err, "Name component contains invalid percent-encoding in component 2 at offset 9: \"%zz\"")
+
+	// AppendPath reports positions within the path
+	n := NewName()
+	err = n.AppendPath("a/b%1x")
+	var parseErr *NameParseError
+	assert.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, 1, parseErr.Index)
+	assert.Equal(t, 3, parseErr.Offset)
+	assert.Equal(t, 0, n.Size())
+}
+
+func TestNameMarshalJSON(t *testing.T) {
+	n, err := NameFromString("/go/32=ndn/v=3/seg=7/t=1000/seq=42/221=x")
+	assert.NoError(t, err)
+
+	encoded, err := json.Marshal(n)
+	assert.NoError(t, err)
+	assert.Equal(t, `"/go/32=ndn/v=3/seg=7/t=1000/seq=42/221=x"`, string(encoded))
+
+	decoded := new(Name)
+	assert.NoError(t, json.Unmarshal(encoded, decoded))
+	assert.True(t, n.Equals(decoded))
+	for i := 0; i < n.Size(); i++ {
+		assert.Equal(t, n.At(i).Type(), decoded.At(i).Type())
+	}
+
+	encoded, err = json.Marshal(NewName())
+	assert.NoError(t, err)
+	assert.Equal(t, `"/"`, string(encoded))
+
+	var nilName *Name
+	text, err := nilName.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "/", string(text))
+
+	keyed, err := json.Marshal(map[*Name]int{n: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"/go/32=ndn/v=3/seg=7/t=1000/seq=42/221=x":1}`, string(keyed))
+
+	assert.Error(t, json.Unmarshal([]byte(`"/go//ndn"`), decoded))
+}
+
+func TestNameMarshalBinary(t *testing.T) {
+	n, err := NameFromString("/go/ndn/v=3")
+	assert.NoError(t, err)
+
+	encoded, err := n.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x07, 0x0c, 0x08, 0x02, 0x67, 0x6f, 0x08, 0x03, 0x6e, 0x64, 0x6e, 0x23, 0x01, 0x03}, encoded)
+
+	decoded := new(Name)
+	assert.NoError(t, decoded.UnmarshalBinary(encoded))
+	assert.True(t, n.Equals(decoded))
+
+	assert.Error(t, decoded.UnmarshalBinary([]byte{0x08, 0x02, 0x67, 0x6f}))
+	assert.Error(t, decoded.UnmarshalBinary(append(encoded, 0x00)))
+	assert.Error(t, decoded.UnmarshalBinary([]byte{0x07, 0x05, 0x08}))
+
+	var buf bytes.Buffer
+	assert.NoError(t, gob.NewEncoder(&buf).Encode(n))
+	gobDecoded := new(Name)
+	assert.NoError(t, gob.NewDecoder(&buf).Decode(gobDecoded))
+	assert.True(t, n.Equals(gobDecoded))
+}
+
+func TestNameValidate(t *testing.T) {
+	digest := make([]byte, 32)
+
+	n, err := NameFromString("/go/ndn/seg=1")
+	assert.NoError(t, err)
+	assert.NoError(t, n.Validate())
+
+	n.Append(NewImplicitSha256DigestComponent(digest))
+	assert.NoError(t, n.Validate())
+	n.AppendGeneric([]byte("after"))
+	assert.Error(t, n.Validate())
+
+	// ParametersSha256DigestComponent followed by a legacy signed command Interest trailer
+	n, err = NameFromString("/go/ndn")
+	assert.NoError(t, err)
+	n.Append(NewParametersSha256DigestComponent(digest))
+	i := NewInterest(n)
+	assert.NoError(t, i.MakeLegacyCommand(NewSha256Signer()))
+	command := i.Name()
+	trailer := command.Components()[n.Size():]
+	unsigned := command.DeepCopy()
+	assert.NoError(t, unsigned.Erase(2))
+
+	withDigest := func(components ...NameComponent) *Name {
+		name, _ := NameFromString("/go/ndn")
+		name.Append(NewParametersSha256DigestComponent(digest))
+		for _, component := range components {
+			name.Append(component)
+		}
+		return name
+	}
+	for _, test := range []struct {
+		description string
+		name        *Name
+		valid       bool
+	}{
+		{"final digest", withDigest(), true},
+		{"legacy command trailer", command, true},
+		{"legacy command trailer without digest", unsigned, true},
+		{"segment", withDigest(NewSegmentNameComponent(1)), false},
+		{"generic component", withDigest(NewGenericNameComponent([]byte("after"))), false},
+		{"truncated trailer", withDigest(trailer[:3]...), false},
+		{"trailer followed by generic component", withDigest(append(trailer, NewGenericNameComponent([]byte("after")))...), false},
+		{"four generic components", withDigest(NewGenericNameComponent([]byte("a")), NewGenericNameComponent([]byte("b")), NewGenericNameComponent([]byte("c")), NewGenericNameComponent([]byte("d"))), false},
+		{"typed trailer", withDigest(NewTimestampNameComponent(1), trailer[1], trailer[2], trailer[3]), false},
+		{"second digest", withDigest(NewParametersSha256DigestComponent(digest)), false},
+		{"second digest before trailer", withDigest(append([]NameComponent{NewParametersSha256DigestComponent(digest)}, trailer...)...), false},
+	} {
+		if test.valid {
+			assert.NoError(t, test.name.Validate(), test.description)
+		} else {
+			assert.Error(t, test.name.Validate(), test.description)
+		}
+	}
+
+	// Decoding remains lenient
+	n, err = DecodeName(tlv.NewBlock(tlv.Name, append(append([]byte{tlv.ImplicitSha256DigestComponent, 0x20}, digest...), 0x08, 0x02, 0x67, 0x6f)))
+	assert.NoError(t, err)
+	assert.NotNil(t, n)
+	assert.Error(t, n.Validate())
+}
+
+func TestNameCheckDuplicateComponents(t *testing.T) {
+	n, err := NameFromString("/go/v=1/seg=0/seg=1")
+	assert.NoError(t, err)
+	assert.NoError(t, n.CheckDuplicateComponents())
+
+	n.AppendVersion(2)
+	n.Append(NewParametersSha256DigestComponent(make([]byte, 32)))
+	n.Append(NewParametersSha256DigestComponent(make([]byte, 32)))
+	assert.Error(t, n.Validate())
+	err = n.CheckDuplicateComponents()
+	assert.EqualError(t, err, "Name has multiple VersionNameComponents at indices 1, 4; multiple ParametersSha256DigestComponents at indices 5, 6")
+}
+
+func TestNameIteration(t *testing.T) {
+	n, err := NameFromString("/go/ndn/seg=1")
+	assert.NoError(t, err)
+
+	components := n.Components()
+	assert.Equal(t, 3, len(components))
+	assert.Equal(t, "go", components[0].String())
+	assert.Equal(t, "ndn", components[1].String())
+	assert.Equal(t, "seg=1", components[2].String())
+	components[0] = NewGenericNameComponent([]byte("replaced"))
+	assert.Equal(t, "go", n.At(0).String())
+
+	visited := []string{}
+	n.Each(func(i int, c NameComponent) bool {
+		visited = append(visited, c.String())
+		return i < 1
+	})
+	assert.Equal(t, []string{"go", "ndn"}, visited)
+}
+
+func TestNameCommonPrefix(t *testing.T) {
+	a, err := NameFromString("/go/ndn/v=1/seg=2")
+	assert.NoError(t, err)
+	b, err := NameFromString("/go/ndn/v=1/seg=3")
+	assert.NoError(t, err)
+	c, err := NameFromString("/ndn/go")
+	assert.NoError(t, err)
+	d, err := NameFromString("/go/ndn/seq=1")
+	assert.NoError(t, err)
+
+	common := CommonPrefix(a, b)
+	assert.Equal(t, "/go/ndn/v=1", common.String())
+	assert.False(t, common.HasWire())
+	encoded, err := common.Encode().Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(tlv.Name), encoded[0])
+
+	assert.Equal(t, "/go/ndn", CommonPrefix(a, d).String())
+	assert.Equal(t, 0, CommonPrefix(a, c).Size())
+	assert.True(t, CommonPrefix(a, a).Equals(a))
+	assert.True(t, CommonPrefix(a, a.Prefix(2)).Equals(a.Prefix(2)))
+	assert.True(t, a.Prefix(10).Equals(a))
+}
+
+func TestNameDecodeMalformed(t *testing.T) {
+	// Component length overruns the name
+	n, err := DecodeName(tlv.NewBlock(tlv.Name, []byte{0x08, 0x02, 0x67, 0x6f, 0x08, 0x05, 0x67}))
+	assert.Nil(t, n)
+	assert.Equal(t, tlv.ErrBufferTooShort, err)
+
+	// Component missing length
+	n, err = DecodeName(tlv.NewBlock(tlv.Name, []byte{0x08, 0x02, 0x67, 0x6f, 0x08}))
+	assert.Nil(t, n)
+	assert.Equal(t, tlv.ErrMissingLength, err)
+
+	// Truncated component type
+	n, err = DecodeName(tlv.NewBlock(tlv.Name, []byte{0xFD, 0x01}))
+	assert.Nil(t, n)
+	assert.Equal(t, util.ErrTooShort, err)
+
+	// Digest of the wrong length
+	n, err = DecodeName(tlv.NewBlock(tlv.Name, []byte{tlv.ImplicitSha256DigestComponent, 0x02, 0x01, 0x02}))
+	assert.Nil(t, n)
+	assert.Error(t, err)
+	n, err = DecodeName(tlv.NewBlock(tlv.Name, []byte{tlv.ParametersSha256DigestComponent, 0x02, 0x01, 0x02}))
+	assert.Nil(t, n)
+	assert.Error(t, err)
+}
+
+func TestNameDecodeLenient(t *testing.T) {
+	block := tlv.NewBlock(tlv.Name, []byte{0x08, 0x00, 0x08, 0x02, 0x67, 0x6f, 0x21, 0x03, 0x01, 0x02, 0x03, 0x08, 0x03, 0x6e, 0x64, 0x6e})
+
+	n, err := DecodeName(block)
+	assert.Nil(t, n)
+	assert.Error(t, err)
+
+	n, err = DecodeNameLenient(block, InvalidComponentError)
+	assert.Nil(t, n)
+	assert.Error(t, err)
+
+	n, err = DecodeNameLenient(block, InvalidComponentSkip)
+	assert.NoError(t, err)
+	assert.NotNil(t, n)
+	assert.Equal(t, "/go/ndn", n.String())
+
+	n, err = DecodeNameLenient(tlv.NewBlock(tlv.Name, []byte{0x08, 0x02, 0x67, 0x6f, 0x08, 0x05, 0x67}), InvalidComponentSkip)
+	assert.Nil(t, n)
+	assert.Equal(t, tlv.ErrBufferTooShort, err)
+}
+
+func TestNameDecodeOptions(t *testing.T) {
+	strict := tlv.DecodeOptions{Strict: true}
+
+	n, err := DecodeNameWithOptions(tlv.NewBlock(tlv.Name, []byte{0x08, 0x02, 0x67, 0x6f, 0x21, 0x01, 0x05}), strict)
+	assert.NoError(t, err)
+	assert.Equal(t, "/go/seg=5", n.String())
+
+	// Non-minimal NNI
+	_, err = DecodeNameWithOptions(tlv.NewBlock(tlv.Name, []byte{0x08, 0x02, 0x67, 0x6f, 0x21, 0x02, 0x00, 0x05}), strict)
+	assert.Error(t, err)
+	_, err = DecodeNameWithOptions(tlv.NewBlock(tlv.Name, []byte{0x08, 0x02, 0x67, 0x6f, 0x21, 0x02, 0x00, 0x05}), tlv.DecodeOptions{})
+	assert.NoError(t, err)
+
+	// Non-minimal TLV-LENGTH of a component
+	_, err = DecodeNameWithOptions(tlv.NewBlock(tlv.Name, []byte{0x08, 0xfd, 0x00, 0x02, 0x67, 0x6f}), strict)
+	assert.Error(t, err)
+	_, err = DecodeNameWithOptions(tlv.NewBlock(tlv.Name, []byte{0x08, 0xfd, 0x00, 0x02, 0x67, 0x6f}), tlv.DecodeOptions{})
+	assert.NoError(t, err)
+}
+
+func TestNameComponentCompare(t *testing.T) {
+	goComponent := NewGenericNameComponent([]byte("go"))
+	goCopy := goComponent.DeepCopy()
+	ndnComponent := NewGenericNameComponent([]byte("ndn"))
+	gpComponent := NewGenericNameComponent([]byte("gp"))
+	keywordComponent := NewKeywordNameComponent([]byte("go"))
+	segComponent := NewSegmentNameComponent(1)
+
+	assert.True(t, goComponent.Equals(goCopy))
+	assert.False(t, goComponent.Equals(keywordComponent))
+	assert.False(t, goComponent.Equals(gpComponent))
+	assert.False(t, goComponent.Equals(nil))
+
+	// Equal
+	assert.Equal(t, 0, goComponent.Compare(goCopy))
+	// Type differs
+	assert.Equal(t, -1, goComponent.Compare(keywordComponent))
+	assert.Equal(t, 1, keywordComponent.Compare(goComponent))
+	assert.Equal(t, 1, segComponent.Compare(ndnComponent))
+	// Length differs
+	assert.Equal(t, -1, goComponent.Compare(ndnComponent))
+	assert.Equal(t, 1, ndnComponent.Compare(goComponent))
+	// Value differs
+	assert.Equal(t, -1, goComponent.Compare(gpComponent))
+	assert.Equal(t, 1, gpComponent.Compare(goComponent))
+}
+
+func TestNameComponentValueCopy(t *testing.T) {
+	n, err := NameFromString("/go/ndn")
+	assert.NoError(t, err)
+	n.Encode()
+
+	value := n.At(0).Value()
+	value[0] = 'x'
+	assert.Equal(t, []byte("go"), n.At(0).Value())
+	assert.Equal(t, "/go/ndn", n.String())
+	wire, err := n.Encode().Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{tlv.Name, 0x09, tlv.GenericNameComponent, 0x02, 0x67, 0x6f, tlv.GenericNameComponent, 0x03, 0x6e, 0x64, 0x6e}, wire)
+
+	n.Set(0, NewGenericNameComponent([]byte("go")))
+	wire, err = n.Encode().Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{tlv.Name, 0x09, tlv.GenericNameComponent, 0x02, 0x67, 0x6f, tlv.GenericNameComponent, 0x03, 0x6e, 0x64, 0x6e}, wire)
+}
+
+func TestNameComponentSetValueWire(t *testing.T) {
+	digest := make([]byte, 32)
+	digest[0] = 0x01
+
+	implicit := NewImplicitSha256DigestComponent(make([]byte, 32))
+	implicit.Encode()
+	assert.NoError(t, implicit.SetValue(digest))
+	assert.Equal(t, digest, implicit.Encode().Value())
+
+	params := NewParametersSha256DigestComponent(make([]byte, 32))
+	params.Encode()
+	assert.NoError(t, params.SetValue(digest))
+	assert.Equal(t, digest, params.Encode().Value())
+
+	generic := NewGenericNameComponent([]byte("go"))
+	generic.Encode()
+	generic.SetValue([]byte("ndn"))
+	assert.Equal(t, []byte("ndn"), generic.Encode().Value())
+
+	keyword := NewKeywordNameComponent([]byte("go"))
+	keyword.Encode()
+	keyword.SetValue([]byte("ndn"))
+	assert.Equal(t, []byte("ndn"), keyword.Encode().Value())
+
+	segment := NewSegmentNameComponent(1)
+	segment.Encode()
+	segment.SetValue(0x0102)
+	assert.Equal(t, []byte{0x01, 0x02}, segment.Encode().Value())
+
+	byteOffset := NewByteOffsetNameComponent(1)
+	byteOffset.Encode()
+	byteOffset.SetValue(0x0102)
+	assert.Equal(t, []byte{0x01, 0x02}, byteOffset.Encode().Value())
+
+	version := NewVersionNameComponent(1)
+	version.Encode()
+	version.SetValue(0x0102)
+	assert.Equal(t, []byte{0x01, 0x02}, version.Encode().Value())
+
+	timestamp := NewTimestampNameComponent(1)
+	timestamp.Encode()
+	timestamp.SetValue(0x0102)
+	assert.Equal(t, []byte{0x01, 0x02}, timestamp.Encode().Value())
+
+	sequenceNum := NewSequenceNumNameComponent(1)
+	sequenceNum.Encode()
+	sequenceNum.SetValue(0x0102)
+	assert.Equal(t, []byte{0x01, 0x02}, sequenceNum.Encode().Value())
+
+	// Changing a component in place invalidates the wire of the name
+	n, err := DecodeName(tlv.NewBlock(tlv.Name, []byte{0x08, 0x02, 0x67, 0x6f, 0x21, 0x01, 0x05}))
+	assert.NoError(t, err)
+	other, err := NameFromString("/go/seg=5")
+	assert.NoError(t, err)
+	other.Encode()
+	assert.True(t, n.HasWire())
+	assert.True(t, n.Equals(other))
+
+	n.At(1).(*SegmentNameComponent).SetValue(6)
+	assert.False(t, n.HasWire())
+	assert.False(t, n.Equals(other))
+	assert.False(t, n.PrefixOf(other))
+	wire, err := n.Encode().Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{tlv.Name, 0x07, 0x08, 0x02, 0x67, 0x6f, 0x21, 0x01, 0x06}, wire)
+	assert.True(t, n.HasWire())
+	assert.Equal(t, "/go/seg=6", n.String())
+}
+
+func TestNameLimits(t *testing.T) {
+	maxComponents, maxSize := NameLimits()
+	assert.Equal(t, DefaultMaxNameComponents, maxComponents)
+	assert.Equal(t, DefaultMaxNameSize, maxSize)
+	defer SetNameLimits(maxComponents, maxSize)
+	assert.Equal(t, util.ErrOutOfRange, SetNameLimits(0, 16))
+	assert.Equal(t, util.ErrOutOfRange, SetNameLimits(3, -1))
+	assert.NoError(t, SetNameLimits(3, 16))
+
+	n := NewName()
+	assert.NoError(t, n.TryAppend(NewGenericNameComponent([]byte("go"))))
+	assert.NoError(t, n.TryAppend(NewGenericNameComponent([]byte("ndn"))))
+	assert.Equal(t, util.ErrTooLong, n.TryAppend(NewGenericNameComponent([]byte("too-long"))))
+	assert.Equal(t, "/go/ndn", n.String())
+	assert.Equal(t, util.ErrTooLong, n.AppendPath("/a/b"))
+	assert.Equal(t, "/go/ndn", n.String())
+	assert.NoError(t, n.TryAppend(NewGenericNameComponent([]byte("a"))))
+	assert.Equal(t, util.ErrTooLong, n.TryAppend(NewGenericNameComponent([]byte("b"))))
+	assert.Equal(t, util.ErrTooLong, n.Insert(0, NewGenericNameComponent([]byte("b"))))
+	assert.Equal(t, "/go/ndn/a", n.String())
+
+	// The running size follows erased, replaced, and modified components
+	assert.NoError(t, n.Erase(2))
+	assert.NoError(t, n.Set(1, NewGenericNameComponent([]byte("n"))))
+	assert.NoError(t, n.TryAppend(NewGenericNameComponent([]byte("abc"))))
+	assert.Equal(t, "/go/n/abc", n.String())
+	assert.NoError(t, n.Erase(2))
+	n.At(1).(*GenericNameComponent).SetValue([]byte("ndn"))
+	n.Encode()
+	assert.Equal(t, util.ErrTooLong, n.TryAppend(NewGenericNameComponent([]byte("abcd"))))
+	assert.NoError(t, n.TryAppend(NewGenericNameComponent([]byte("a"))))
+	assert.Equal(t, util.ErrTooLong, n.Prefix(2).TryAppend(NewGenericNameComponent([]byte("abcd"))))
+	assert.NoError(t, n.Prefix(2).TryAppend(NewGenericNameComponent([]byte("abc"))))
+	assert.Equal(t, util.ErrTooLong, n.DeepCopy().Insert(0, NewGenericNameComponent([]byte("b"))))
+
+	// Decoding
+	_, err := DecodeName(n.Encode())
+	assert.NoError(t, err)
+	assert.NoError(t, SetNameLimits(2, 16))
+	_, err = DecodeName(n.Encode())
+	assert.Equal(t, util.ErrTooLong, err)
+	assert.NoError(t, SetNameLimits(3, 10))
+	_, err = DecodeName(n.Encode())
+	assert.Equal(t, util.ErrTooLong, err)
+}
+
+func TestNameMarshalTLV(t *testing.T) {
+	type withName struct {
+		_    struct{} `tlv:"0x80"`
+		Name *Name    `tlv:"7"`
+	}
+
+	n, _ := NameFromString("/go/ndn")
+	block, err := tlv.Marshal(&withName{Name: n})
+	assert.NoError(t, err)
+	wire, err := block.Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x80, 0x0b, tlv.Name, 0x09, tlv.GenericNameComponent, 0x02, 0x67, 0x6f, tlv.GenericNameComponent, 0x03, 0x6e, 0x64, 0x6e}, wire)
+
+	var decoded withName
+	decodedBlock, _, err := tlv.DecodeBlock(wire)
+	assert.NoError(t, err)
+	assert.NoError(t, tlv.Unmarshal(decodedBlock, &decoded))
+	assert.True(t, n.Equals(decoded.Name))
+
+	_, err = tlv.Marshal(&withName{})
+	assert.Error(t, err)
+}
+
+func TestNameComponentMinimalNNI(t *testing.T) {
+	segment := NewSegmentNameComponent(5)
+	wire, err := segment.Encode().Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{tlv.SegmentNameComponent, 0x01, 0x05}, wire)
+	assert.Equal(t, "seg=5", segment.String())
+
+	segment.SetValue(0x0100)
+	wire, err = segment.Encode().Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{tlv.SegmentNameComponent, 0x02, 0x01, 0x00}, wire)
+	assert.Equal(t, "seg=256", segment.String())
+
+	// Non-minimal encodings are accepted and compare equal to the minimal form
+	decoded, err := DecodeNameComponent(tlv.NewBlock(tlv.SegmentNameComponent, []byte{0x00, 0x00, 0x01, 0x00}))
+	assert.NoError(t, err)
+	assert.True(t, segment.Equals(decoded))
+}
+
+func TestNameTypedAccessors(t *testing.T) {
+	n := NewName()
+	assert.Nil(t, n.Last())
+	_, ok := n.Segment(0)
+	assert.False(t, ok)
+
+	n.AppendGeneric([]byte("go")).AppendVersion(3).AppendTimestamp(1000).AppendSequenceNum(42).Append(NewByteOffsetNameComponent(0x0100)).AppendSegment(7)
+	assert.Equal(t, "seg=7", n.Last().String())
+
+	segment, ok := n.Segment(n.Size() - 1)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(7), segment)
+	version, ok := n.Version(1)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(3), version)
+	timestamp, ok := n.Timestamp(2)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1000), timestamp)
+	seq, ok := n.SequenceNum(3)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(42), seq)
+	offset, ok := n.ByteOffset(4)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(0x0100), offset)
+
+	// Wrong type or out of range
+	_, ok = n.Segment(0)
+	assert.False(t, ok)
+	_, ok = n.Version(2)
+	assert.False(t, ok)
+	_, ok = n.Segment(-1)
+	assert.False(t, ok)
+	_, ok = n.Segment(n.Size())
+	assert.False(t, ok)
+
+	// Non-minimal encoding
+	decoded, err := DecodeName(tlv.NewBlock(tlv.Name, []byte{tlv.VersionNameComponent, 0x04, 0x00, 0x00, 0x01, 0x00}))
+	assert.NoError(t, err)
+	version, ok = decoded.Version(0)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(0x0100), version)
+}
+
+func TestNameEqualsWire(t *testing.T) {
+	n, err := NameFromString("/go/ndn/seg=5")
+	assert.NoError(t, err)
+	n.Encode()
+	assert.True(t, n.HasWire())
+
+	// Canonical wires are compared directly
+	decoded, err := DecodeName(tlv.NewBlock(tlv.Name, []byte{0x08, 0x02, 0x67, 0x6f, 0x08, 0x03, 0x6e, 0x64, 0x6e, 0x21, 0x01, 0x05}))
+	assert.NoError(t, err)
+	assert.True(t, decoded.HasWire())
+	assert.True(t, n.Equals(decoded))
+	assert.True(t, decoded.Equals(n))
+	assert.True(t, n.Prefix(2).PrefixOf(decoded))
+	prefix := n.Prefix(2)
+	prefix.Encode()
+	assert.True(t, prefix.PrefixOf(decoded))
+	assert.False(t, decoded.PrefixOf(prefix))
+
+	// A byte-level prefix that ends inside a component is not a name prefix
+	partial, err := NameFromString("/go/nd")
+	assert.NoError(t, err)
+	partial.Encode()
+	assert.False(t, partial.PrefixOf(decoded))
+
+	// Names received with non-minimal encodings still compare by component
+	nonMinimal, err := DecodeName(tlv.NewBlock(tlv.Name, []byte{0x08, 0x02, 0x67, 0x6f, 0x08, 0xfd, 0x00, 0x03, 0x6e, 0x64, 0x6e, 0x21, 0x02, 0x00, 0x05}))
+	assert.NoError(t, err)
+	assert.True(t, nonMinimal.HasWire())
+	assert.True(t, n.Equals(nonMinimal))
+	assert.True(t, nonMinimal.Equals(n))
+	assert.True(t, prefix.PrefixOf(nonMinimal))
+
+	// Names without cached wires compare by component
+	other := n.DeepCopy()
+	assert.False(t, other.HasWire())
+	assert.True(t, n.Equals(other))
+	other.AppendGeneric([]byte("x"))
+	other.Encode()
+	assert.False(t, n.Equals(other))
+	assert.True(t, n.PrefixOf(other))
+}
+
+func benchmarkLongName() *Name {
+	n := NewName()
+	for i := 0; i < 32; i++ {
+		n.AppendGeneric([]byte("component"))
+	}
+	n.AppendSegment(1000)
+	return n
+}
+
+func BenchmarkNameEqualsComponents(b *testing.B) {
+	n1 := benchmarkLongName()
+	n2 := benchmarkLongName()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !n1.Equals(n2) {
+			b.Fatal("names not equal")
+		}
+	}
+}
+
+func BenchmarkNameEqualsWire(b *testing.B) {
+	n1 := benchmarkLongName()
+	n2 := benchmarkLongName()
+	n1.Encode()
+	n2.Encode()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !n1.Equals(n2) {
+			b.Fatal("names not equal")
+		}
+	}
+}
+
+func TestDecodeNameFromBuffer(t *testing.T) {
+	buf := []byte{tlv.Name, 0x08, 0x08, 0x02, 0x67, 0x6f, 0x21, 0x02, 0x01, 0x00, tlv.Nonce, 0x04, 0x01, 0x02, 0x03, 0x04}
+	n, consumed, err := DecodeNameFromBuffer(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, consumed)
+	assert.Equal(t, "/go/seg=256", n.String())
+	assert.True(t, n.HasWire())
+	wire, err := n.Encode().Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, buf[:10], wire)
+
+	// The decoded name does not reference the buffer
+	buf[4] = 'G'
+	assert.Equal(t, "/go/seg=256", n.String())
+
+	// Non-minimal encodings compare equal to minimal ones
+	nonMinimal, consumed, err := DecodeNameFromBuffer([]byte{tlv.Name, 0xfd, 0x00, 0x06, 0x08, 0xfd, 0x00, 0x02, 0x67, 0x6f})
+	assert.NoError(t, err)
+	assert.Equal(t, 10, consumed)
+	expected, _ := NameFromString("/go")
+	expected.Encode()
+	assert.True(t, nonMinimal.Equals(expected))
+	assert.True(t, expected.Equals(nonMinimal))
+
+	// Empty name
+	n, consumed, err = DecodeNameFromBuffer([]byte{tlv.Name, 0x00})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, consumed)
+	assert.Equal(t, 0, n.Size())
+
+	// Leading element must be a Name
+	n, consumed, err = DecodeNameFromBuffer([]byte{tlv.Interest, 0x00})
+	assert.Nil(t, n)
+	assert.Equal(t, 0, consumed)
+	assert.Equal(t, tlv.ErrUnexpected, err)
+
+	// Truncated name
+	n, _, err = DecodeNameFromBuffer([]byte{tlv.Name, 0x04, 0x08, 0x02, 0x67})
+	assert.Nil(t, n)
+	assert.Error(t, err)
+}
+
+func TestNameStringURI(t *testing.T) {
+	assert.Equal(t, "/", NewName().StringURI())
+
+	n := NewName()
+	n.AppendGeneric([]byte("go-ndn_2.0~"))
+	n.AppendGeneric([]byte{0x00, 0x0a, 0xff, '/', '%', '=', ' '})
+	n.AppendKeyword("key word")
+	n.AppendVersion(3)
+	n.AppendSegment(10)
+	n.Append(NewBaseNameComponent(0x7f20, []byte("a/b")))
+	assert.Equal(t, "/go-ndn_2.0~/%00%0A%FF%2F%25%3D%20/32=key%20word/v=3/seg=10/32544=a%2Fb", n.StringURI())
+
+	// String is unchanged and keeps raw bytes
+	assert.Equal(t, "/go-ndn_2.0~/"+string([]byte{0x00, 0x0a, 0xff, '/', '%', '=', ' '})+"/32=key word/v=3/seg=10/32544=a/b", n.String())
+
+	// The URI form round trips through NameFromString
+	parsed, err := NameFromString(n.StringURI())
+	assert.NoError(t, err)
+	assert.True(t, n.Equals(parsed))
+
+	// Text marshaling uses the URI form
+	text, err := n.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, n.StringURI(), string(text))
+	var unmarshaled Name
+	assert.NoError(t, unmarshaled.UnmarshalText(text))
+	assert.True(t, n.Equals(&unmarshaled))
+}
+
+func TestNameWriteTo(t *testing.T) {
+	n, err := NameFromString("/go/ndn/seg=1")
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	written, err := n.WriteTo(&buf)
+	assert.NoError(t, err)
+	wire, err := n.Encode().Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(wire)), written)
+	assert.Equal(t, wire, buf.Bytes())
+
+	// Cached wire is written without allocating
+	allocs := testing.AllocsPerRun(100, func() {
+		n.WriteTo(ioutil.Discard)
+	})
+	assert.Equal(t, 0.0, allocs)
+
+	// Modified names are re-encoded
+	n.At(0).(*GenericNameComponent).SetValue([]byte("GO"))
+	buf.Reset()
+	_, err = n.WriteTo(&buf)
+	assert.NoError(t, err)
+	decoded, err := DecodeName(tlv.NewBlock(tlv.Name, buf.Bytes()[2:]))
+	assert.NoError(t, err)
+	assert.Equal(t, "/GO/ndn/seg=1", decoded.String())
+}
+
+func BenchmarkNameWriteTo(b *testing.B) {
+	n, _ := NameFromString("/go/ndn/edu/ucla/seg=1")
+	n.WriteTo(ioutil.Discard)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		n.WriteTo(ioutil.Discard)
+	}
+}
+
+func BenchmarkNameEncodeWrite(b *testing.B) {
+	n, _ := NameFromString("/go/ndn/edu/ucla/seg=1")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		wire, _ := n.Encode().Wire()
+		ioutil.Discard.Write(wire)
+	}
+}
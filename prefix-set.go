@@ -0,0 +1,99 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn
+
+import "sync"
+
+// PrefixSet tracks the prefixes registered by a producer, determining which of them need to be registered with the forwarder. A prefix covered by an ancestor in the set does not need to be registered, as Interests under it already reach the producer. A separate PrefixSet should be used for each combination of face and strategy.
+type PrefixSet struct {
+	prefixes map[string]*Name
+	lock     sync.Mutex
+}
+
+// NewPrefixSet creates an empty PrefixSet.
+func NewPrefixSet() *PrefixSet {
+	p := new(PrefixSet)
+	p.prefixes = make(map[string]*Name)
+	return p
+}
+
+// isCovered returns whether a proper ancestor of the specified name is in the set.
+func (p *PrefixSet) isCovered(name *Name) bool {
+	for i := 0; i < name.Size(); i++ {
+		if _, ok := p.prefixes[name.Prefix(i).String()]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Add adds a prefix to the set, returning whether it needs to be registered. This is false if the prefix is already in the set or is covered by an ancestor in the set.
+func (p *PrefixSet) Add(name *Name) (needsRegister bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	key := name.String()
+	if _, ok := p.prefixes[key]; ok {
+		return false
+	}
+	p.prefixes[key] = name.DeepCopy()
+	return !p.isCovered(name)
+}
+
+// Remove removes a prefix from the set, returning whether it needs to be unregistered and the prefixes it masked that now need to be registered. If the prefix is not in the set, no action is taken.
+func (p *PrefixSet) Remove(name *Name) (needsUnregister bool, reregister []*Name) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	key := name.String()
+	if _, ok := p.prefixes[key]; !ok {
+		return false, nil
+	}
+	delete(p.prefixes, key)
+	if p.isCovered(name) {
+		return false, nil
+	}
+
+	for _, prefix := range p.prefixes {
+		if name.PrefixOf(prefix) && !p.isCovered(prefix) {
+			reregister = append(reregister, prefix.DeepCopy())
+		}
+	}
+	return true, reregister
+}
+
+// Has returns whether the specified prefix is in the set.
+func (p *PrefixSet) Has(name *Name) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	_, ok := p.prefixes[name.String()]
+	return ok
+}
+
+// Registered returns the prefixes in the set that need to be registered, i.e., those not covered by an ancestor in the set.
+func (p *PrefixSet) Registered() []*Name {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	registered := make([]*Name, 0, len(p.prefixes))
+	for _, prefix := range p.prefixes {
+		if !p.isCovered(prefix) {
+			registered = append(registered, prefix.DeepCopy())
+		}
+	}
+	return registered
+}
+
+// Size returns the number of prefixes in the set.
+func (p *PrefixSet) Size() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return len(p.prefixes)
+}
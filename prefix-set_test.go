@@ -0,0 +1,80 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn_test
+
+import (
+	"sort"
+	"testing"
+
+	ndn "github.com/eric135/go-ndn2"
+	"github.com/stretchr/testify/assert"
+)
+
+func prefixStrings(names []*ndn.Name) []string {
+	strs := make([]string, 0, len(names))
+	for _, name := range names {
+		strs = append(strs, name.String())
+	}
+	sort.Strings(strs)
+	return strs
+}
+
+func TestPrefixSet(t *testing.T) {
+	p := ndn.NewPrefixSet()
+	a, _ := ndn.NameFromString("/a")
+	ab, _ := ndn.NameFromString("/a/b")
+	abc, _ := ndn.NameFromString("/a/b/c")
+	ad, _ := ndn.NameFromString("/a/d")
+	ax, _ := ndn.NameFromString("/ax")
+
+	assert.True(t, p.Add(ab))
+	assert.False(t, p.Add(ab))
+	assert.False(t, p.Add(abc))
+	assert.True(t, p.Add(a))
+	assert.False(t, p.Add(ad))
+	// Not covered, as /a is not a name prefix of /ax
+	assert.True(t, p.Add(ax))
+	assert.Equal(t, 5, p.Size())
+	assert.True(t, p.Has(abc))
+	assert.Equal(t, []string{"/a", "/ax"}, prefixStrings(p.Registered()))
+
+	// Removing a covered prefix requires no changes
+	needsUnregister, reregister := p.Remove(ad)
+	assert.False(t, needsUnregister)
+	assert.Empty(t, reregister)
+
+	// Removing the covering prefix unmasks its nearest descendants
+	needsUnregister, reregister = p.Remove(a)
+	assert.True(t, needsUnregister)
+	assert.Equal(t, []string{"/a/b"}, prefixStrings(reregister))
+	assert.Equal(t, []string{"/a/b", "/ax"}, prefixStrings(p.Registered()))
+
+	needsUnregister, reregister = p.Remove(ab)
+	assert.True(t, needsUnregister)
+	assert.Equal(t, []string{"/a/b/c"}, prefixStrings(reregister))
+
+	// Not in the set
+	needsUnregister, reregister = p.Remove(a)
+	assert.False(t, needsUnregister)
+	assert.Empty(t, reregister)
+	assert.False(t, p.Has(a))
+	assert.Equal(t, 2, p.Size())
+}
+
+func TestPrefixSetRoot(t *testing.T) {
+	p := ndn.NewPrefixSet()
+	a, _ := ndn.NameFromString("/a")
+	assert.True(t, p.Add(a))
+	assert.True(t, p.Add(ndn.NewName()))
+	assert.False(t, p.Add(a))
+	assert.Equal(t, []string{"/"}, prefixStrings(p.Registered()))
+
+	needsUnregister, reregister := p.Remove(ndn.NewName())
+	assert.True(t, needsUnregister)
+	assert.Equal(t, []string{"/a"}, prefixStrings(reregister))
+}
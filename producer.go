@@ -0,0 +1,84 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn
+
+import (
+	"errors"
+	"time"
+
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/eric135/go-ndn2/util"
+)
+
+// RdrMetadataKeyword is the value of the KeywordNameComponent that identifies Realtime Data Retrieval (RDR) metadata packets.
+const RdrMetadataKeyword = "metadata"
+
+// DefaultRdrMetadataFreshnessPeriod is the FreshnessPeriod of RDR metadata packets, which is kept short so that consumers discover new versions promptly.
+const DefaultRdrMetadataFreshnessPeriod = 10 * time.Millisecond
+
+// ProduceSegmented splits the content into segments of at most segmentSize octets, the last of which may be shorter, and returns a signed Data packet for each. Each packet is named by appending a SegmentNameComponent to the specified name (which should usually end in a VersionNameComponent) and has a FinalBlockId containing the number of the last segment. Empty content produces a single empty segment 0. If the signer is nil, the segments are signed with DigestSha256.
+func ProduceSegmented(name *Name, content []byte, segmentSize int, signer Signer) ([]*Data, error) {
+	if name == nil {
+		return nil, util.ErrNonExistent
+	}
+	if segmentSize <= 0 {
+		return nil, util.ErrOutOfRange
+	}
+
+	numSegments := (len(content) + segmentSize - 1) / segmentSize
+	if numSegments == 0 {
+		numSegments = 1
+	}
+	finalBlockID := NewSegmentNameComponent(uint64(numSegments - 1))
+
+	segments := make([]*Data, numSegments)
+	for i := range segments {
+		start := i * segmentSize
+		end := start + segmentSize
+		if end > len(content) {
+			end = len(content)
+		}
+
+		d, err := NewDataBuilder().
+			Name(name.DeepCopy().AppendSegment(uint64(i))).
+			Content(content[start:end]).
+			FinalBlockID(finalBlockID).
+			Sign(signer).
+			Build()
+		if err != nil {
+			return nil, err
+		}
+		segments[i] = d
+	}
+	return segments, nil
+}
+
+// MakeRdrMetadata creates a signed RDR metadata packet announcing the specified versioned name, which must end in a VersionNameComponent, as the latest version of the content under its prefix. The metadata packet is named /<prefix>/32=metadata/<version>/<segment=0>, where the version is the current time in milliseconds, and contains the encoded versioned name. If the signer is nil, the packet is signed with DigestSha256.
+func MakeRdrMetadata(versionedName *Name, signer Signer) (*Data, error) {
+	if versionedName == nil {
+		return nil, util.ErrNonExistent
+	}
+	if last := versionedName.Last(); last == nil || last.Type() != tlv.VersionNameComponent {
+		return nil, errors.New("RDR metadata must announce a versioned name")
+	}
+
+	nameWire, err := versionedName.Encode().Wire()
+	if err != nil {
+		return nil, err
+	}
+	metadataName := versionedName.Prefix(versionedName.Size() - 1).
+		AppendKeyword(RdrMetadataKeyword).
+		AppendVersion(uint64(time.Now().UnixNano() / int64(time.Millisecond))).
+		AppendSegment(0)
+	return NewDataBuilder().
+		Name(metadataName).
+		Content(nameWire).
+		FreshnessPeriod(DefaultRdrMetadataFreshnessPeriod).
+		Sign(signer).
+		Build()
+}
@@ -0,0 +1,86 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn_test
+
+import (
+	"bytes"
+	"testing"
+
+	ndn "github.com/eric135/go-ndn2"
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProduceSegmented(t *testing.T) {
+	name := mustName(t, "/go/file/v=3")
+	content := bytes.Repeat([]byte{0x01, 0x02, 0x03}, 334)
+	segments, err := ndn.ProduceSegmented(name, content, 100, nil)
+	assert.NoError(t, err)
+	assert.Len(t, segments, 11)
+
+	var reassembled []byte
+	for i, d := range segments {
+		segment, ok := d.Name().Segment(3)
+		assert.True(t, ok)
+		assert.Equal(t, uint64(i), segment)
+		assert.True(t, name.PrefixOf(d.Name()))
+		assert.Equal(t, "seg=10", d.MetaInfo().FinalBlockID().String())
+		assert.Equal(t, uint64(ndn.SignatureDigestSha256), d.SignatureInfo().SignatureType())
+		assert.NoError(t, d.Verify(ndn.NewSha256Verifier()))
+		reassembled = append(reassembled, d.Content()...)
+	}
+	assert.Equal(t, 2, len(segments[10].Content()))
+	assert.Equal(t, content, reassembled)
+
+	// Content that divides evenly
+	segments, err = ndn.ProduceSegmented(name, content[:200], 100, nil)
+	assert.NoError(t, err)
+	assert.Len(t, segments, 2)
+	assert.Equal(t, "seg=1", segments[1].MetaInfo().FinalBlockID().String())
+
+	// Empty content
+	segments, err = ndn.ProduceSegmented(name, nil, 100, nil)
+	assert.NoError(t, err)
+	assert.Len(t, segments, 1)
+	assert.Equal(t, "/go/file/v=3/seg=0", segments[0].Name().String())
+	assert.Equal(t, "seg=0", segments[0].MetaInfo().FinalBlockID().String())
+	assert.Empty(t, segments[0].Content())
+
+	// Invalid arguments
+	_, err = ndn.ProduceSegmented(nil, content, 100, nil)
+	assert.Error(t, err)
+	_, err = ndn.ProduceSegmented(name, content, 0, nil)
+	assert.Error(t, err)
+	_, err = ndn.ProduceSegmented(name, content, 100, new(failingSigner))
+	assert.Error(t, err)
+}
+
+func TestMakeRdrMetadata(t *testing.T) {
+	d, err := ndn.MakeRdrMetadata(mustName(t, "/go/file/v=3"), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, d.Name().Size())
+	assert.True(t, mustName(t, "/go/file/32=metadata").PrefixOf(d.Name()))
+	_, ok := d.Name().Version(3)
+	assert.True(t, ok)
+	segment, ok := d.Name().Segment(4)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(0), segment)
+	assert.Equal(t, ndn.DefaultRdrMetadataFreshnessPeriod, *d.MetaInfo().FreshnessPeriod())
+
+	block, _, err := tlv.DecodeBlock(d.Content())
+	assert.NoError(t, err)
+	announced, err := ndn.DecodeName(block)
+	assert.NoError(t, err)
+	assert.Equal(t, "/go/file/v=3", announced.String())
+
+	// Unversioned names cannot be announced
+	_, err = ndn.MakeRdrMetadata(mustName(t, "/go/file"), nil)
+	assert.Error(t, err)
+	_, err = ndn.MakeRdrMetadata(nil, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,169 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"errors"
+
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/eric135/go-ndn2/util"
+)
+
+// ExportSafeBag encodes a certificate and its private key into a SafeBag, the format used by ndnsec to transfer keys. The private key is stored as a PKCS #8 EncryptedPrivateKeyInfo protected by the specified passphrase.
+func ExportSafeBag(certificate *Certificate, key crypto.PrivateKey, passphrase []byte) ([]byte, error) {
+	if certificate == nil || key == nil {
+		return nil, util.ErrNonExistent
+	}
+	if err := checkKeyMatchesCertificate(key, certificate); err != nil {
+		return nil, err
+	}
+
+	privateKeyInfo, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	encryptedKey, err := encryptPKCS8(privateKeyInfo, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	certificateBlock, err := certificate.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	wire := tlv.NewEmptyBlock(tlv.SafeBag)
+	wire.Append(certificateBlock)
+	wire.Append(tlv.NewBlock(tlv.EncryptedKeyBag, encryptedKey))
+	return wire.Wire()
+}
+
+// ImportSafeBag decodes a SafeBag, decrypting its private key with the specified passphrase, and returns a signer using the key along with the certificate. Only ECDSA keys are supported.
+func ImportSafeBag(data []byte, passphrase []byte) (Signer, *Certificate, error) {
+	key, certificate, err := decodeSafeBag(data, passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+	return NewEcdsaSigner(certificate.KeyName(), key), certificate, nil
+}
+
+// decodeSafeBag decodes a SafeBag containing an ECDSA private key and its certificate.
+func decodeSafeBag(data []byte, passphrase []byte) (*ecdsa.PrivateKey, *Certificate, error) {
+	wire, err := decodeSingleBlock(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if wire.Type() != tlv.SafeBag {
+		return nil, nil, tlv.ErrUnexpected
+	}
+	if !wire.Parse() {
+		return nil, nil, tlv.ErrBufferTooShort
+	}
+
+	var certificate *Certificate
+	var encryptedKey []byte
+	mostRecentElem := 0
+	for _, elem := range wire.Subelements() {
+		switch elem.Type() {
+		case tlv.Data:
+			if mostRecentElem >= 1 {
+				return nil, nil, errors.New("Certificate is duplicate or out-of-order")
+			}
+			mostRecentElem = 1
+			if certificate, err = DecodeCertificate(elem); err != nil {
+				return nil, nil, err
+			}
+		case tlv.EncryptedKeyBag:
+			if mostRecentElem >= 2 {
+				return nil, nil, errors.New("EncryptedKeyBag is duplicate or out-of-order")
+			}
+			mostRecentElem = 2
+			encryptedKey = elem.Value()
+		default:
+			if tlv.IsCritical(elem.Type()) {
+				return nil, nil, tlv.ErrUnrecognizedCritical
+			}
+			// If non-critical, ignore
+		}
+	}
+	if certificate == nil || encryptedKey == nil {
+		return nil, nil, errors.New("SafeBag must contain a certificate and an EncryptedKeyBag")
+	}
+
+	privateKeyInfo, err := decryptPKCS8(encryptedKey, passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+	parsedKey, err := x509.ParsePKCS8PrivateKey(privateKeyInfo)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, ok := parsedKey.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, nil, errors.New("Unsupported private key type")
+	}
+	if err := checkKeyMatchesCertificate(key, certificate); err != nil {
+		return nil, nil, err
+	}
+	return key, certificate, nil
+}
+
+// checkKeyMatchesCertificate checks that the private key corresponds to the public key in the certificate.
+func checkKeyMatchesCertificate(key crypto.PrivateKey, certificate *Certificate) error {
+	publicKey, err := certificate.PublicKey()
+	if err != nil {
+		return err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return errors.New("Unsupported private key type")
+	}
+	if comparable, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !comparable.Equal(publicKey) {
+		return errors.New("Private key does not match certificate")
+	}
+	return nil
+}
+
+// ImportSafeBag adds the key and certificate in a SafeBag to the KeyChain as a new identity, returning the certificate.
+func (k *KeyChain) ImportSafeBag(data []byte, passphrase []byte) (*Certificate, error) {
+	key, certificate, err := decodeSafeBag(data, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if key.Curve.Params().Name != "P-256" {
+		return nil, errors.New("KeyChain only supports ECDSA P-256 keys")
+	}
+
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	name := certificate.Identity()
+	if _, ok := k.identities[name.String()]; ok {
+		return nil, errors.New("Identity already exists")
+	}
+	identity := &keyChainIdentity{key: key, certificate: certificate}
+	if err := k.saveIdentity(identity); err != nil {
+		return nil, err
+	}
+	k.identities[name.String()] = identity
+	return certificate.DeepCopy(), nil
+}
+
+// ExportSafeBag encodes the key and certificate of the specified identity into a SafeBag protected by the specified passphrase.
+func (k *KeyChain) ExportSafeBag(identity *Name, passphrase []byte) ([]byte, error) {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+
+	i, ok := k.identities[identity.String()]
+	if !ok {
+		return nil, util.ErrNonExistent
+	}
+	return ExportSafeBag(i.certificate, i.key, passphrase)
+}
@@ -0,0 +1,181 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/eric135/go-ndn2/util"
+)
+
+// Signature types.
+const (
+	SignatureDigestSha256    = 0
+	SignatureSha256WithRsa   = 1
+	SignatureSha256WithEcdsa = 3
+	SignatureHmacWithSha256  = 4
+	SignatureEd25519         = 5
+)
+
+// SignatureInfo contains information about the signature of a packet.
+type SignatureInfo struct {
+	signatureType  uint64
+	keyLocator     *KeyLocator
+	validityPeriod *ValidityPeriod
+}
+
+// NewSignatureInfo creates a SignatureInfo of the specified signature type.
+func NewSignatureInfo(signatureType uint64) *SignatureInfo {
+	s := new(SignatureInfo)
+	s.signatureType = signatureType
+	return s
+}
+
+// DecodeSignatureInfo decodes a SignatureInfo from the wire.
+func DecodeSignatureInfo(wire *tlv.Block) (*SignatureInfo, error) {
+	return decodeSignatureInfo(wire, tlv.DecodeOptions{})
+}
+
+// decodeSignatureInfo decodes a SignatureInfo from the wire, checking the encoding according to the specified options.
+func decodeSignatureInfo(wire *tlv.Block, opts tlv.DecodeOptions) (*SignatureInfo, error) {
+	if wire == nil {
+		return nil, util.ErrNonExistent
+	}
+	if !wire.Parse() {
+		return nil, tlv.ErrBufferTooShort
+	}
+
+	s := new(SignatureInfo)
+	order := newElementOrder(opts)
+	for _, elem := range wire.Subelements() {
+		switch elem.Type() {
+		case tlv.SignatureType:
+			if err := order.check(1, "SignatureType"); err != nil {
+				return nil, err
+			}
+			signatureType, err := opts.DecodeNNIBlock(elem)
+			if err != nil {
+				return nil, errors.New("Error decoding SignatureType")
+			}
+			s.signatureType = signatureType
+		case tlv.KeyLocator:
+			if err := order.check(2, "KeyLocator"); err != nil {
+				return nil, err
+			}
+			keyLocator, err := decodeKeyLocator(elem, opts)
+			if err != nil {
+				return nil, err
+			}
+			s.keyLocator = keyLocator
+		case tlv.ValidityPeriod:
+			if err := order.check(3, "ValidityPeriod"); err != nil {
+				return nil, err
+			}
+			validityPeriod, err := decodeValidityPeriod(elem, opts)
+			if err != nil {
+				return nil, err
+			}
+			s.validityPeriod = validityPeriod
+		default:
+			if tlv.IsCritical(elem.Type()) {
+				return nil, tlv.ErrUnrecognizedCritical
+			}
+			// If non-critical, ignore
+		}
+	}
+
+	if !order.has(1) {
+		return nil, errors.New("SignatureInfo is missing SignatureType")
+	}
+	return s, nil
+}
+
+func (s *SignatureInfo) String() string {
+	str := "SignatureInfo(SignatureType=" + strconv.FormatUint(s.signatureType, 10)
+	if s.keyLocator != nil {
+		str += ", " + s.keyLocator.String()
+	}
+	if s.validityPeriod != nil {
+		str += ", " + s.validityPeriod.String()
+	}
+	str += ")"
+	return str
+}
+
+// DeepCopy returns a deep copy of the SignatureInfo.
+func (s *SignatureInfo) DeepCopy() *SignatureInfo {
+	copyS := new(SignatureInfo)
+	copyS.signatureType = s.signatureType
+	if s.keyLocator != nil {
+		copyS.keyLocator = s.keyLocator.DeepCopy()
+	}
+	if s.validityPeriod != nil {
+		copyS.validityPeriod = s.validityPeriod.DeepCopy()
+	}
+	return copyS
+}
+
+// SignatureType returns the signature type.
+func (s *SignatureInfo) SignatureType() uint64 {
+	return s.signatureType
+}
+
+// SetSignatureType sets the signature type.
+func (s *SignatureInfo) SetSignatureType(signatureType uint64) {
+	s.signatureType = signatureType
+}
+
+// KeyLocator returns a copy of the KeyLocator or nil if no KeyLocator is set.
+func (s *SignatureInfo) KeyLocator() *KeyLocator {
+	if s.keyLocator == nil {
+		return nil
+	}
+	return s.keyLocator.DeepCopy()
+}
+
+// SetKeyLocator sets the KeyLocator (or unsets it if nil is specified).
+func (s *SignatureInfo) SetKeyLocator(keyLocator *KeyLocator) {
+	if keyLocator == nil {
+		s.keyLocator = nil
+	} else {
+		s.keyLocator = keyLocator.DeepCopy()
+	}
+}
+
+// ValidityPeriod returns a copy of the ValidityPeriod or nil if no ValidityPeriod is set.
+func (s *SignatureInfo) ValidityPeriod() *ValidityPeriod {
+	if s.validityPeriod == nil {
+		return nil
+	}
+	return s.validityPeriod.DeepCopy()
+}
+
+// SetValidityPeriod sets the ValidityPeriod (or unsets it if nil is specified).
+func (s *SignatureInfo) SetValidityPeriod(validityPeriod *ValidityPeriod) {
+	if validityPeriod == nil {
+		s.validityPeriod = nil
+	} else {
+		s.validityPeriod = validityPeriod.DeepCopy()
+	}
+}
+
+// Encode encodes the SignatureInfo into a block.
+func (s *SignatureInfo) Encode() *tlv.Block {
+	wire := tlv.NewEmptyBlock(tlv.SignatureInfo)
+	wire.Append(tlv.EncodeNNIBlock(tlv.SignatureType, s.signatureType))
+	if s.keyLocator != nil {
+		wire.Append(s.keyLocator.Encode())
+	}
+	if s.validityPeriod != nil {
+		wire.Append(s.validityPeriod.Encode())
+	}
+	wire.Wire()
+	return wire
+}
@@ -0,0 +1,219 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+
+	"github.com/eric135/go-ndn2/util"
+)
+
+// Signer computes signatures over the signed portion of packets.
+type Signer interface {
+	// Type returns the signature type produced by the signer.
+	Type() uint64
+	// KeyLocator returns the KeyLocator to place in the SignatureInfo, or nil if none should be included.
+	KeyLocator() *KeyLocator
+	// ComputeSignatureValue returns the signature value for the specified signed portion.
+	ComputeSignatureValue(signedPortion []byte) ([]byte, error)
+}
+
+// digestSigner is implemented by signers whose signature is computed over the SHA-256 digest of the signed portion, allowing packets to hash the signed portion incrementally rather than buffering it.
+type digestSigner interface {
+	signDigest(digest []byte) ([]byte, error)
+}
+
+// Sha256Signer produces DigestSha256 signatures, which provide integrity but not authenticity.
+type Sha256Signer struct{}
+
+// NewSha256Signer creates a Sha256Signer.
+func NewSha256Signer() *Sha256Signer {
+	return new(Sha256Signer)
+}
+
+// Type returns the signature type produced by the signer.
+func (s *Sha256Signer) Type() uint64 {
+	return SignatureDigestSha256
+}
+
+// KeyLocator returns nil, since DigestSha256 signatures do not have a KeyLocator.
+func (s *Sha256Signer) KeyLocator() *KeyLocator {
+	return nil
+}
+
+// ComputeSignatureValue returns the SHA-256 digest of the specified signed portion.
+func (s *Sha256Signer) ComputeSignatureValue(signedPortion []byte) ([]byte, error) {
+	digest := sha256.Sum256(signedPortion)
+	return s.signDigest(digest[:])
+}
+
+// signDigest returns the SHA-256 digest of the signed portion as the signature.
+func (s *Sha256Signer) signDigest(digest []byte) ([]byte, error) {
+	signatureValue := make([]byte, len(digest))
+	copy(signatureValue, digest)
+	return signatureValue, nil
+}
+
+// EcdsaSigner produces SignatureSha256WithEcdsa signatures using the specified private key, which may be on the P-256, P-384, or P-521 curve. The signed portion is hashed with SHA-256 regardless of the curve.
+type EcdsaSigner struct {
+	keyName *Name
+	key     *ecdsa.PrivateKey
+}
+
+// NewEcdsaSigner creates an EcdsaSigner that signs with the specified key and places the specified key name in the KeyLocator.
+func NewEcdsaSigner(keyName *Name, key *ecdsa.PrivateKey) *EcdsaSigner {
+	s := new(EcdsaSigner)
+	s.keyName = keyName.DeepCopy()
+	s.key = key
+	return s
+}
+
+// Type returns the signature type produced by the signer.
+func (s *EcdsaSigner) Type() uint64 {
+	return SignatureSha256WithEcdsa
+}
+
+// KeyLocator returns a KeyLocator containing the name of the signing key.
+func (s *EcdsaSigner) KeyLocator() *KeyLocator {
+	return NewKeyLocatorName(s.keyName)
+}
+
+// ComputeSignatureValue returns the DER-encoded ECDSA signature of the SHA-256 digest of the specified signed portion.
+func (s *EcdsaSigner) ComputeSignatureValue(signedPortion []byte) ([]byte, error) {
+	digest := sha256.Sum256(signedPortion)
+	return s.signDigest(digest[:])
+}
+
+// signDigest returns the DER-encoded ECDSA signature of the SHA-256 digest of the signed portion.
+func (s *EcdsaSigner) signDigest(digest []byte) ([]byte, error) {
+	if s.key == nil {
+		return nil, util.ErrNonExistent
+	}
+	if !isSupportedEcdsaCurve(s.key.Curve) {
+		return nil, errors.New("Unsupported ECDSA curve")
+	}
+	return ecdsa.SignASN1(rand.Reader, s.key, digest)
+}
+
+// isSupportedEcdsaCurve returns whether the curve is one of the NIST curves permitted for SignatureSha256WithEcdsa.
+func isSupportedEcdsaCurve(curve elliptic.Curve) bool {
+	return curve == elliptic.P256() || curve == elliptic.P384() || curve == elliptic.P521()
+}
+
+// Ed25519Signer produces SignatureEd25519 signatures using the specified private key.
+type Ed25519Signer struct {
+	keyName *Name
+	key     ed25519.PrivateKey
+}
+
+// NewEd25519Signer creates an Ed25519Signer that signs with the specified key and places the specified key name in the KeyLocator.
+func NewEd25519Signer(keyName *Name, key ed25519.PrivateKey) *Ed25519Signer {
+	s := new(Ed25519Signer)
+	s.keyName = keyName.DeepCopy()
+	s.key = key
+	return s
+}
+
+// Type returns the signature type produced by the signer.
+func (s *Ed25519Signer) Type() uint64 {
+	return SignatureEd25519
+}
+
+// KeyLocator returns a KeyLocator containing the name of the signing key.
+func (s *Ed25519Signer) KeyLocator() *KeyLocator {
+	return NewKeyLocatorName(s.keyName)
+}
+
+// ComputeSignatureValue returns the 64-octet Ed25519 signature of the specified signed portion. Unlike ECDSA signatures, the signature is not DER-encoded and the signed portion is not hashed beforehand.
+func (s *Ed25519Signer) ComputeSignatureValue(signedPortion []byte) ([]byte, error) {
+	if len(s.key) != ed25519.PrivateKeySize {
+		return nil, util.ErrNonExistent
+	}
+	return ed25519.Sign(s.key, signedPortion), nil
+}
+
+// RsaSigner produces SignatureSha256WithRsa signatures using the specified private key, as PKCS #1 v1.5 signatures of the SHA-256 digest of the signed portion.
+type RsaSigner struct {
+	keyName *Name
+	key     *rsa.PrivateKey
+}
+
+// NewRsaSigner creates an RsaSigner that signs with the specified key and places the specified key name in the KeyLocator.
+func NewRsaSigner(keyName *Name, key *rsa.PrivateKey) *RsaSigner {
+	s := new(RsaSigner)
+	s.keyName = keyName.DeepCopy()
+	s.key = key
+	return s
+}
+
+// Type returns the signature type produced by the signer.
+func (s *RsaSigner) Type() uint64 {
+	return SignatureSha256WithRsa
+}
+
+// KeyLocator returns a KeyLocator containing the name of the signing key.
+func (s *RsaSigner) KeyLocator() *KeyLocator {
+	return NewKeyLocatorName(s.keyName)
+}
+
+// ComputeSignatureValue returns the PKCS #1 v1.5 RSA signature of the SHA-256 digest of the specified signed portion.
+func (s *RsaSigner) ComputeSignatureValue(signedPortion []byte) ([]byte, error) {
+	digest := sha256.Sum256(signedPortion)
+	return s.signDigest(digest[:])
+}
+
+// signDigest returns the PKCS #1 v1.5 RSA signature of the SHA-256 digest of the signed portion.
+func (s *RsaSigner) signDigest(digest []byte) ([]byte, error) {
+	if s.key == nil {
+		return nil, util.ErrNonExistent
+	}
+	return rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest)
+}
+
+// HmacSigner produces SignatureHmacWithSha256 signatures using the specified shared secret key.
+type HmacSigner struct {
+	keyName *Name
+	key     []byte
+}
+
+// NewHmacSigner creates an HmacSigner that signs with a copy of the specified key and places the specified key name in the KeyLocator.
+func NewHmacSigner(keyName *Name, key []byte) *HmacSigner {
+	s := new(HmacSigner)
+	s.keyName = keyName.DeepCopy()
+	s.key = make([]byte, len(key))
+	copy(s.key, key)
+	return s
+}
+
+// Type returns the signature type produced by the signer.
+func (s *HmacSigner) Type() uint64 {
+	return SignatureHmacWithSha256
+}
+
+// KeyLocator returns a KeyLocator containing the name of the signing key.
+func (s *HmacSigner) KeyLocator() *KeyLocator {
+	return NewKeyLocatorName(s.keyName)
+}
+
+// ComputeSignatureValue returns the HMAC-SHA256 of the specified signed portion. Unlike the other signature types, this cannot be computed from the SHA-256 digest of the signed portion.
+func (s *HmacSigner) ComputeSignatureValue(signedPortion []byte) ([]byte, error) {
+	if len(s.key) == 0 {
+		return nil, util.ErrNonExistent
+	}
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(signedPortion)
+	return mac.Sum(nil), nil
+}
@@ -0,0 +1,146 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"testing"
+
+	ndn "github.com/eric135/go-ndn2"
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/stretchr/testify/assert"
+)
+
+// signedPortion returns the signed portion and signature value of an encoded Data packet.
+func signedPortion(t *testing.T, d *ndn.Data) ([]byte, []byte) {
+	encoded, err := d.Encode()
+	assert.NoError(t, err)
+	wire, err := encoded.Wire()
+	assert.NoError(t, err)
+	block, _, err := tlv.DecodeBlock(wire)
+	assert.NoError(t, err)
+	value := block.Value()
+	signatureValueSize := tlv.SizeOfVarNumber(tlv.SignatureValue) + tlv.SizeOfVarNumber(uint64(len(d.SignatureValue()))) + len(d.SignatureValue())
+	return value[:len(value)-signatureValueSize], d.SignatureValue()
+}
+
+func TestSha256Signer(t *testing.T) {
+	name, _ := ndn.NameFromString("/go/ndn")
+	d := ndn.NewData(name, []byte{0x01, 0x02, 0x03})
+	assert.NoError(t, d.Sign(ndn.NewSha256Signer()))
+	assert.Equal(t, uint64(ndn.SignatureDigestSha256), d.SignatureInfo().SignatureType())
+	assert.Nil(t, d.SignatureInfo().KeyLocator())
+
+	input, signatureValue := signedPortion(t, d)
+	digest := sha256.Sum256(input)
+	assert.Equal(t, digest[:], signatureValue)
+}
+
+func TestEcdsaSigner(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	keyName, _ := ndn.NameFromString("/go/KEY/abcd")
+	name, _ := ndn.NameFromString("/go/ndn")
+	d := ndn.NewData(name, []byte{0x01, 0x02, 0x03})
+	d.SetSignatureInfo(ndn.NewSignatureInfo(ndn.SignatureDigestSha256))
+	assert.NoError(t, d.Sign(ndn.NewEcdsaSigner(keyName, key)))
+	assert.Equal(t, uint64(ndn.SignatureSha256WithEcdsa), d.SignatureInfo().SignatureType())
+	assert.Equal(t, "/go/KEY/abcd", d.SignatureInfo().KeyLocator().Name().String())
+
+	input, signatureValue := signedPortion(t, d)
+	digest := sha256.Sum256(input)
+	assert.True(t, ecdsa.VerifyASN1(&key.PublicKey, digest[:], signatureValue))
+	digest[0] ^= 0xff
+	assert.False(t, ecdsa.VerifyASN1(&key.PublicKey, digest[:], signatureValue))
+}
+
+func TestEcdsaSignerCurves(t *testing.T) {
+	keyName, _ := ndn.NameFromString("/go/KEY/abcd")
+	name, _ := ndn.NameFromString("/go/ndn")
+	for _, curve := range []elliptic.Curve{elliptic.P384(), elliptic.P521()} {
+		key, err := ecdsa.GenerateKey(curve, rand.Reader)
+		assert.NoError(t, err)
+		d := ndn.NewData(name, []byte{0x01, 0x02, 0x03})
+		assert.NoError(t, d.Sign(ndn.NewEcdsaSigner(keyName, key)))
+		assert.Equal(t, uint64(ndn.SignatureSha256WithEcdsa), d.SignatureInfo().SignatureType())
+
+		input, signatureValue := signedPortion(t, d)
+		digest := sha256.Sum256(input)
+		assert.True(t, ecdsa.VerifyASN1(&key.PublicKey, digest[:], signatureValue))
+	}
+
+	// Curves other than P-256, P-384, and P-521 are rejected
+	key, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	assert.NoError(t, err)
+	d := ndn.NewData(name, []byte{0x01, 0x02, 0x03})
+	assert.Error(t, d.Sign(ndn.NewEcdsaSigner(keyName, key)))
+}
+
+func TestEd25519Signer(t *testing.T) {
+	publicKey, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	keyName, _ := ndn.NameFromString("/go/KEY/abcd")
+	name, _ := ndn.NameFromString("/go/ndn")
+	d := ndn.NewData(name, []byte{0x01, 0x02, 0x03})
+	assert.NoError(t, d.Sign(ndn.NewEd25519Signer(keyName, key)))
+	assert.Equal(t, uint64(ndn.SignatureEd25519), d.SignatureInfo().SignatureType())
+	assert.Equal(t, "/go/KEY/abcd", d.SignatureInfo().KeyLocator().Name().String())
+
+	input, signatureValue := signedPortion(t, d)
+	assert.Equal(t, ed25519.SignatureSize, len(signatureValue))
+	assert.True(t, ed25519.Verify(publicKey, input, signatureValue))
+	input[0] ^= 0xff
+	assert.False(t, ed25519.Verify(publicKey, input, signatureValue))
+
+	assert.Error(t, d.Sign(ndn.NewEd25519Signer(keyName, nil)))
+}
+
+func TestRsaSigner(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	keyName, _ := ndn.NameFromString("/go/KEY/abcd")
+	name, _ := ndn.NameFromString("/go/ndn")
+	d := ndn.NewData(name, []byte{0x01, 0x02, 0x03})
+	assert.NoError(t, d.Sign(ndn.NewRsaSigner(keyName, key)))
+	assert.Equal(t, uint64(ndn.SignatureSha256WithRsa), d.SignatureInfo().SignatureType())
+	assert.Equal(t, "/go/KEY/abcd", d.SignatureInfo().KeyLocator().Name().String())
+
+	input, signatureValue := signedPortion(t, d)
+	digest := sha256.Sum256(input)
+	assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signatureValue))
+	digest[0] ^= 0xff
+	assert.Error(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signatureValue))
+
+	assert.Error(t, d.Sign(ndn.NewRsaSigner(keyName, nil)))
+}
+
+func TestHmacSigner(t *testing.T) {
+	key := []byte("secret")
+	keyName, _ := ndn.NameFromString("/go/KEY/abcd")
+	name, _ := ndn.NameFromString("/go/ndn")
+	d := ndn.NewData(name, []byte{0x01, 0x02, 0x03})
+	signer := ndn.NewHmacSigner(keyName, key)
+	key[0] = 'S'
+	assert.NoError(t, d.Sign(signer))
+	assert.Equal(t, uint64(ndn.SignatureHmacWithSha256), d.SignatureInfo().SignatureType())
+	assert.Equal(t, "/go/KEY/abcd", d.SignatureInfo().KeyLocator().Name().String())
+
+	input, signatureValue := signedPortion(t, d)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(input)
+	assert.Equal(t, mac.Sum(nil), signatureValue)
+
+	assert.Error(t, d.Sign(ndn.NewHmacSigner(keyName, nil)))
+}
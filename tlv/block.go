@@ -9,11 +9,18 @@ package tlv
 
 import (
 	"bytes"
+	"io"
 	"math"
 
 	"github.com/eric135/go-ndn2/util"
 )
 
+// MaxLength is the largest TLV-LENGTH that blocks are encoded with, which is the largest value that fits in the 5-octet VAR-NUMBER form. Longer values cannot be encoded and cause util.ErrTooLong to be returned.
+const MaxLength = math.MaxUint32
+
+// maxEncodedSize is the largest size of an encoded block or buffer of blocks that can be indexed by an int on the current platform.
+const maxEncodedSize = uint64(^uint(0) >> 1)
+
 // Block contains an encoded block.
 type Block struct {
 	// Contents
@@ -55,9 +62,11 @@ func (b *Block) Type() uint32 {
 	return b.tlvType
 }
 
-// Value returns the value contained in the block.
+// Value returns a copy of the value contained in the block, so that modifying it cannot desynchronize the cached wire.
 func (b *Block) Value() []byte {
-	return b.value
+	value := make([]byte, len(b.value))
+	copy(value, b.value)
+	return value
 }
 
 // Subelements returns the sub-elements of the block.
@@ -210,6 +219,11 @@ func (b *Block) Insert(in *Block) {
 
 // Parse parses the block value into subelements, if possible.
 func (b *Block) Parse() bool {
+	if len(b.subelements) > 0 && len(b.value) == 0 {
+		// Already parsed or constructed from subelements
+		return true
+	}
+
 	startPos := uint64(0)
 	b.subelements = []*Block{}
 	for startPos < uint64(len(b.value)) {
@@ -228,44 +242,34 @@ func (b *Block) Parse() bool {
 // Encoding/Decoding
 ////////////////////
 
-// Wire returns the wire-encoded block.
+// Wire returns the wire-encoded block. The returned slice is the block's cached encoding and must not be modified.
 func (b *Block) Wire() ([]byte, error) {
 	if b.hasWire {
 		return b.wire, nil
 	}
+	valueSize, err := b.checkedValueSize()
+	if err != nil {
+		return nil, err
+	}
 	b.wire = []byte{}
 
 	// Encode type, length, and value into wire
 	encodedType := EncodeVarNum(uint64(b.tlvType))
+	encodedLength := EncodeVarNum(uint64(valueSize))
 	var buf bytes.Buffer
+	buf.Grow(len(encodedType) + len(encodedLength) + valueSize)
+	buf.Write(encodedType)
+	buf.Write(encodedLength)
 	if len(b.subelements) > 0 {
 		// Wire encode subelements
-		var elemSize uint64
 		for _, elem := range b.subelements {
 			elemWire, err := elem.Wire()
 			if err != nil {
 				return b.wire, err
 			}
-			elemSize += uint64(len(elemWire))
-		}
-		encodedLength := EncodeVarNum(elemSize)
-
-		buf.Grow(len(encodedType) + len(encodedLength) + int(elemSize))
-		buf.Write(encodedType)
-		buf.Write(encodedLength)
-		for _, elem := range b.subelements {
-			elemWire, err := elem.Wire()
-			if err != nil {
-				b.wire = []byte{}
-				return b.wire, nil
-			}
 			buf.Write(elemWire)
 		}
 	} else {
-		encodedLength := EncodeVarNum(uint64(len(b.value)))
-		buf.Grow(len(encodedType) + len(encodedLength) + len(b.value))
-		buf.Write(encodedType)
-		buf.Write(encodedLength)
 		buf.Write(b.value)
 	}
 
@@ -274,58 +278,168 @@ func (b *Block) Wire() ([]byte, error) {
 	return b.wire, nil
 }
 
+// WriteTo writes the wire encoding of the block to the specified writer, without building an intermediate buffer if the block has no wire.
+func (b *Block) WriteTo(w io.Writer) (int64, error) {
+	if b.hasWire {
+		n, err := w.Write(b.wire)
+		return int64(n), err
+	}
+	valueSize, err := b.checkedValueSize()
+	if err != nil {
+		return 0, err
+	}
+
+	var written int64
+	n, err := w.Write(EncodeVarNum(uint64(b.tlvType)))
+	written += int64(n)
+	if err != nil {
+		return written, err
+	}
+	n, err = w.Write(EncodeVarNum(uint64(valueSize)))
+	written += int64(n)
+	if err != nil {
+		return written, err
+	}
+
+	if len(b.subelements) > 0 {
+		for _, elem := range b.subelements {
+			elemWritten, err := elem.WriteTo(w)
+			written += elemWritten
+			if err != nil {
+				return written, err
+			}
+		}
+	} else {
+		n, err = w.Write(b.value)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// EncodeAll encodes the specified blocks back-to-back into a single contiguous buffer, such that several packets can be sent to a stream with one write. No framing is added between the blocks. Unlike Wire, the encodings are not cached in the blocks.
+func EncodeAll(blocks []*Block) ([]byte, error) {
+	var size uint64
+	for _, b := range blocks {
+		if b == nil {
+			return nil, util.ErrNonExistent
+		}
+		blockSize, err := b.checkedSize()
+		if err != nil {
+			return nil, err
+		}
+		size += uint64(blockSize)
+		if size > maxEncodedSize {
+			return nil, util.ErrTooLong
+		}
+	}
+
+	buf := make([]byte, 0, size)
+	for _, b := range blocks {
+		buf = b.appendTo(buf)
+	}
+	return buf, nil
+}
+
+// appendTo appends the wire encoding of the block to the buffer, using the cached wire of the block and its subelements where available.
+func (b *Block) appendTo(buf []byte) []byte {
+	if b.hasWire {
+		return append(buf, b.wire...)
+	}
+
+	buf = appendVarNum(buf, uint64(b.tlvType))
+	buf = appendVarNum(buf, uint64(b.valueSize()))
+	if len(b.subelements) > 0 {
+		for _, elem := range b.subelements {
+			buf = elem.appendTo(buf)
+		}
+	} else {
+		buf = append(buf, b.value...)
+	}
+	return buf
+}
+
 // HasWire returns whether the block has a valid wire encoding.
 func (b *Block) HasWire() bool {
 	return b.hasWire
 }
 
-// Size returns the size of the wire.
+// Size returns the size of the wire encoding of the block, computing it from the type, value, and subelements if the block has no wire.
 func (b *Block) Size() int {
-	return len(b.wire)
+	if b.hasWire {
+		return len(b.wire)
+	}
+	valueSize := b.valueSize()
+	return SizeOfVarNumber(uint64(b.tlvType)) + SizeOfVarNumber(uint64(valueSize)) + valueSize
 }
 
-// Reset clears the encoded wire buffer, value, and subelements of the block.
-func (b *Block) Reset() {
-	b.hasWire = false
-	b.wire = []byte{}
-	b.value = []byte{}
-	b.subelements = []*Block{}
+// valueSize returns the size of the encoded value of the block, including any subelements.
+func (b *Block) valueSize() int {
+	if len(b.subelements) == 0 {
+		return len(b.value)
+	}
+	size := 0
+	for _, elem := range b.subelements {
+		size += elem.Size()
+	}
+	return size
 }
 
-// DecodeBlock decodes a block from the wire.
-func DecodeBlock(wire []byte) (*Block, uint64, error) {
-	b := new(Block)
-
-	// Decode TLV type
-	tlvType, tlvTypeLen, err := DecodeVarNum(wire)
+// checkedSize returns the size of the wire encoding of the block like Size, or util.ErrTooLong if the value of the block or any of its subelements is too long to encode.
+func (b *Block) checkedSize() (int, error) {
+	if b.hasWire {
+		return len(b.wire), nil
+	}
+	valueSize, err := b.checkedValueSize()
 	if err != nil {
-		return nil, 0, err
+		return 0, err
 	}
-	if tlvType > math.MaxUint32 {
-		return nil, 0, util.ErrOutOfRange
+	size := uint64(SizeOfVarNumber(uint64(b.tlvType))) + uint64(SizeOfVarNumber(uint64(valueSize))) + uint64(valueSize)
+	if size > maxEncodedSize {
+		return 0, util.ErrTooLong
 	}
-	b.tlvType = uint32(tlvType)
+	return int(size), nil
+}
 
-	// Decode TLV length (we don't store this because it's implicit from value slice length)
-	if tlvTypeLen == len(wire) {
-		return nil, 0, ErrMissingLength
+// checkedValueSize returns the size of the encoded value of the block like valueSize, or util.ErrTooLong if it exceeds MaxLength, such as when the sizes of nested subelements add up to more than can be encoded in the 5-octet VAR-NUMBER form.
+func (b *Block) checkedValueSize() (int, error) {
+	if len(b.subelements) == 0 {
+		if uint64(len(b.value)) > MaxLength {
+			return 0, util.ErrTooLong
+		}
+		return len(b.value), nil
 	}
-	tlvLength, tlvLengthLen, err := DecodeVarNum(wire[tlvTypeLen:])
-	if err != nil {
-		return nil, 0, err
+	var size uint64
+	for _, elem := range b.subelements {
+		elemSize, err := elem.checkedSize()
+		if err != nil {
+			return 0, err
+		}
+		size += uint64(elemSize)
+		if size > MaxLength || size > maxEncodedSize {
+			return 0, util.ErrTooLong
+		}
 	}
+	return int(size), nil
+}
 
-	// Decode TLV value
-	if uint64(len(wire)) < uint64(tlvTypeLen)+uint64(tlvLengthLen)+tlvLength {
-		return nil, 0, ErrBufferTooShort
-	}
-	b.value = make([]byte, tlvLength)
-	copy(b.value, wire[tlvTypeLen+tlvLengthLen:uint64(tlvTypeLen)+uint64(tlvLengthLen)+tlvLength])
+// ClearWire invalidates the encoded wire buffer of the block, preserving its type, value, and subelements.
+func (b *Block) ClearWire() {
+	b.hasWire = false
+	b.wire = []byte{}
+}
 
-	// Add wire
-	b.wire = make([]byte, uint64(tlvTypeLen)+uint64(tlvLengthLen)+tlvLength)
-	copy(b.wire, wire)
-	b.hasWire = true
+// Reset clears the encoded wire buffer, value, and subelements of the block. The TLV type is preserved.
+func (b *Block) Reset() {
+	b.hasWire = false
+	b.wire = []byte{}
+	b.value = []byte{}
+	b.subelements = []*Block{}
+}
 
-	return b, uint64(tlvTypeLen) + uint64(tlvLengthLen) + tlvLength, nil
+// DecodeBlock decodes a single block from the start of the wire and returns the number of bytes consumed, allowing concatenated blocks to be decoded in sequence. Non-minimal encodings are tolerated.
+func DecodeBlock(wire []byte) (*Block, uint64, error) {
+	return DecodeOptions{}.DecodeBlock(wire)
 }
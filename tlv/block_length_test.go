@@ -0,0 +1,54 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package tlv
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/eric135/go-ndn2/util"
+	"github.com/stretchr/testify/assert"
+)
+
+// oversizedBlock returns a block whose subelements add up to more than MaxLength, sharing a single value so that the test does not need to allocate it all.
+func oversizedBlock() *Block {
+	elem := NewBlock(0x20, make([]byte, 1<<20))
+	block := NewEmptyBlock(0x21)
+	for i := 0; i < 4096; i++ {
+		block.subelements = append(block.subelements, elem)
+	}
+	return block
+}
+
+func TestBlockEncodeTooLong(t *testing.T) {
+	block := oversizedBlock()
+	_, err := block.Wire()
+	assert.Equal(t, util.ErrTooLong, err)
+	assert.False(t, block.HasWire())
+
+	var buf bytes.Buffer
+	written, err := block.WriteTo(&buf)
+	assert.Equal(t, util.ErrTooLong, err)
+	assert.Equal(t, int64(0), written)
+	assert.Equal(t, 0, buf.Len())
+
+	_, err = EncodeAll([]*Block{NewBlock(0x22, []byte{0x01}), block})
+	assert.Equal(t, util.ErrTooLong, err)
+
+	// Nesting the oversized block propagates the error
+	outer := NewEmptyBlock(0x23)
+	outer.subelements = append(outer.subelements, block)
+	_, err = outer.Wire()
+	assert.Equal(t, util.ErrTooLong, err)
+
+	// One fewer subelement fits
+	block.subelements = block.subelements[1:]
+	size, err := block.checkedSize()
+	assert.NoError(t, err)
+	assert.Equal(t, 1+5+4095*(1+5+1<<20), size)
+}
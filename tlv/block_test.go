@@ -8,6 +8,8 @@
 package tlv_test
 
 import (
+	"bytes"
+	"io/ioutil"
 	"testing"
 
 	"github.com/eric135/go-ndn2/tlv"
@@ -62,6 +64,51 @@ func TestBlockDecode(t *testing.T) {
 	assert.ElementsMatch(t, []byte{0x28, 0x04, 0x01, 0x02, 0x03, 0x04}, encoded)
 }
 
+func TestBlockDecodeConcatenated(t *testing.T) {
+	wire := []byte{0x28, 0x02, 0x01, 0x02, 0x29, 0x00, 0x2A, 0x01, 0x03}
+	types := []uint32{}
+	for pos := uint64(0); pos < uint64(len(wire)); {
+		block, blockSize, err := tlv.DecodeBlock(wire[pos:])
+		assert.NoError(t, err)
+		types = append(types, block.Type())
+		pos += blockSize
+	}
+	assert.Equal(t, []uint32{0x28, 0x29, 0x2A}, types)
+}
+
+func TestBlockDecodeVarNumForms(t *testing.T) {
+	for _, tlvType := range []uint32{1, 252, 253, 65535, 65536, 0xFFFFFFFF} {
+		for _, length := range []int{0, 252, 253, 65535, 65536} {
+			wire := append(tlv.EncodeVarNum(uint64(tlvType)), tlv.EncodeVarNum(uint64(length))...)
+			wire = append(wire, make([]byte, length)...)
+			block, blockSize, err := tlv.DecodeBlock(wire)
+			assert.NoError(t, err)
+			assert.Equal(t, tlvType, block.Type())
+			assert.Equal(t, length, len(block.Value()))
+			assert.Equal(t, uint64(len(wire)), blockSize)
+		}
+	}
+
+	// TLV-TYPE must fit in 32 bits
+	_, _, err := tlv.DecodeBlock([]byte{0xFF, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00})
+	assert.Error(t, err)
+}
+
+func TestBlockDecodeTooShort(t *testing.T) {
+	block, blockSize, err := tlv.DecodeBlock([]byte{0x28, 0x05, 0x01, 0x02, 0x03, 0x04})
+	assert.Nil(t, block)
+	assert.Equal(t, uint64(0), blockSize)
+	assert.Equal(t, tlv.ErrBufferTooShort, err)
+
+	block, _, err = tlv.DecodeBlock([]byte{0x28, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x01})
+	assert.Nil(t, block)
+	assert.Equal(t, tlv.ErrBufferTooShort, err)
+
+	block, _, err = tlv.DecodeBlock([]byte{0x28})
+	assert.Nil(t, block)
+	assert.Equal(t, tlv.ErrMissingLength, err)
+}
+
 func TestBlockSetters(t *testing.T) {
 	block := tlv.NewBlock(0x30, []byte{0x01, 0x02, 0x03, 0x04, 0x05})
 	assert.NotNil(t, block)
@@ -283,3 +330,186 @@ func TestBlockDeepCopy(t *testing.T) {
 	assert.NotSame(t, &(block.Subelements()[1]), &(copyBlock.Subelements()[1]))
 	assert.NotSame(t, encodedBlock, encodedCopyBlock)
 }
+
+func TestBlockDeepCopyNested(t *testing.T) {
+	inner := tlv.NewEmptyBlock(0xAA)
+	inner.Append(tlv.NewBlock(0xA1, []byte{0x01}))
+	block := tlv.NewEmptyBlock(0xCC)
+	block.Append(inner)
+	block.Append(tlv.NewBlock(0xBB, []byte{0x02}))
+	encodedBlock, err := block.Wire()
+	assert.NoError(t, err)
+	expected := append([]byte{}, encodedBlock...)
+
+	copyBlock := block.DeepCopy()
+	assert.NotSame(t, block.Subelements()[0], copyBlock.Subelements()[0])
+	assert.NotSame(t, block.Subelements()[0].Subelements()[0], copyBlock.Subelements()[0].Subelements()[0])
+
+	// Mutating subelements of the copy does not affect the original
+	copyBlock.Subelements()[0].Subelements()[0].SetValue([]byte{0xFF})
+	copyBlock.Subelements()[1].SetType(0xBD)
+	copyBlock.Subelements()[0].Append(tlv.NewBlock(0xA2, []byte{0x03}))
+	copyBlock.ClearWire()
+	copyBlock.Subelements()[0].ClearWire()
+	encodedCopyBlock, err := copyBlock.Wire()
+	assert.NoError(t, err)
+	assert.NotEqual(t, expected, encodedCopyBlock)
+
+	assert.Equal(t, []byte{0x01}, block.Subelements()[0].Subelements()[0].Value())
+	assert.Equal(t, uint32(0xBB), block.Subelements()[1].Type())
+	assert.Equal(t, 1, len(block.Subelements()[0].Subelements()))
+	block.ClearWire()
+	block.Subelements()[0].ClearWire()
+	encodedBlock, err = block.Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, encodedBlock)
+}
+
+func TestBlockValueCopy(t *testing.T) {
+	block := tlv.NewBlock(0x01, []byte{0x02, 0x03})
+	encoded, err := block.Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x01, 0x02, 0x02, 0x03}, encoded)
+
+	value := block.Value()
+	value[0] = 0xFF
+	assert.Equal(t, []byte{0x02, 0x03}, block.Value())
+	block.ClearWire()
+	encoded, err = block.Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x01, 0x02, 0x02, 0x03}, encoded)
+}
+
+func TestBlockClearWire(t *testing.T) {
+	block := tlv.NewEmptyBlock(0x77)
+	block.Append(tlv.NewBlock(0xA0, []byte{0x20}))
+	encoded, err := block.Wire()
+	assert.NoError(t, err)
+	assert.True(t, block.HasWire())
+
+	block.ClearWire()
+	assert.False(t, block.HasWire())
+	assert.Equal(t, uint32(0x77), block.Type())
+	assert.Equal(t, 1, len(block.Subelements()))
+	reencoded, err := block.Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, encoded, reencoded)
+
+	block.Reset()
+	assert.False(t, block.HasWire())
+	assert.Equal(t, uint32(0x77), block.Type())
+	assert.Equal(t, 0, len(block.Subelements()))
+	reencoded, err = block.Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x77, 0x00}, reencoded)
+}
+
+func TestBlockSize(t *testing.T) {
+	block := tlv.NewEmptyBlock(0x77)
+	block.Append(tlv.NewBlock(0xA0, []byte{0x20}))
+	blockDD := tlv.NewEmptyBlock(0xFD)
+	blockDD.Append(tlv.NewBlock(0xEE, make([]byte, 300)))
+	block.Append(blockDD)
+	assert.False(t, block.HasWire())
+	assert.Equal(t, 1+3+3+3+3+1+3+300, block.Size())
+	assert.False(t, block.HasWire())
+
+	encoded, err := block.Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, len(encoded), block.Size())
+}
+
+func TestBlockWriteTo(t *testing.T) {
+	block := tlv.NewEmptyBlock(0xAA)
+	block.Append(tlv.NewBlock(0xBB, []byte{0x01}))
+	blockDD := tlv.NewEmptyBlock(0xDD)
+	blockDD.Append(tlv.NewBlock(0xEE, make([]byte, 300)))
+	block.Append(blockDD)
+
+	var buf bytes.Buffer
+	written, err := block.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.False(t, block.HasWire())
+	assert.Equal(t, int64(block.Size()), written)
+
+	encoded, err := block.Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, encoded, buf.Bytes())
+
+	buf.Reset()
+	written, err = block.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(encoded)), written)
+	assert.Equal(t, encoded, buf.Bytes())
+}
+
+func TestEncodeAll(t *testing.T) {
+	nested := tlv.NewEmptyBlock(0xAA)
+	nested.Append(tlv.NewBlock(0xBB, make([]byte, 300)))
+	cached := tlv.NewBlock(0x29, []byte{0x01})
+	cached.Wire()
+	blocks := []*tlv.Block{tlv.NewBlock(0x28, []byte{0x01, 0x02}), nested, tlv.NewEmptyBlock(0x2A), cached}
+
+	wire, err := tlv.EncodeAll(blocks)
+	assert.NoError(t, err)
+	var expected []byte
+	for _, block := range blocks {
+		encoded, err := block.DeepCopy().Wire()
+		assert.NoError(t, err)
+		expected = append(expected, encoded...)
+	}
+	assert.Equal(t, expected, wire)
+	assert.False(t, nested.HasWire())
+
+	// The blocks can be split again by the decoder
+	for i, pos := 0, uint64(0); pos < uint64(len(wire)); i++ {
+		block, blockSize, err := tlv.DecodeBlock(wire[pos:])
+		assert.NoError(t, err)
+		assert.Equal(t, blocks[i].Type(), block.Type())
+		assert.Equal(t, uint64(blocks[i].Size()), blockSize)
+		pos += blockSize
+	}
+
+	wire, err = tlv.EncodeAll(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, wire)
+	_, err = tlv.EncodeAll([]*tlv.Block{blocks[0], nil})
+	assert.Error(t, err)
+}
+
+func benchmarkPackets() []*tlv.Block {
+	blocks := make([]*tlv.Block, 16)
+	for i := range blocks {
+		blocks[i] = pooledTestBlock()
+	}
+	return blocks
+}
+
+func BenchmarkWritePerPacket(b *testing.B) {
+	blocks := benchmarkPackets()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, block := range blocks {
+			block.ClearWire()
+			wire, err := block.Wire()
+			if err != nil {
+				b.Fatal(err)
+			}
+			ioutil.Discard.Write(wire)
+		}
+	}
+}
+
+func BenchmarkEncodeAll(b *testing.B) {
+	blocks := benchmarkPackets()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		wire, err := tlv.EncodeAll(blocks)
+		if err != nil {
+			b.Fatal(err)
+		}
+		ioutil.Discard.Write(wire)
+	}
+}
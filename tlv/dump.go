@@ -0,0 +1,100 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package tlv
+
+import (
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// DefaultDumpPreviewSize is the maximum number of octets of each leaf value shown by Dump.
+const DefaultDumpPreviewSize = 16
+
+// containerTypes contains the known TLV types whose values consist of nested elements.
+var containerTypes = map[uint32]bool{
+	Interest:              true,
+	Data:                  true,
+	Name:                  true,
+	ForwardingHint:        true,
+	InterestSignatureInfo: true,
+	MetaInfo:              true,
+	FinalBlockID:          true,
+	SignatureInfo:         true,
+	KeyLocator:            true,
+	ValidityPeriod:        true,
+	SafeBag:               true,
+	Delegation:            true,
+}
+
+// Dump returns a human-readable representation of the TLV structure of the block for debugging, showing at most DefaultDumpPreviewSize octets of each leaf value.
+func Dump(b *Block) string {
+	return DumpWithPreviewSize(b, DefaultDumpPreviewSize)
+}
+
+// DumpWithPreviewSize returns a human-readable representation of the TLV structure of the block for debugging, with one line per element indented by its depth. Each line contains the name of the TLV type (see TypeName), its number, and the length of the value, followed by a hex and ASCII preview of at most previewSize octets for leaf values. The values of known container types, such as Name and MetaInfo, and of unknown types are shown as nested structures if they can be completely parsed as a sequence of TLV elements, so the value of an unknown type that happens to be valid TLV will also be shown this way. The block itself is not modified.
+func DumpWithPreviewSize(b *Block, previewSize int) string {
+	if b == nil {
+		return ""
+	}
+	var sb strings.Builder
+	dumpBlock(&sb, b.DeepCopy(), 0, previewSize)
+	return sb.String()
+}
+
+// dumpBlock appends the representation of the block and its subelements at the specified depth to the builder.
+func dumpBlock(sb *strings.Builder, b *Block, depth int, previewSize int) {
+	sb.WriteString(strings.Repeat("  ", depth))
+	sb.WriteString(TypeName(b.tlvType))
+	sb.WriteString(" (" + strconv.FormatUint(uint64(b.tlvType), 10) + ") [" + strconv.Itoa(b.valueSize()) + "]")
+
+	_, known := typeNames[b.tlvType]
+	if len(b.subelements) == 0 && len(b.value) > 0 && (containerTypes[b.tlvType] || !known) {
+		parsed := b.DeepCopy()
+		if parsed.Parse() {
+			b = parsed
+		}
+	}
+	if len(b.subelements) == 0 {
+		if len(b.value) > 0 {
+			sb.WriteString(": " + dumpPreview(b.value, previewSize))
+		}
+		sb.WriteString("\n")
+		return
+	}
+
+	sb.WriteString("\n")
+	for _, elem := range b.subelements {
+		dumpBlock(sb, elem, depth+1, previewSize)
+	}
+}
+
+// dumpPreview returns the hex and ASCII representation of at most previewSize octets of the value, with non-printable characters shown as ".".
+func dumpPreview(value []byte, previewSize int) string {
+	if previewSize < 0 {
+		previewSize = 0
+	}
+	truncated := len(value) > previewSize
+	if truncated {
+		value = value[:previewSize]
+	}
+
+	ascii := make([]byte, len(value))
+	for i, c := range value {
+		if c >= 0x20 && c < 0x7f {
+			ascii[i] = c
+		} else {
+			ascii[i] = '.'
+		}
+	}
+	preview := hex.EncodeToString(value) + " |" + string(ascii) + "|"
+	if truncated {
+		preview += " ..."
+	}
+	return preview
+}
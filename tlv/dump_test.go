@@ -0,0 +1,51 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package tlv_test
+
+import (
+	"testing"
+
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDump(t *testing.T) {
+	wire := []byte{tlv.Data, 0x19,
+		tlv.Name, 0x09, tlv.GenericNameComponent, 0x02, 0x67, 0x6f, tlv.GenericNameComponent, 0x03, 0x6e, 0x64, 0x6e,
+		tlv.Content, 0x0a, 0x68, 0x65, 0x6c, 0x6c, 0x6f, 0x00, 0x77, 0x6f, 0x72, 0x6c,
+		tlv.SignatureValue, 0x00}
+	block, _, err := tlv.DecodeBlock(wire)
+	assert.NoError(t, err)
+
+	expected := "Data (6) [25]\n" +
+		"  Name (7) [9]\n" +
+		"    GenericNameComponent (8) [2]: 676f |go|\n" +
+		"    GenericNameComponent (8) [3]: 6e646e |ndn|\n" +
+		"  Content (21) [10]: 68656c6c6f00 |hello.| ...\n" +
+		"  SignatureValue (23) [0]\n"
+	assert.Equal(t, expected, tlv.DumpWithPreviewSize(block, 6))
+	assert.Contains(t, tlv.Dump(block), "Content (21) [10]: 68656c6c6f00776f726c |hello.worl|\n")
+
+	// The block is not parsed by dumping it
+	assert.Equal(t, 0, len(block.Subelements()))
+	assert.Equal(t, "", tlv.Dump(nil))
+
+	// Unknown types and blocks constructed from subelements
+	outer := tlv.NewEmptyBlock(0xFD01)
+	outer.Append(tlv.NewBlock(tlv.Nonce, []byte{0x01, 0x02, 0x03, 0x04}))
+	assert.Equal(t, "Type(64769) (64769) [6]\n  Nonce (10) [4]: 01020304 |....|\n", tlv.Dump(outer))
+}
+
+func TestDumpUnknownNested(t *testing.T) {
+	block := tlv.NewBlock(0xC8, []byte{0xC9, 0x01, 0xAA, 0xCA, 0x00})
+	assert.Equal(t, "Type(200) (200) [5]\n  Type(201) (201) [1]: aa |.|\n  Type(202) (202) [0]\n", tlv.Dump(block))
+
+	// Unknown values that are not valid TLV are shown as leaves
+	block = tlv.NewBlock(0xC8, []byte{0xC9, 0x05, 0xAA})
+	assert.Equal(t, "Type(200) (200) [3]: c905aa |...|\n", tlv.Dump(block))
+}
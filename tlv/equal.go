@@ -0,0 +1,97 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package tlv
+
+import "bytes"
+
+// orderedContainerTypes contains the container types whose subelements are order-sensitive, such as the components of a Name and the delegations of a ForwardingHint, which are in order of preference.
+var orderedContainerTypes = map[uint32]bool{
+	Name:           true,
+	ForwardingHint: true,
+	FinalBlockID:   true,
+	KeyLocator:     true,
+}
+
+// EqualSemantic returns whether two blocks are equivalent for interoperability testing, ignoring differences in the order of independent elements. Blocks must have the same type. The values of known container types (see Dump) are compared element by element: the subelements of order-sensitive types, such as the components of a Name and the delegations of a ForwardingHint, must appear in the same order, while for other types, such as Interest, Data, MetaInfo, and SignatureInfo, only the relative order of repeated elements of the same type is significant. All other values, including those of unknown types, are compared octet by octet. Non-minimal encodings of TLV-TYPE and TLV-LENGTH are not considered differences. Blocks that cannot be encoded are never equal.
+func EqualSemantic(a *Block, b *Block) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	normalA, ok := normalizeBlock(a)
+	if !ok {
+		return false
+	}
+	normalB, ok := normalizeBlock(b)
+	if !ok {
+		return false
+	}
+	return equalSemantic(normalA, normalB)
+}
+
+// normalizeBlock returns a copy of the block decoded from its wire encoding, so that blocks constructed from subelements and blocks decoded from the wire can be compared alike.
+func normalizeBlock(b *Block) (*Block, bool) {
+	wire, err := b.DeepCopy().Wire()
+	if err != nil {
+		return nil, false
+	}
+	normal, _, err := DecodeBlock(wire)
+	if err != nil {
+		return nil, false
+	}
+	return normal, true
+}
+
+// equalSemantic compares two normalized blocks as described for EqualSemantic.
+func equalSemantic(a *Block, b *Block) bool {
+	if a.tlvType != b.tlvType {
+		return false
+	}
+	if !containerTypes[a.tlvType] || !a.Parse() || !b.Parse() {
+		return bytes.Equal(a.value, b.value)
+	}
+
+	if len(a.subelements) != len(b.subelements) {
+		return false
+	}
+	if orderedContainerTypes[a.tlvType] {
+		for i := range a.subelements {
+			if !equalSemantic(a.subelements[i], b.subelements[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// Repeated elements of each type must match in order, regardless of how they are interleaved with other types
+	byTypeA := groupByType(a.subelements)
+	byTypeB := groupByType(b.subelements)
+	if len(byTypeA) != len(byTypeB) {
+		return false
+	}
+	for tlvType, elemsA := range byTypeA {
+		elemsB := byTypeB[tlvType]
+		if len(elemsA) != len(elemsB) {
+			return false
+		}
+		for i := range elemsA {
+			if !equalSemantic(elemsA[i], elemsB[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// groupByType groups the blocks by TLV type, preserving their relative order within each type.
+func groupByType(blocks []*Block) map[uint32][]*Block {
+	byType := make(map[uint32][]*Block)
+	for _, b := range blocks {
+		byType[b.tlvType] = append(byType[b.tlvType], b)
+	}
+	return byType
+}
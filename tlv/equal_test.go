@@ -0,0 +1,81 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package tlv_test
+
+import (
+	"testing"
+
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustDecodeBlock(t *testing.T, wire []byte) *tlv.Block {
+	block, _, err := tlv.DecodeBlock(wire)
+	assert.NoError(t, err)
+	return block
+}
+
+func TestEqualSemantic(t *testing.T) {
+	name := []byte{tlv.Name, 0x04, tlv.GenericNameComponent, 0x02, 0x67, 0x6f}
+	metaInfoA := []byte{tlv.MetaInfo, 0x06, tlv.ContentType, 0x01, 0x00, tlv.FreshnessPeriod, 0x01, 0x64}
+	metaInfoB := []byte{tlv.MetaInfo, 0x06, tlv.FreshnessPeriod, 0x01, 0x64, tlv.ContentType, 0x01, 0x00}
+	content := []byte{tlv.Content, 0x02, 0x01, 0x02}
+
+	// Reordered independent elements
+	a := mustDecodeBlock(t, append(append([]byte{tlv.Data, 0x12}, name...), append(metaInfoA, content...)...))
+	b := mustDecodeBlock(t, append(append([]byte{tlv.Data, 0x12}, content...), append(metaInfoB, name...)...))
+	assert.True(t, tlv.EqualSemantic(a, b))
+	assert.True(t, tlv.EqualSemantic(b, a))
+	assert.True(t, tlv.EqualSemantic(a, a))
+
+	// Different values
+	c := mustDecodeBlock(t, append(append([]byte{tlv.Data, 0x12}, name...), append(metaInfoA, tlv.Content, 0x02, 0x01, 0x03)...))
+	assert.False(t, tlv.EqualSemantic(a, c))
+
+	// Name components are order-sensitive
+	nameA := mustDecodeBlock(t, []byte{tlv.Name, 0x06, tlv.GenericNameComponent, 0x02, 0x67, 0x6f, tlv.SegmentNameComponent, 0x00})
+	nameB := mustDecodeBlock(t, []byte{tlv.Name, 0x06, tlv.SegmentNameComponent, 0x00, tlv.GenericNameComponent, 0x02, 0x67, 0x6f})
+	assert.False(t, tlv.EqualSemantic(nameA, nameB))
+
+	// Repeated elements are order-sensitive
+	a = mustDecodeBlock(t, []byte{tlv.Interest, 0x0a, tlv.ApplicationParameters, 0x01, 0x01, 0xf0, 0x00, 0xf0, 0x01, 0x02, 0xf1, 0x00})
+	b = mustDecodeBlock(t, []byte{tlv.Interest, 0x0a, tlv.ApplicationParameters, 0x01, 0x01, 0xf0, 0x00, 0xf1, 0x00, 0xf0, 0x01, 0x02})
+	assert.True(t, tlv.EqualSemantic(a, b))
+	b = mustDecodeBlock(t, []byte{tlv.Interest, 0x0a, tlv.ApplicationParameters, 0x01, 0x01, 0xf0, 0x01, 0x02, 0xf0, 0x00, 0xf1, 0x00})
+	assert.False(t, tlv.EqualSemantic(a, b))
+
+	// Missing or extra elements
+	a = mustDecodeBlock(t, []byte{tlv.Interest, 0x08, tlv.CanBePrefix, 0x00, tlv.MustBeFresh, 0x00, tlv.Nonce, 0x02, 0x01, 0x02})
+	b = mustDecodeBlock(t, []byte{tlv.Interest, 0x06, tlv.CanBePrefix, 0x00, tlv.Nonce, 0x02, 0x01, 0x02})
+	assert.False(t, tlv.EqualSemantic(a, b))
+
+	// Different types
+	assert.False(t, tlv.EqualSemantic(tlv.NewBlock(tlv.Content, []byte{0x01}), tlv.NewBlock(tlv.SignatureValue, []byte{0x01})))
+
+	// Nil blocks
+	assert.True(t, tlv.EqualSemantic(nil, nil))
+	assert.False(t, tlv.EqualSemantic(a, nil))
+}
+
+func TestEqualSemanticEncodings(t *testing.T) {
+	// Blocks constructed from subelements equal blocks decoded from the wire
+	constructed := tlv.NewEmptyBlock(tlv.MetaInfo)
+	constructed.Append(tlv.EncodeNNIBlock(tlv.FreshnessPeriod, 100))
+	constructed.Append(tlv.EncodeNNIBlock(tlv.ContentType, 0))
+	decoded := mustDecodeBlock(t, []byte{tlv.MetaInfo, 0x06, tlv.ContentType, 0x01, 0x00, tlv.FreshnessPeriod, 0x01, 0x64})
+	assert.True(t, tlv.EqualSemantic(constructed, decoded))
+
+	// Non-minimal TLV-LENGTH
+	nonMinimal := mustDecodeBlock(t, []byte{tlv.MetaInfo, 0xfd, 0x00, 0x06, tlv.ContentType, 0x01, 0x00, tlv.FreshnessPeriod, 0x01, 0x64})
+	assert.True(t, tlv.EqualSemantic(nonMinimal, decoded))
+
+	// Values of unknown types are compared octet by octet
+	assert.False(t, tlv.EqualSemantic(
+		mustDecodeBlock(t, []byte{0xfd, 0x01, 0x00, 0x04, 0x08, 0x00, 0x09, 0x00}),
+		mustDecodeBlock(t, []byte{0xfd, 0x01, 0x00, 0x04, 0x09, 0x00, 0x08, 0x00})))
+}
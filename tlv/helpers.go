@@ -36,6 +36,30 @@ func EncodeVarNum(in uint64) []byte {
 	}
 }
 
+// appendVarNum appends the encoding of the specified value as a variable-length number to the buffer, without allocating a temporary slice.
+func appendVarNum(buf []byte, in uint64) []byte {
+	if in <= 0xFC {
+		return append(buf, byte(in))
+	} else if in <= 0xFFFF {
+		return append(buf, 0xFD, byte(in>>8), byte(in))
+	} else if in <= 0xFFFFFFFF {
+		return append(buf, 0xFE, byte(in>>24), byte(in>>16), byte(in>>8), byte(in))
+	}
+	return append(buf, 0xFF, byte(in>>56), byte(in>>48), byte(in>>40), byte(in>>32), byte(in>>24), byte(in>>16), byte(in>>8), byte(in))
+}
+
+// SizeOfVarNumber returns the number of octets needed to encode the specified value as a variable-length number.
+func SizeOfVarNumber(in uint64) int {
+	if in <= 0xFC {
+		return 1
+	} else if in <= 0xFFFF {
+		return 3
+	} else if in <= 0xFFFFFFFF {
+		return 5
+	}
+	return 9
+}
+
 // DecodeVarNum decodes a non-negative integer value from a wire value.
 func DecodeVarNum(in []byte) (uint64, int, error) {
 	if len(in) < 1 {
@@ -62,23 +86,76 @@ func DecodeVarNum(in []byte) (uint64, int, error) {
 	}
 }
 
-// EncodeNNIBlock encodes a non-negative integer value in a block of the specified type.
+// DecodeVarNumStrict decodes a non-negative integer value from a wire value like DecodeVarNum, but returns util.ErrOutOfRange if the value is not encoded in the minimal number of octets.
+func DecodeVarNumStrict(in []byte) (uint64, int, error) {
+	value, length, err := DecodeVarNum(in)
+	if err != nil {
+		return 0, 0, err
+	}
+	if length != SizeOfVarNumber(value) {
+		return 0, 0, util.ErrOutOfRange
+	}
+	return value, length, nil
+}
+
+// EncodeNNI encodes a non-negative integer value in the minimal number of octets (1, 2, 4, or 8), in network byte order.
+func EncodeNNI(v uint64) []byte {
+	if v <= 0xFF {
+		return []byte{byte(v)}
+	} else if v <= 0xFFFF {
+		value := make([]byte, 2)
+		binary.BigEndian.PutUint16(value, uint16(v))
+		return value
+	} else if v <= 0xFFFFFFFF {
+		value := make([]byte, 4)
+		binary.BigEndian.PutUint32(value, uint32(v))
+		return value
+	}
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, v)
+	return value
+}
+
+// sizeOfNNI returns the number of octets in the minimal encoding of the specified non-negative integer.
+func sizeOfNNI(v uint64) int {
+	if v <= 0xFF {
+		return 1
+	} else if v <= 0xFFFF {
+		return 2
+	} else if v <= 0xFFFFFFFF {
+		return 4
+	}
+	return 8
+}
+
+// EncodeNNIBlock encodes a non-negative integer value in a block of the specified type, using the minimal-length encoding.
 func EncodeNNIBlock(t uint32, v uint64) *Block {
 	b := new(Block)
 	b.SetType(t)
-	value := make([]byte, 8)
-	binary.BigEndian.PutUint64(value, v)
-	b.SetValue(value)
+	b.SetValue(EncodeNNI(v))
 	return b
 }
 
+// DecodeNNI decodes a non-negative integer value encoded in 1, 2, 4, or 8 octets.
+func DecodeNNI(value []byte) (uint64, error) {
+	switch len(value) {
+	case 1:
+		return uint64(value[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(value)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(value)), nil
+	case 8:
+		return binary.BigEndian.Uint64(value), nil
+	default:
+		return 0, util.ErrOutOfRange
+	}
+}
+
 // DecodeNNIBlock decodes a non-negative integer value from a block.
 func DecodeNNIBlock(wire *Block) (uint64, error) {
 	if wire == nil {
 		return 0, util.ErrNonExistent
 	}
-	if len(wire.Value()) != 8 {
-		return 0, ErrBufferTooShort
-	}
-	return binary.BigEndian.Uint64(wire.Value()), nil
+	return DecodeNNI(wire.Value())
 }
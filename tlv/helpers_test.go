@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/eric135/go-ndn2/tlv"
+	"github.com/eric135/go-ndn2/util"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -72,6 +73,60 @@ func TestVarNumTooShort(t *testing.T) {
 	assert.EqualError(t, err, "Value too short")
 }
 
+func TestVarNumBoundaries(t *testing.T) {
+	for _, test := range []struct {
+		value uint64
+		wire  []byte
+	}{
+		{0, []byte{0x00}},
+		{252, []byte{0xFC}},
+		{253, []byte{0xFD, 0x00, 0xFD}},
+		{65535, []byte{0xFD, 0xFF, 0xFF}},
+		{65536, []byte{0xFE, 0x00, 0x01, 0x00, 0x00}},
+		{0xFFFFFFFF, []byte{0xFE, 0xFF, 0xFF, 0xFF, 0xFF}},
+		{0x100000000, []byte{0xFF, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00}},
+		{0xFFFFFFFFFFFFFFFF, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}},
+	} {
+		assert.Equal(t, test.wire, tlv.EncodeVarNum(test.value), test.value)
+		assert.Equal(t, len(test.wire), tlv.SizeOfVarNumber(test.value), test.value)
+
+		decoded, length, err := tlv.DecodeVarNum(test.wire)
+		assert.NoError(t, err, test.value)
+		assert.Equal(t, test.value, decoded)
+		assert.Equal(t, len(test.wire), length)
+
+		decoded, length, err = tlv.DecodeVarNumStrict(test.wire)
+		assert.NoError(t, err, test.value)
+		assert.Equal(t, test.value, decoded)
+		assert.Equal(t, len(test.wire), length)
+	}
+}
+
+func TestVarNumNonMinimal(t *testing.T) {
+	for _, test := range []struct {
+		value uint64
+		wire  []byte
+	}{
+		{0, []byte{0xFD, 0x00, 0x00}},
+		{252, []byte{0xFD, 0x00, 0xFC}},
+		{65535, []byte{0xFE, 0x00, 0x00, 0xFF, 0xFF}},
+		{252, []byte{0xFE, 0x00, 0x00, 0x00, 0xFC}},
+		{0xFFFFFFFF, []byte{0xFF, 0x00, 0x00, 0x00, 0x00, 0xFF, 0xFF, 0xFF, 0xFF}},
+	} {
+		// Tolerated when lenient
+		decoded, length, err := tlv.DecodeVarNum(test.wire)
+		assert.NoError(t, err)
+		assert.Equal(t, test.value, decoded)
+		assert.Equal(t, len(test.wire), length)
+
+		_, _, err = tlv.DecodeVarNumStrict(test.wire)
+		assert.Equal(t, util.ErrOutOfRange, err, test.wire)
+	}
+
+	_, _, err := tlv.DecodeVarNumStrict([]byte{0xFD, 0x01})
+	assert.Equal(t, util.ErrTooShort, err)
+}
+
 func TestNNIBlock(t *testing.T) {
 	nni := uint64(0x0102030405060708)
 	blockType := uint32(0x27)
@@ -83,3 +138,62 @@ func TestNNIBlock(t *testing.T) {
 	assert.NoError(t, err)
 	assert.ElementsMatch(t, nniWire, encodedWire)
 }
+
+func TestNNIMinimalLength(t *testing.T) {
+	assert.Equal(t, []byte{0x05}, tlv.EncodeNNI(5))
+	assert.Equal(t, []byte{0xFF}, tlv.EncodeNNI(0xFF))
+	assert.Equal(t, []byte{0x01, 0x00}, tlv.EncodeNNI(0x0100))
+	assert.Equal(t, []byte{0xFF, 0xFF}, tlv.EncodeNNI(0xFFFF))
+	assert.Equal(t, []byte{0x00, 0x01, 0x00, 0x00}, tlv.EncodeNNI(0x010000))
+	assert.Equal(t, []byte{0xFF, 0xFF, 0xFF, 0xFF}, tlv.EncodeNNI(0xFFFFFFFF))
+	assert.Equal(t, []byte{0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00}, tlv.EncodeNNI(0x0100000000))
+
+	encodedWire, err := tlv.EncodeNNIBlock(0x27, 5).Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x27, 0x01, 0x05}, encodedWire)
+	encodedWire, err = tlv.EncodeNNIBlock(0x27, 0x0100).Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x27, 0x02, 0x01, 0x00}, encodedWire)
+
+	for _, value := range []uint64{0, 5, 0xFF, 0x0100, 0xFFFF, 0x010000, 0xFFFFFFFF, 0x0100000000, 0xFFFFFFFFFFFFFFFF} {
+		decoded, err := tlv.DecodeNNI(tlv.EncodeNNI(value))
+		assert.NoError(t, err)
+		assert.Equal(t, value, decoded)
+	}
+}
+
+func TestNNI(t *testing.T) {
+	decoded, err := tlv.DecodeNNI([]byte{0x01})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0x01), decoded)
+
+	decoded, err = tlv.DecodeNNI([]byte{0x01, 0x02})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0x0102), decoded)
+
+	decoded, err = tlv.DecodeNNI([]byte{0x01, 0x02, 0x03, 0x04})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0x01020304), decoded)
+
+	decoded, err = tlv.DecodeNNI([]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0x0102030405060708), decoded)
+
+	_, err = tlv.DecodeNNI([]byte{})
+	assert.Error(t, err)
+	_, err = tlv.DecodeNNI([]byte{0x01, 0x02, 0x03})
+	assert.Error(t, err)
+}
+
+func TestSizeOfVarNumber(t *testing.T) {
+	assert.Equal(t, 1, tlv.SizeOfVarNumber(0))
+	assert.Equal(t, 1, tlv.SizeOfVarNumber(0xFC))
+	assert.Equal(t, 3, tlv.SizeOfVarNumber(0xFD))
+	assert.Equal(t, 3, tlv.SizeOfVarNumber(0xFFFF))
+	assert.Equal(t, 5, tlv.SizeOfVarNumber(0x10000))
+	assert.Equal(t, 5, tlv.SizeOfVarNumber(0xFFFFFFFF))
+	assert.Equal(t, 9, tlv.SizeOfVarNumber(0x100000000))
+	for _, v := range []uint64{0, 0xFC, 0xFD, 0xFFFF, 0x10000, 0xFFFFFFFF, 0x100000000} {
+		assert.Equal(t, len(tlv.EncodeVarNum(v)), tlv.SizeOfVarNumber(v))
+	}
+}
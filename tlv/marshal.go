@@ -0,0 +1,318 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package tlv
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/eric135/go-ndn2/util"
+)
+
+// Marshaler is implemented by types that can encode themselves into a block. The type of the returned block is replaced by the type in the field tag.
+type Marshaler interface {
+	MarshalTLV() (*Block, error)
+}
+
+// Unmarshaler is implemented by types that can decode themselves from a block.
+type Unmarshaler interface {
+	UnmarshalTLV(b *Block) error
+}
+
+var (
+	marshalerType   = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+)
+
+// marshalField describes a struct field tagged for TLV marshaling.
+type marshalField struct {
+	index    int
+	tlvType  uint32
+	optional bool
+}
+
+// parseMarshalFields parses the tlv tags of the fields of the specified struct type. The TLV type of the struct itself is taken from the tag of a blank (_) field, if present.
+func parseMarshalFields(t reflect.Type) (uint32, bool, []marshalField, error) {
+	var structType uint32
+	hasStructType := false
+	fields := make([]marshalField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("tlv")
+		if !ok || tag == "-" {
+			continue
+		}
+
+		options := strings.Split(tag, ",")
+		tlvType, err := strconv.ParseUint(options[0], 0, 32)
+		if err != nil {
+			return 0, false, nil, errors.New("Invalid TLV type in tag of field " + field.Name)
+		}
+		if field.Name == "_" {
+			structType = uint32(tlvType)
+			hasStructType = true
+			continue
+		}
+		if field.PkgPath != "" {
+			return 0, false, nil, errors.New("Field " + field.Name + " with tlv tag is not exported")
+		}
+
+		f := marshalField{index: i, tlvType: uint32(tlvType)}
+		for _, option := range options[1:] {
+			switch option {
+			case "optional":
+				f.optional = true
+			default:
+				return 0, false, nil, errors.New("Unknown option " + option + " in tag of field " + field.Name)
+			}
+		}
+		fields = append(fields, f)
+	}
+	return structType, hasStructType, fields, nil
+}
+
+// isRepeated returns whether values of the specified type are encoded as a sequence of repeated elements.
+func isRepeated(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8
+}
+
+// Marshal encodes a struct into a block, using the tlv tags of its fields. The tag of each field contains its TLV type, optionally followed by ",optional". The TLV type of the block is taken from the tag of a blank (_) field.
+//
+// Supported field types are unsigned integers (encoded as NNIs), bools (encoded as an empty element if true), strings, byte slices, nested structs, pointers to these types (nil pointers are omitted), slices of these types (encoded as repeated elements), and types implementing Marshaler. Optional fields are omitted if they have their zero value.
+func Marshal(v interface{}) (*Block, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, util.ErrNonExistent
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, errors.New("Cannot marshal non-struct type " + rv.Type().String())
+	}
+
+	structType, hasStructType, _, err := parseMarshalFields(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+	if !hasStructType {
+		return nil, errors.New("Struct " + rv.Type().String() + " does not specify its TLV type")
+	}
+	block, err := marshalStruct(rv, structType)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := block.Wire(); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+func marshalStruct(rv reflect.Value, tlvType uint32) (*Block, error) {
+	_, _, fields, err := parseMarshalFields(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	block := NewEmptyBlock(tlvType)
+	for _, f := range fields {
+		fv := rv.Field(f.index)
+		if isRepeated(fv.Type()) {
+			if fv.Len() == 0 && !f.optional {
+				return nil, errors.New("Missing required element " + TypeName(f.tlvType))
+			}
+			for i := 0; i < fv.Len(); i++ {
+				elem, err := marshalValue(fv.Index(i), f.tlvType)
+				if err != nil {
+					return nil, err
+				}
+				block.Append(elem)
+			}
+			continue
+		}
+
+		if fv.Kind() == reflect.Bool && !fv.Bool() {
+			// False is encoded as absence
+			continue
+		}
+		if f.optional && fv.IsZero() {
+			continue
+		}
+		if fv.Kind() == reflect.Ptr && fv.IsNil() {
+			return nil, errors.New("Missing required element " + TypeName(f.tlvType))
+		}
+		elem, err := marshalValue(fv, f.tlvType)
+		if err != nil {
+			return nil, err
+		}
+		block.Append(elem)
+	}
+	return block, nil
+}
+
+func marshalValue(v reflect.Value, tlvType uint32) (*Block, error) {
+	if v.Type().Implements(marshalerType) || (v.CanAddr() && v.Addr().Type().Implements(marshalerType)) {
+		if v.Kind() != reflect.Ptr && v.CanAddr() {
+			v = v.Addr()
+		}
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			return nil, util.ErrNonExistent
+		}
+		block, err := v.Interface().(Marshaler).MarshalTLV()
+		if err != nil {
+			return nil, err
+		}
+		block.SetType(tlvType)
+		return block, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		return marshalValue(v.Elem(), tlvType)
+	case reflect.Bool:
+		return NewEmptyBlock(tlvType), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return EncodeNNIBlock(tlvType, v.Uint()), nil
+	case reflect.String:
+		return NewBlock(tlvType, []byte(v.String())), nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return NewBlock(tlvType, v.Bytes()), nil
+		}
+	case reflect.Struct:
+		return marshalStruct(v, tlvType)
+	}
+	return nil, errors.New("Cannot marshal type " + v.Type().String())
+}
+
+// Unmarshal decodes a block into the struct pointed to by v, using the tlv tags of its fields as described for Marshal. If the struct specifies its TLV type, the type of the block must match.
+//
+// Elements must appear in the order of the struct fields. Unrecognized non-critical elements are ignored, while unrecognized critical elements cause ErrUnrecognizedCritical to be returned.
+func Unmarshal(b *Block, v interface{}) error {
+	if b == nil {
+		return util.ErrNonExistent
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("Unmarshal requires a non-nil pointer")
+	}
+	rv = rv.Elem()
+	if rv.Kind() != reflect.Struct {
+		return errors.New("Cannot unmarshal into non-struct type " + rv.Type().String())
+	}
+
+	structType, hasStructType, _, err := parseMarshalFields(rv.Type())
+	if err != nil {
+		return err
+	}
+	if hasStructType && b.Type() != structType {
+		return ErrUnexpected
+	}
+	return unmarshalStruct(b, rv)
+}
+
+func unmarshalStruct(b *Block, rv reflect.Value) error {
+	_, _, fields, err := parseMarshalFields(rv.Type())
+	if err != nil {
+		return err
+	}
+	if !b.Parse() {
+		return ErrBufferTooShort
+	}
+
+	for _, f := range fields {
+		fv := rv.Field(f.index)
+		fv.Set(reflect.Zero(fv.Type()))
+	}
+
+	present := make([]bool, len(fields))
+	mostRecentField := -1
+	for _, elem := range b.Subelements() {
+		fieldIndex := -1
+		for i, f := range fields {
+			if f.tlvType == elem.Type() {
+				fieldIndex = i
+				break
+			}
+		}
+		if fieldIndex == -1 {
+			if IsCritical(elem.Type()) {
+				return ErrUnrecognizedCritical
+			}
+			// If non-critical, ignore
+			continue
+		}
+
+		fv := rv.Field(fields[fieldIndex].index)
+		repeated := isRepeated(fv.Type())
+		if fieldIndex < mostRecentField || (fieldIndex == mostRecentField && !repeated) {
+			return errors.New(TypeName(elem.Type()) + " is duplicate or out-of-order")
+		}
+		mostRecentField = fieldIndex
+		present[fieldIndex] = true
+
+		if repeated {
+			value := reflect.New(fv.Type().Elem()).Elem()
+			if err := unmarshalValue(elem, value); err != nil {
+				return err
+			}
+			fv.Set(reflect.Append(fv, value))
+		} else if err := unmarshalValue(elem, fv); err != nil {
+			return err
+		}
+	}
+
+	for i, f := range fields {
+		if !present[i] && !f.optional && rv.Field(f.index).Kind() != reflect.Bool {
+			return errors.New("Missing required element " + TypeName(f.tlvType))
+		}
+	}
+	return nil
+}
+
+func unmarshalValue(b *Block, v reflect.Value) error {
+	if v.CanAddr() && v.Addr().Type().Implements(unmarshalerType) {
+		return v.Addr().Interface().(Unmarshaler).UnmarshalTLV(b)
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		ptr := reflect.New(v.Type().Elem())
+		if err := unmarshalValue(b, ptr.Elem()); err != nil {
+			return err
+		}
+		v.Set(ptr)
+		return nil
+	case reflect.Bool:
+		v.SetBool(true)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, err := DecodeNNIBlock(b)
+		if err != nil {
+			return err
+		}
+		if v.OverflowUint(value) {
+			return util.ErrOutOfRange
+		}
+		v.SetUint(value)
+		return nil
+	case reflect.String:
+		v.SetString(string(b.Value()))
+		return nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes(b.Value())
+			return nil
+		}
+	case reflect.Struct:
+		return unmarshalStruct(b, v)
+	}
+	return errors.New("Cannot unmarshal type " + v.Type().String())
+}
@@ -0,0 +1,101 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package tlv_test
+
+import (
+	"testing"
+
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/eric135/go-ndn2/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type marshalInner struct {
+	Value uint8 `tlv:"0x81"`
+}
+
+type marshalOuter struct {
+	_        struct{}       `tlv:"0x80"`
+	Number   uint64         `tlv:"0x82"`
+	Flag     bool           `tlv:"0x84"`
+	Text     string         `tlv:"0x86,optional"`
+	Bytes    []byte         `tlv:"0x88,optional"`
+	Optional *uint32        `tlv:"0x8a,optional"`
+	Inner    marshalInner   `tlv:"0x8c"`
+	Repeated []marshalInner `tlv:"0x8e,optional"`
+	Ignored  int
+}
+
+func TestMarshal(t *testing.T) {
+	optional := uint32(5)
+	v := marshalOuter{
+		Number:   0x0102,
+		Flag:     true,
+		Text:     "ab",
+		Optional: &optional,
+		Inner:    marshalInner{Value: 7},
+		Repeated: []marshalInner{{Value: 1}, {Value: 2}},
+	}
+	block, err := tlv.Marshal(&v)
+	assert.NoError(t, err)
+	wire, err := block.Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x80, 0x1c,
+		0x82, 0x02, 0x01, 0x02,
+		0x84, 0x00,
+		0x86, 0x02, 'a', 'b',
+		0x8a, 0x01, 0x05,
+		0x8c, 0x03, 0x81, 0x01, 0x07,
+		0x8e, 0x03, 0x81, 0x01, 0x01,
+		0x8e, 0x03, 0x81, 0x01, 0x02}, wire)
+
+	decodedBlock, _, err := tlv.DecodeBlock(wire)
+	assert.NoError(t, err)
+	var decoded marshalOuter
+	assert.NoError(t, tlv.Unmarshal(decodedBlock, &decoded))
+	assert.Equal(t, v, decoded)
+
+	// Missing struct type
+	_, err = tlv.Marshal(&v.Inner)
+	assert.Error(t, err)
+	_, err = tlv.Marshal(5)
+	assert.Error(t, err)
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	var v marshalOuter
+	decode := func(tlvType byte, value ...byte) error {
+		block, _, err := tlv.DecodeBlock(append([]byte{tlvType, byte(len(value))}, value...))
+		assert.NoError(t, err)
+		return tlv.Unmarshal(block, &v)
+	}
+
+	// Minimal valid
+	assert.NoError(t, decode(0x80, 0x82, 0x01, 0x01, 0x8c, 0x03, 0x81, 0x01, 0x07))
+	assert.Equal(t, uint64(1), v.Number)
+	assert.False(t, v.Flag)
+	assert.Nil(t, v.Optional)
+	assert.Equal(t, uint8(7), v.Inner.Value)
+
+	// Wrong outer type
+	assert.Equal(t, tlv.ErrUnexpected, decode(0x90, 0x82, 0x01, 0x01, 0x8c, 0x03, 0x81, 0x01, 0x07))
+	// Missing required element
+	assert.Error(t, decode(0x80, 0x82, 0x01, 0x01))
+	// Out-of-order
+	assert.Error(t, decode(0x80, 0x8c, 0x03, 0x81, 0x01, 0x07, 0x82, 0x01, 0x01))
+	// Duplicate
+	assert.Error(t, decode(0x80, 0x82, 0x01, 0x01, 0x82, 0x01, 0x01, 0x8c, 0x03, 0x81, 0x01, 0x07))
+	// Overflow of nested uint8
+	assert.Equal(t, util.ErrOutOfRange, decode(0x80, 0x82, 0x01, 0x01, 0x8c, 0x04, 0x81, 0x02, 0x01, 0x00))
+	// Unrecognized critical element
+	assert.Equal(t, tlv.ErrUnrecognizedCritical, decode(0x80, 0x82, 0x01, 0x01, 0x8d, 0x00, 0x8c, 0x03, 0x81, 0x01, 0x07))
+	// Unrecognized non-critical element is ignored
+	assert.NoError(t, decode(0x80, 0x82, 0x01, 0x01, 0x90, 0x00, 0x8c, 0x03, 0x81, 0x01, 0x07))
+	// Not a pointer
+	assert.Error(t, tlv.Unmarshal(tlv.NewEmptyBlock(0x80), v))
+}
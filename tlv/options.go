@@ -0,0 +1,102 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package tlv
+
+import (
+	"math"
+
+	"github.com/eric135/go-ndn2/util"
+)
+
+// DecodeOptions controls how strictly encodings are checked during decoding. The zero value is lenient, tolerating non-canonical input for interoperability, as a forwarder should. Signers and verifiers should decode strictly, as a non-canonical encoding would change the signed bytes.
+type DecodeOptions struct {
+	// Strict causes non-minimal VAR-NUMBER and NNI encodings, as well as out-of-order elements, to be rejected.
+	Strict bool
+}
+
+// DecodeVarNum decodes a variable-length number, rejecting non-minimal encodings with util.ErrOutOfRange if strict.
+func (o DecodeOptions) DecodeVarNum(in []byte) (uint64, int, error) {
+	if o.Strict {
+		return DecodeVarNumStrict(in)
+	}
+	return DecodeVarNum(in)
+}
+
+// DecodeNNI decodes a non-negative integer, rejecting values not encoded in the minimal number of octets with util.ErrOutOfRange if strict.
+func (o DecodeOptions) DecodeNNI(value []byte) (uint64, error) {
+	v, err := DecodeNNI(value)
+	if err != nil {
+		return 0, err
+	}
+	if o.Strict && len(value) != sizeOfNNI(v) {
+		return 0, util.ErrOutOfRange
+	}
+	return v, nil
+}
+
+// DecodeNNIBlock decodes a non-negative integer from a block, checking its encoding as DecodeNNI does.
+func (o DecodeOptions) DecodeNNIBlock(wire *Block) (uint64, error) {
+	if wire == nil {
+		return 0, util.ErrNonExistent
+	}
+	if err := o.CheckBlock(wire); err != nil {
+		return 0, err
+	}
+	return o.DecodeNNI(wire.value)
+}
+
+// DecodeBlock decodes a single block from the start of the wire like the DecodeBlock function, rejecting a non-minimal TLV-TYPE or TLV-LENGTH if strict.
+func (o DecodeOptions) DecodeBlock(wire []byte) (*Block, uint64, error) {
+	b := new(Block)
+
+	// Decode TLV type
+	tlvType, tlvTypeLen, err := o.DecodeVarNum(wire)
+	if err != nil {
+		return nil, 0, err
+	}
+	if tlvType > math.MaxUint32 {
+		return nil, 0, util.ErrOutOfRange
+	}
+	b.tlvType = uint32(tlvType)
+
+	// Decode TLV length (we don't store this because it's implicit from value slice length)
+	if tlvTypeLen == len(wire) {
+		return nil, 0, ErrMissingLength
+	}
+	tlvLength, tlvLengthLen, err := o.DecodeVarNum(wire[tlvTypeLen:])
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Decode TLV value
+	if tlvLength > uint64(len(wire)-tlvTypeLen-tlvLengthLen) {
+		return nil, 0, ErrBufferTooShort
+	}
+	b.value = make([]byte, tlvLength)
+	copy(b.value, wire[tlvTypeLen+tlvLengthLen:uint64(tlvTypeLen)+uint64(tlvLengthLen)+tlvLength])
+
+	// Add wire
+	b.wire = make([]byte, uint64(tlvTypeLen)+uint64(tlvLengthLen)+tlvLength)
+	copy(b.wire, wire)
+	b.hasWire = true
+
+	return b, uint64(tlvTypeLen) + uint64(tlvLengthLen) + tlvLength, nil
+}
+
+// CheckBlock checks the TLV-TYPE and TLV-LENGTH of the cached wire of the block, returning util.ErrOutOfRange if strict and either is not minimally encoded. Blocks without a cached wire are always encoded minimally.
+func (o DecodeOptions) CheckBlock(b *Block) error {
+	if !o.Strict || !b.hasWire {
+		return nil
+	}
+	_, typeLen, err := DecodeVarNumStrict(b.wire)
+	if err != nil {
+		return err
+	}
+	_, _, err = DecodeVarNumStrict(b.wire[typeLen:])
+	return err
+}
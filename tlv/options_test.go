@@ -0,0 +1,67 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package tlv_test
+
+import (
+	"testing"
+
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/eric135/go-ndn2/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeOptionsNNI(t *testing.T) {
+	lenient := tlv.DecodeOptions{}
+	strict := tlv.DecodeOptions{Strict: true}
+
+	for _, value := range [][]byte{{0x05}, {0x01, 0x00}, {0x00, 0x01, 0x00, 0x00}, {0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00}} {
+		_, err := strict.DecodeNNI(value)
+		assert.NoError(t, err)
+	}
+	for _, value := range [][]byte{{0x00, 0x05}, {0x00, 0x00, 0x01, 0x00}, {0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05}} {
+		decoded, err := lenient.DecodeNNI(value)
+		assert.NoError(t, err)
+		assert.NotZero(t, decoded)
+		_, err = strict.DecodeNNI(value)
+		assert.Equal(t, util.ErrOutOfRange, err)
+	}
+	_, err := strict.DecodeNNI([]byte{0x00, 0x00, 0x05})
+	assert.Error(t, err)
+
+	_, err = strict.DecodeNNIBlock(tlv.NewBlock(0x0C, []byte{0x00, 0x05}))
+	assert.Equal(t, util.ErrOutOfRange, err)
+	_, err = strict.DecodeNNIBlock(nil)
+	assert.Equal(t, util.ErrNonExistent, err)
+}
+
+func TestDecodeOptionsBlock(t *testing.T) {
+	lenient := tlv.DecodeOptions{}
+	strict := tlv.DecodeOptions{Strict: true}
+
+	for _, wire := range [][]byte{
+		{0xFD, 0x00, 0x08, 0x01, 0x41},
+		{0x08, 0xFD, 0x00, 0x01, 0x41},
+		{0x08, 0xFE, 0x00, 0x00, 0x00, 0x01, 0x41},
+	} {
+		block, blockLen, err := lenient.DecodeBlock(wire)
+		assert.NoError(t, err)
+		assert.Equal(t, uint32(0x08), block.Type())
+		assert.Equal(t, []byte{0x41}, block.Value())
+		assert.Equal(t, uint64(len(wire)), blockLen)
+		assert.NoError(t, lenient.CheckBlock(block))
+		assert.Equal(t, util.ErrOutOfRange, strict.CheckBlock(block))
+
+		_, _, err = strict.DecodeBlock(wire)
+		assert.Equal(t, util.ErrOutOfRange, err)
+	}
+
+	block, _, err := strict.DecodeBlock([]byte{0x08, 0x01, 0x41})
+	assert.NoError(t, err)
+	assert.NoError(t, strict.CheckBlock(block))
+	assert.NoError(t, strict.CheckBlock(tlv.NewBlock(0x08, []byte{0x41})))
+}
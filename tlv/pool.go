@@ -0,0 +1,63 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package tlv
+
+import (
+	"sync"
+
+	"github.com/eric135/go-ndn2/util"
+)
+
+// maxPooledBufferSize is the largest buffer capacity that is returned to the pool, to avoid retaining memory used by unusually large packets.
+const maxPooledBufferSize = 65536
+
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(PooledBuffer)
+	},
+}
+
+// PooledBuffer is a reusable buffer holding the wire encoding of a block. It must be released with Release once it is no longer needed, after which its contents must not be accessed.
+type PooledBuffer struct {
+	buf []byte
+}
+
+// EncodeToPooledBuffer encodes the block into a buffer obtained from a pool, avoiding the allocation of a new wire buffer. Unlike Wire, the encoding is not cached in the block.
+func EncodeToPooledBuffer(b *Block) (*PooledBuffer, error) {
+	if b == nil {
+		return nil, util.ErrNonExistent
+	}
+
+	p := bufferPool.Get().(*PooledBuffer)
+	size := b.Size()
+	if cap(p.buf) < size {
+		p.buf = make([]byte, 0, size)
+	}
+	p.buf = b.appendTo(p.buf)
+	return p, nil
+}
+
+// Bytes returns the contents of the buffer, which are only valid until the buffer is released.
+func (p *PooledBuffer) Bytes() []byte {
+	return p.buf
+}
+
+// Len returns the number of bytes in the buffer.
+func (p *PooledBuffer) Len() int {
+	return len(p.buf)
+}
+
+// Release returns the buffer to the pool.
+func (p *PooledBuffer) Release() {
+	if cap(p.buf) > maxPooledBufferSize {
+		p.buf = nil
+	} else {
+		p.buf = p.buf[:0]
+	}
+	bufferPool.Put(p)
+}
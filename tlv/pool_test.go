@@ -0,0 +1,86 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package tlv_test
+
+import (
+	"testing"
+
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/stretchr/testify/assert"
+)
+
+// pooledTestBlock returns a block structured like a typical Data packet.
+func pooledTestBlock() *tlv.Block {
+	name := tlv.NewEmptyBlock(tlv.Name)
+	for _, component := range []string{"go", "ndn", "pool", "test"} {
+		name.Append(tlv.NewBlock(tlv.GenericNameComponent, []byte(component)))
+	}
+	metaInfo := tlv.NewEmptyBlock(tlv.MetaInfo)
+	metaInfo.Append(tlv.EncodeNNIBlock(tlv.FreshnessPeriod, 4000))
+	signatureInfo := tlv.NewEmptyBlock(tlv.SignatureInfo)
+	signatureInfo.Append(tlv.EncodeNNIBlock(tlv.SignatureType, 0))
+
+	data := tlv.NewEmptyBlock(tlv.Data)
+	data.Append(name)
+	data.Append(metaInfo)
+	data.Append(tlv.NewBlock(tlv.Content, make([]byte, 1024)))
+	data.Append(signatureInfo)
+	data.Append(tlv.NewBlock(tlv.SignatureValue, make([]byte, 32)))
+	return data
+}
+
+func TestEncodeToPooledBuffer(t *testing.T) {
+	block := pooledTestBlock()
+	expected, err := block.DeepCopy().Wire()
+	assert.NoError(t, err)
+
+	buffer, err := tlv.EncodeToPooledBuffer(block)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, buffer.Bytes())
+	assert.Equal(t, len(expected), buffer.Len())
+	assert.False(t, block.HasWire())
+	buffer.Release()
+
+	// Reused buffers do not contain stale data
+	small := tlv.NewBlock(0x01, []byte{0x02})
+	buffer, err = tlv.EncodeToPooledBuffer(small)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x01, 0x01, 0x02}, buffer.Bytes())
+	buffer.Release()
+
+	_, err = tlv.EncodeToPooledBuffer(nil)
+	assert.Error(t, err)
+}
+
+func BenchmarkBlockWire(b *testing.B) {
+	block := pooledTestBlock()
+	block.Wire()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		block.ClearWire()
+		if _, err := block.Wire(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodeToPooledBuffer(b *testing.B) {
+	block := pooledTestBlock()
+	block.Wire()
+	block.ClearWire()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buffer, err := tlv.EncodeToPooledBuffer(block)
+		if err != nil {
+			b.Fatal(err)
+		}
+		buffer.Release()
+	}
+}
@@ -7,6 +7,8 @@
 
 package tlv
 
+import "strconv"
+
 // TLV types for NDN.
 const (
 	// Packet types
@@ -55,9 +57,35 @@ const (
 	SignatureTime   = 0x28
 	SignatureSeqNum = 0x2a
 
+	// Certificate
+	ValidityPeriod = 0xfd
+	NotBefore      = 0xfe
+	NotAfter       = 0xff
+
+	// SafeBag
+	SafeBag         = 0x80
+	EncryptedKeyBag = 0x81
+
 	// Link Object
 	Delegation = 0x1f
 	Preference = 0x1e
+
+	// NDNLPv2
+	LpPacket        = 0x64
+	Fragment        = 0x50
+	Sequence        = 0x51
+	FragIndex       = 0x52
+	FragCount       = 0x53
+	PitToken        = 0x62
+	Nack            = 0x0320
+	NackReason      = 0x0321
+	NextHopFaceID   = 0x0330
+	IncomingFaceID  = 0x0331
+	CachePolicy     = 0x0334
+	CachePolicyType = 0x0335
+	CongestionMark  = 0x0340
+	Ack             = 0x0344
+	TxSequence      = 0x0348
 )
 
 // IsCritical returns whether a TLV type is critical.
@@ -67,3 +95,67 @@ func IsCritical(tlvType uint32) bool {
 	}
 	return tlvType&0x1 == 1
 }
+
+// typeNames contains the names of known TLV types. Types whose numbers are reused in different contexts list every name.
+var typeNames = map[uint32]string{
+	ImplicitSha256DigestComponent:   "ImplicitSha256DigestComponent",
+	ParametersSha256DigestComponent: "ParametersSha256DigestComponent",
+	Interest:                        "Interest",
+	Data:                            "Data",
+	Name:                            "Name",
+	GenericNameComponent:            "GenericNameComponent",
+	Nonce:                           "Nonce",
+	InterestLifetime:                "InterestLifetime",
+	MustBeFresh:                     "MustBeFresh",
+	MetaInfo:                        "MetaInfo",
+	Content:                         "Content",
+	SignatureInfo:                   "SignatureInfo",
+	SignatureValue:                  "SignatureValue",
+	ContentType:                     "ContentType",
+	FreshnessPeriod:                 "FreshnessPeriod",
+	FinalBlockID:                    "FinalBlockID",
+	SignatureType:                   "SignatureType",
+	KeyLocator:                      "KeyLocator",
+	KeyDigest:                       "KeyDigest",
+	ForwardingHint:                  "ForwardingHint/Preference",
+	Delegation:                      "Delegation",
+	KeywordNameComponent:            "KeywordNameComponent",
+	SegmentNameComponent:            "SegmentNameComponent/CanBePrefix",
+	ByteOffsetNameComponent:         "ByteOffsetNameComponent/HopLimit",
+	VersionNameComponent:            "VersionNameComponent",
+	TimestampNameComponent:          "TimestampNameComponent/ApplicationParameters",
+	SequenceNumNameComponent:        "SequenceNumNameComponent",
+	SignatureNonce:                  "SignatureNonce",
+	SignatureTime:                   "SignatureTime",
+	SignatureSeqNum:                 "SignatureSeqNum",
+	InterestSignatureInfo:           "InterestSignatureInfo",
+	InterestSignatureValue:          "InterestSignatureValue",
+	ValidityPeriod:                  "ValidityPeriod",
+	NotBefore:                       "NotBefore",
+	NotAfter:                        "NotAfter",
+	SafeBag:                         "SafeBag",
+	EncryptedKeyBag:                 "EncryptedKeyBag",
+	LpPacket:                        "LpPacket",
+	Fragment:                        "Fragment",
+	Sequence:                        "Sequence",
+	FragIndex:                       "FragIndex",
+	FragCount:                       "FragCount",
+	PitToken:                        "PitToken",
+	Nack:                            "Nack",
+	NackReason:                      "NackReason",
+	NextHopFaceID:                   "NextHopFaceId",
+	IncomingFaceID:                  "IncomingFaceId",
+	CachePolicy:                     "CachePolicy",
+	CachePolicyType:                 "CachePolicyType",
+	CongestionMark:                  "CongestionMark",
+	Ack:                             "Ack",
+	TxSequence:                      "TxSequence",
+}
+
+// TypeName returns the name of the specified TLV type, or "Type(<number>)" if the type is unknown.
+func TypeName(tlvType uint32) string {
+	if name, ok := typeNames[tlvType]; ok {
+		return name
+	}
+	return "Type(" + strconv.FormatUint(uint64(tlvType), 10) + ")"
+}
@@ -22,3 +22,37 @@ func TestCritical(t *testing.T) {
 	assert.False(t, tlv.IsCritical(0x2000))
 	assert.True(t, tlv.IsCritical(0x2001))
 }
+
+func TestTypeName(t *testing.T) {
+	assert.Equal(t, "Interest", tlv.TypeName(tlv.Interest))
+	assert.Equal(t, "Data", tlv.TypeName(tlv.Data))
+	assert.Equal(t, "Name", tlv.TypeName(tlv.Name))
+	assert.Equal(t, "MetaInfo", tlv.TypeName(tlv.MetaInfo))
+	assert.Equal(t, "SegmentNameComponent/CanBePrefix", tlv.TypeName(tlv.CanBePrefix))
+	assert.Equal(t, "Type(221)", tlv.TypeName(221))
+}
+
+func TestLpTypes(t *testing.T) {
+	// Values from the NDNLPv2 specification
+	for tlvType, name := range map[uint32]string{
+		100: "LpPacket",
+		80:  "Fragment",
+		81:  "Sequence",
+		82:  "FragIndex",
+		83:  "FragCount",
+		98:  "PitToken",
+		800: "Nack",
+		801: "NackReason",
+		816: "NextHopFaceId",
+		817: "IncomingFaceId",
+		820: "CachePolicy",
+		821: "CachePolicyType",
+		832: "CongestionMark",
+		836: "Ack",
+		840: "TxSequence",
+	} {
+		assert.Equal(t, name, tlv.TypeName(tlvType))
+	}
+	assert.Equal(t, 800, tlv.Nack)
+	assert.Equal(t, 840, tlv.TxSequence)
+}
@@ -0,0 +1,263 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/eric135/go-ndn2/util"
+)
+
+// TrustAnchorStore holds the trust anchor certificates of a validator, indexed by key name. Anchors are either added statically or loaded from directories, which can be re-scanned on demand with Refresh or periodically with StartRefresh. It is safe for concurrent use.
+type TrustAnchorStore struct {
+	static      map[string]*Certificate
+	directories []string
+	fromDirs    map[string]*Certificate
+	// generation is incremented whenever directories or fromDirs is replaced
+	generation uint64
+	stop       chan struct{}
+	lock       sync.RWMutex
+}
+
+// NewTrustAnchorStore creates an empty TrustAnchorStore.
+func NewTrustAnchorStore() *TrustAnchorStore {
+	s := new(TrustAnchorStore)
+	s.static = make(map[string]*Certificate)
+	s.fromDirs = make(map[string]*Certificate)
+	return s
+}
+
+// LoadCertificateFile reads a certificate from a file containing either an "NDN CERTIFICATE" PEM block, as written by KeyChain, or the base64-encoded wire of the certificate (possibly split across lines), as in the .ndncert files of ndn-cxx.
+func LoadCertificateFile(path string) (*Certificate, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var wire []byte
+	if block, _ := pem.Decode(contents); block != nil {
+		if block.Type != keyChainCertificatePEMType {
+			return nil, errors.New("Unexpected PEM block type " + block.Type)
+		}
+		wire = block.Bytes
+	} else {
+		encoded := bytes.Join(bytes.Fields(contents), nil)
+		wire = make([]byte, base64.StdEncoding.DecodedLen(len(encoded)))
+		n, err := base64.StdEncoding.Decode(wire, encoded)
+		if err != nil {
+			return nil, err
+		}
+		wire = wire[:n]
+	}
+
+	block, _, err := tlv.DecodeBlock(wire)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeCertificate(block)
+}
+
+// Add adds a static trust anchor, which remains in the store until removed.
+func (s *TrustAnchorStore) Add(certificate *Certificate) error {
+	if certificate == nil {
+		return util.ErrNonExistent
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	addTrustAnchor(s.static, certificate.DeepCopy())
+	return nil
+}
+
+// AddFile loads a static trust anchor from the specified file, as read by LoadCertificateFile.
+func (s *TrustAnchorStore) AddFile(path string) error {
+	certificate, err := LoadCertificateFile(path)
+	if err != nil {
+		return errors.New("Error loading " + path + ": " + err.Error())
+	}
+	return s.Add(certificate)
+}
+
+// Remove removes the static trust anchor with the specified key name. Anchors loaded from directories are not affected.
+func (s *TrustAnchorStore) Remove(keyName *Name) {
+	if keyName == nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.static, keyName.String())
+}
+
+// AddDirectory loads trust anchors from every file in the specified directory, as read by LoadCertificateFile, and remembers the directory so that Refresh re-scans it. Subdirectories are ignored. If any file cannot be loaded, an error is returned and the directory is not added.
+func (s *TrustAnchorStore) AddDirectory(path string) error {
+	return s.reload(func(directories []string) ([]string, error) {
+		for _, directory := range directories {
+			if directory == path {
+				return nil, errors.New("Directory " + path + " has already been added")
+			}
+		}
+		return append(append([]string(nil), directories...), path), nil
+	})
+}
+
+// Refresh re-scans all directories added with AddDirectory, replacing the anchors loaded from them, so that added, changed, and deleted files take effect. If any file cannot be loaded, an error is returned and the previously loaded anchors are kept.
+func (s *TrustAnchorStore) Refresh() error {
+	return s.reload(func(directories []string) ([]string, error) {
+		return directories, nil
+	})
+}
+
+// reload loads the anchors from the directories returned by update, which is called with the current directories, and stores both. Files are read without holding the lock. If another AddDirectory or Refresh stores its result in the meantime, the load is repeated, so that a directory added concurrently is never lost and an older scan never replaces a newer one.
+func (s *TrustAnchorStore) reload(update func(directories []string) ([]string, error)) error {
+	for {
+		s.lock.RLock()
+		generation := s.generation
+		directories, err := update(s.directories)
+		s.lock.RUnlock()
+		if err != nil {
+			return err
+		}
+
+		anchors, err := loadTrustAnchors(directories)
+		if err != nil {
+			return err
+		}
+
+		s.lock.Lock()
+		if s.generation == generation {
+			s.directories = directories
+			s.fromDirs = anchors
+			s.generation++
+			s.lock.Unlock()
+			return nil
+		}
+		s.lock.Unlock()
+	}
+}
+
+// StartRefresh calls Refresh every interval until StopRefresh is called, replacing any previously started refresh. Errors during periodic refreshes are ignored, keeping the previously loaded anchors.
+func (s *TrustAnchorStore) StartRefresh(interval time.Duration) {
+	s.StopRefresh()
+
+	stop := make(chan struct{})
+	s.lock.Lock()
+	s.stop = stop
+	s.lock.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.Refresh()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// StopRefresh stops periodic refreshes started by StartRefresh, if any.
+func (s *TrustAnchorStore) StopRefresh() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+}
+
+// loadTrustAnchors loads the certificates in all files in the specified directories.
+func loadTrustAnchors(directories []string) (map[string]*Certificate, error) {
+	anchors := make(map[string]*Certificate)
+	for _, directory := range directories {
+		files, err := ioutil.ReadDir(directory)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if file.IsDir() {
+				continue
+			}
+			path := filepath.Join(directory, file.Name())
+			certificate, err := LoadCertificateFile(path)
+			if err != nil {
+				return nil, errors.New("Error loading " + path + ": " + err.Error())
+			}
+			addTrustAnchor(anchors, certificate)
+		}
+	}
+	return anchors, nil
+}
+
+// addTrustAnchor adds the certificate to the index, keeping the certificate with the latest version if there are several for the same key.
+func addTrustAnchor(anchors map[string]*Certificate, certificate *Certificate) {
+	keyName := certificate.KeyName().String()
+	if existing, ok := anchors[keyName]; ok {
+		_, _, _, existingVersion, _ := ParseCertificateName(&existing.name)
+		_, _, _, version, _ := ParseCertificateName(&certificate.name)
+		if version < existingVersion {
+			return
+		}
+	}
+	anchors[keyName] = certificate
+}
+
+// Find returns a copy of the trust anchor with the specified key name, or nil if there is none. Static anchors take precedence over those loaded from directories. Expired anchors are returned, since the validator checks their validity period.
+func (s *TrustAnchorStore) Find(keyName *Name) *Certificate {
+	if keyName == nil {
+		return nil
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if certificate, ok := s.static[keyName.String()]; ok {
+		return certificate.DeepCopy()
+	}
+	if certificate, ok := s.fromDirs[keyName.String()]; ok {
+		return certificate.DeepCopy()
+	}
+	return nil
+}
+
+// Anchors returns copies of all trust anchors in the store.
+func (s *TrustAnchorStore) Anchors() []*Certificate {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	anchors := make([]*Certificate, 0, len(s.static)+len(s.fromDirs))
+	for _, certificate := range s.static {
+		anchors = append(anchors, certificate.DeepCopy())
+	}
+	for keyName, certificate := range s.fromDirs {
+		if _, ok := s.static[keyName]; !ok {
+			anchors = append(anchors, certificate.DeepCopy())
+		}
+	}
+	return anchors
+}
+
+// Expired returns copies of the trust anchors whose ValidityPeriod does not cover the specified time, or that have no ValidityPeriod, so that they can be reported or replaced.
+func (s *TrustAnchorStore) Expired(at time.Time) []*Certificate {
+	var expired []*Certificate
+	for _, certificate := range s.Anchors() {
+		if checkCertificateValidity(certificate, at) != nil {
+			expired = append(expired, certificate)
+		}
+	}
+	return expired
+}
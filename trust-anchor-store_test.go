@@ -0,0 +1,191 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn_test
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	ndn "github.com/eric135/go-ndn2"
+	"github.com/stretchr/testify/assert"
+)
+
+// certificateWire returns the wire encoding of the certificate.
+func certificateWire(t *testing.T, certificate *ndn.Certificate) []byte {
+	encoded, err := certificate.Encode()
+	assert.NoError(t, err)
+	wire, err := encoded.Wire()
+	assert.NoError(t, err)
+	return wire
+}
+
+// writePEMCertificate writes the certificate to the specified file as an "NDN CERTIFICATE" PEM block.
+func writePEMCertificate(t *testing.T, path string, certificate *ndn.Certificate) {
+	contents := pem.EncodeToMemory(&pem.Block{Type: "NDN CERTIFICATE", Bytes: certificateWire(t, certificate)})
+	assert.NoError(t, ioutil.WriteFile(path, contents, 0600))
+}
+
+// writeBase64Certificate writes the certificate to the specified file in base64, split into lines of 64 characters.
+func writeBase64Certificate(t *testing.T, path string, certificate *ndn.Certificate) {
+	encoded := base64.StdEncoding.EncodeToString(certificateWire(t, certificate))
+	var contents []byte
+	for len(encoded) > 64 {
+		contents = append(contents, encoded[:64]+"\n"...)
+		encoded = encoded[64:]
+	}
+	contents = append(contents, encoded+"\n"...)
+	assert.NoError(t, ioutil.WriteFile(path, contents, 0600))
+}
+
+func TestLoadCertificateFile(t *testing.T) {
+	path, err := ioutil.TempDir("", "anchors")
+	assert.NoError(t, err)
+	defer os.RemoveAll(path)
+	root, _ := makeCertificate(t, "/root", nil, nil, time.Now().Add(time.Hour))
+
+	writePEMCertificate(t, filepath.Join(path, "root.pem"), root)
+	certificate, err := ndn.LoadCertificateFile(filepath.Join(path, "root.pem"))
+	assert.NoError(t, err)
+	assert.True(t, root.Name().Equals(certificate.Name()))
+
+	writeBase64Certificate(t, filepath.Join(path, "root.ndncert"), root)
+	certificate, err = ndn.LoadCertificateFile(filepath.Join(path, "root.ndncert"))
+	assert.NoError(t, err)
+	assert.True(t, root.Name().Equals(certificate.Name()))
+
+	// Invalid files
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(path, "invalid"), []byte("not a certificate"), 0600))
+	_, err = ndn.LoadCertificateFile(filepath.Join(path, "invalid"))
+	assert.Error(t, err)
+	_, err = ndn.LoadCertificateFile(filepath.Join(path, "missing"))
+	assert.Error(t, err)
+}
+
+func TestTrustAnchorStore(t *testing.T) {
+	notAfter := time.Now().Add(time.Hour)
+	root, rootKey := makeCertificate(t, "/root", nil, nil, notAfter)
+	other, _ := makeCertificate(t, "/other", nil, nil, notAfter)
+	expired, _ := makeCertificate(t, "/expired", nil, nil, time.Now().Add(-time.Minute))
+
+	store := ndn.NewTrustAnchorStore()
+	assert.Nil(t, store.Find(root.KeyName()))
+	assert.NoError(t, store.Add(root))
+	assert.NoError(t, store.Add(expired))
+	assert.Error(t, store.Add(nil))
+	assert.True(t, root.Name().Equals(store.Find(root.KeyName()).Name()))
+	assert.Nil(t, store.Find(other.KeyName()))
+	assert.Nil(t, store.Find(nil))
+	assert.Len(t, store.Anchors(), 2)
+
+	// Expired anchors are reported
+	expiredAnchors := store.Expired(time.Now())
+	assert.Len(t, expiredAnchors, 1)
+	assert.True(t, expired.Name().Equals(expiredAnchors[0].Name()))
+	assert.Len(t, store.Expired(time.Now().Add(2*time.Hour)), 2)
+
+	// Validator consults the store
+	name, _ := ndn.NameFromString("/root/data")
+	d := ndn.NewData(name, []byte{0x01, 0x02})
+	assert.NoError(t, d.Sign(ndn.NewEcdsaSigner(root.KeyName(), rootKey)))
+	validator := ndn.NewHierarchicalValidator(nil)
+	assert.Nil(t, validator.TrustAnchors())
+	assert.Error(t, validator.Validate(d, nil))
+	validator.SetTrustAnchors(store)
+	assert.Equal(t, store, validator.TrustAnchors())
+	assert.NoError(t, validator.Validate(d, nil))
+
+	store.Remove(root.KeyName())
+	assert.Nil(t, store.Find(root.KeyName()))
+	assert.Error(t, validator.Validate(d, nil))
+}
+
+func TestTrustAnchorStoreDirectory(t *testing.T) {
+	path, err := ioutil.TempDir("", "anchors")
+	assert.NoError(t, err)
+	defer os.RemoveAll(path)
+	notAfter := time.Now().Add(time.Hour)
+	root, _ := makeCertificate(t, "/root", nil, nil, notAfter)
+	other, _ := makeCertificate(t, "/other", nil, nil, notAfter)
+
+	writePEMCertificate(t, filepath.Join(path, "root.pem"), root)
+	assert.NoError(t, os.Mkdir(filepath.Join(path, "subdirectory"), 0700))
+	store := ndn.NewTrustAnchorStore()
+	assert.NoError(t, store.AddDirectory(path))
+	assert.Error(t, store.AddDirectory(path))
+	assert.NotNil(t, store.Find(root.KeyName()))
+	assert.Nil(t, store.Find(other.KeyName()))
+
+	// Refresh picks up added and deleted files
+	writeBase64Certificate(t, filepath.Join(path, "other.ndncert"), other)
+	assert.NoError(t, os.Remove(filepath.Join(path, "root.pem")))
+	assert.Nil(t, store.Find(other.KeyName()))
+	assert.NoError(t, store.Refresh())
+	assert.NotNil(t, store.Find(other.KeyName()))
+	assert.Nil(t, store.Find(root.KeyName()))
+
+	// Invalid files cause the refresh to fail, keeping the loaded anchors
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(path, "invalid"), []byte("not a certificate"), 0600))
+	assert.Error(t, store.Refresh())
+	assert.NotNil(t, store.Find(other.KeyName()))
+	assert.NoError(t, os.Remove(filepath.Join(path, "invalid")))
+
+	// Periodic refresh
+	store.StartRefresh(10 * time.Millisecond)
+	defer store.StopRefresh()
+	writePEMCertificate(t, filepath.Join(path, "root.pem"), root)
+	assert.Eventually(t, func() bool {
+		return store.Find(root.KeyName()) != nil
+	}, time.Second, 10*time.Millisecond)
+	store.StopRefresh()
+
+	// Missing directory
+	assert.Error(t, ndn.NewTrustAnchorStore().AddDirectory(filepath.Join(path, "missing")))
+}
+
+func TestTrustAnchorStoreConcurrentDirectories(t *testing.T) {
+	notAfter := time.Now().Add(time.Hour)
+	var directories []string
+	var certificates []*ndn.Certificate
+	for _, identity := range []string{"/a", "/b", "/c", "/d"} {
+		path, err := ioutil.TempDir("", "anchors")
+		assert.NoError(t, err)
+		defer os.RemoveAll(path)
+		certificate, _ := makeCertificate(t, identity, nil, nil, notAfter)
+		writePEMCertificate(t, filepath.Join(path, "anchor.pem"), certificate)
+		directories = append(directories, path)
+		certificates = append(certificates, certificate)
+	}
+
+	// Refreshes running alongside AddDirectory must not drop the added directories
+	store := ndn.NewTrustAnchorStore()
+	var wg sync.WaitGroup
+	for _, directory := range directories {
+		wg.Add(2)
+		go func(directory string) {
+			defer wg.Done()
+			assert.NoError(t, store.AddDirectory(directory))
+		}(directory)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, store.Refresh())
+		}()
+	}
+	wg.Wait()
+
+	for _, certificate := range certificates {
+		assert.NotNil(t, store.Find(certificate.KeyName()))
+	}
+	assert.NoError(t, store.Refresh())
+	assert.Len(t, store.Anchors(), len(certificates))
+}
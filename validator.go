@@ -0,0 +1,191 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"time"
+
+	"github.com/eric135/go-ndn2/util"
+)
+
+// DefaultMaxCertificateChainDepth is the default maximum number of certificates fetched while validating a single packet.
+const DefaultMaxCertificateChainDepth = 5
+
+// Validator verifies the signatures of Data packets.
+type Validator interface {
+	// Validate returns nil if the Data is trusted under the specified trust anchors, or an error describing why it is not.
+	Validate(d *Data, anchors []*Certificate) error
+}
+
+// CertificateFetcher retrieves the certificate of the key with the specified name.
+type CertificateFetcher func(keyName *Name) (*Certificate, error)
+
+// HierarchicalValidator trusts packets whose signing key belongs to an identity that is a prefix of the packet name, following the certificate chain up to a trust anchor.
+type HierarchicalValidator struct {
+	fetcher      CertificateFetcher
+	maxDepth     int
+	trustAnchors *TrustAnchorStore
+}
+
+// NewHierarchicalValidator creates a HierarchicalValidator that retrieves intermediate certificates using the specified fetcher (which may be nil if all signers are trust anchors).
+func NewHierarchicalValidator(fetcher CertificateFetcher) *HierarchicalValidator {
+	v := new(HierarchicalValidator)
+	v.fetcher = fetcher
+	v.maxDepth = DefaultMaxCertificateChainDepth
+	return v
+}
+
+// MaxDepth returns the maximum number of certificates fetched while validating a single packet.
+func (v *HierarchicalValidator) MaxDepth() int {
+	return v.maxDepth
+}
+
+// SetMaxDepth sets the maximum number of certificates fetched while validating a single packet.
+func (v *HierarchicalValidator) SetMaxDepth(maxDepth int) {
+	v.maxDepth = maxDepth
+}
+
+// TrustAnchors returns the store of trust anchors consulted in addition to those passed to Validate, or nil if none is set.
+func (v *HierarchicalValidator) TrustAnchors() *TrustAnchorStore {
+	return v.trustAnchors
+}
+
+// SetTrustAnchors sets the store of trust anchors consulted in addition to those passed to Validate (or unsets it if nil is specified).
+func (v *HierarchicalValidator) SetTrustAnchors(trustAnchors *TrustAnchorStore) {
+	v.trustAnchors = trustAnchors
+}
+
+// Validate returns nil if the Data is trusted under the specified trust anchors or those in the TrustAnchorStore of the validator, or an error describing why it is not.
+func (v *HierarchicalValidator) Validate(d *Data, anchors []*Certificate) error {
+	if d == nil {
+		return util.ErrNonExistent
+	}
+
+	now := time.Now()
+	seen := make(map[string]bool)
+	packet := d
+	for depth := 0; ; depth++ {
+		keyName, err := hierarchicalSigningKey(packet)
+		if err != nil {
+			return err
+		}
+
+		// Check whether the signer is a trust anchor
+		if anchor := v.findTrustAnchor(keyName, anchors); anchor != nil {
+			if err := checkCertificateValidity(anchor, now); err != nil {
+				return err
+			}
+			return verifyDataSignature(packet, anchor)
+		}
+
+		// Otherwise, retrieve the signer's certificate and move up the chain
+		if depth >= v.maxDepth {
+			return errors.New("Certificate chain exceeds maximum depth")
+		}
+		if v.fetcher == nil {
+			return errors.New("Signing certificate is not a trust anchor and cannot be fetched")
+		}
+		if seen[keyName.String()] {
+			return errors.New("Certificate chain contains a loop")
+		}
+		seen[keyName.String()] = true
+
+		certificate, err := v.fetcher(keyName)
+		if err != nil {
+			return err
+		}
+		if certificate == nil || !certificate.KeyName().Equals(keyName) {
+			return errors.New("Fetched certificate does not match KeyLocator")
+		}
+		if err := checkCertificateValidity(certificate, now); err != nil {
+			return err
+		}
+		if err := verifyDataSignature(packet, certificate); err != nil {
+			return err
+		}
+		packet = &certificate.Data
+	}
+}
+
+// findTrustAnchor returns the trust anchor with the specified key name from the specified anchors or, failing that, the TrustAnchorStore of the validator, or nil if there is none.
+func (v *HierarchicalValidator) findTrustAnchor(keyName *Name, anchors []*Certificate) *Certificate {
+	for _, anchor := range anchors {
+		if anchor.KeyName().Equals(keyName) {
+			return anchor
+		}
+	}
+	if v.trustAnchors != nil {
+		return v.trustAnchors.Find(keyName)
+	}
+	return nil
+}
+
+// hierarchicalSigningKey returns the name of the key that signed the packet, checking that its identity is a prefix of the packet name.
+func hierarchicalSigningKey(d *Data) (*Name, error) {
+	if d.signatureInfo == nil || d.signatureInfo.keyLocator == nil || d.signatureInfo.keyLocator.name == nil {
+		return nil, errors.New("Packet does not have a KeyLocator name")
+	}
+	keyName := d.signatureInfo.keyLocator.name
+	if keyName.Size() < 2 || !isKeyComponent(keyName.At(keyName.Size()-2)) {
+		return nil, errors.New("KeyLocator is not a key name")
+	}
+	if !keyName.Prefix(keyName.Size() - 2).PrefixOf(&d.name) {
+		return nil, errors.New("Signing identity is not a prefix of the packet name")
+	}
+	return keyName.DeepCopy(), nil
+}
+
+// checkCertificateValidity checks that the certificate has a ValidityPeriod covering the specified time.
+func checkCertificateValidity(certificate *Certificate, at time.Time) error {
+	validityPeriod := certificate.ValidityPeriod()
+	if validityPeriod == nil || !validityPeriod.IsValid(at) {
+		return errors.New("Certificate " + certificate.name.String() + " is not valid")
+	}
+	return nil
+}
+
+// verifyDataSignature verifies the signature of the Data using the public key in the certificate, accepting only public-key signature types matching the algorithm of that key, since DigestSha256 and HMAC signatures do not prove possession of the certified key.
+func verifyDataSignature(d *Data, certificate *Certificate) error {
+	publicKey, err := certificate.PublicKey()
+	if err != nil {
+		return err
+	}
+	var keyMatches bool
+	switch d.signatureInfo.signatureType {
+	case SignatureSha256WithRsa:
+		_, keyMatches = publicKey.(*rsa.PublicKey)
+	case SignatureSha256WithEcdsa:
+		_, keyMatches = publicKey.(*ecdsa.PublicKey)
+	case SignatureEd25519:
+		_, keyMatches = publicKey.(ed25519.PublicKey)
+	default:
+		return errors.New("Signature type is not a public-key signature")
+	}
+	if !keyMatches {
+		return errors.New("Signature type does not match certificate key")
+	}
+	verifier, err := NewVerifier(d.signatureInfo.signatureType, publicKey)
+	if err != nil {
+		return err
+	}
+	return d.Verify(verifier)
+}
+
+// verifySignature verifies a signature of the specified type over the input using a Verifier for the public key, which must match the signature type. The public key is ignored for DigestSha256 signatures.
+func verifySignature(signatureType uint64, publicKey crypto.PublicKey, input []byte, signatureValue []byte) error {
+	verifier, err := NewVerifier(signatureType, publicKey)
+	if err != nil {
+		return err
+	}
+	return verifier.Verify(input, signatureValue)
+}
@@ -0,0 +1,242 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+
+	ndn "github.com/eric135/go-ndn2"
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/stretchr/testify/assert"
+)
+
+// makeCertificate creates a certificate for the specified identity, signed by the issuer key (or self-signed if issuerKey is nil).
+func makeCertificate(t *testing.T, identity string, issuerKeyName *ndn.Name, issuerKey *ecdsa.PrivateKey, notAfter time.Time) (*ndn.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+
+	keyName, err := ndn.NameFromString(identity + "/KEY/abcd")
+	assert.NoError(t, err)
+	if issuerKey == nil {
+		issuerKeyName, issuerKey = keyName, key
+	}
+	d := ndn.NewData(keyName.DeepCopy().AppendGeneric([]byte("issuer")).AppendVersion(1), publicKey)
+	signatureInfo := ndn.NewSignatureInfo(ndn.SignatureSha256WithEcdsa)
+	signatureInfo.SetValidityPeriod(ndn.NewValidityPeriod(time.Now().Add(-time.Hour), notAfter))
+	d.SetSignatureInfo(signatureInfo)
+	assert.NoError(t, d.Sign(ndn.NewEcdsaSigner(issuerKeyName, issuerKey)))
+	certificate, err := ndn.NewCertificate(d)
+	assert.NoError(t, err)
+	return certificate, key
+}
+
+// forgedDigestSigner produces DigestSha256 signatures carrying the KeyLocator of the specified key, which anyone can compute without the key.
+type forgedDigestSigner struct {
+	keyName *ndn.Name
+}
+
+func (s *forgedDigestSigner) Type() uint64 {
+	return ndn.SignatureDigestSha256
+}
+
+func (s *forgedDigestSigner) KeyLocator() *ndn.KeyLocator {
+	return ndn.NewKeyLocatorName(s.keyName)
+}
+
+func (s *forgedDigestSigner) ComputeSignatureValue(signedPortion []byte) ([]byte, error) {
+	digest := sha256.Sum256(signedPortion)
+	return digest[:], nil
+}
+
+func TestHierarchicalValidator(t *testing.T) {
+	notAfter := time.Now().Add(time.Hour)
+	root, rootKey := makeCertificate(t, "/root", nil, nil, notAfter)
+	site, siteKey := makeCertificate(t, "/root/site", root.KeyName(), rootKey, notAfter)
+	certificates := map[string]*ndn.Certificate{site.KeyName().String(): site}
+	fetcher := func(keyName *ndn.Name) (*ndn.Certificate, error) {
+		if certificate, ok := certificates[keyName.String()]; ok {
+			return certificate, nil
+		}
+		return nil, errors.New("Not found")
+	}
+	anchors := []*ndn.Certificate{root}
+
+	name, _ := ndn.NameFromString("/root/site/data")
+	d := ndn.NewData(name, []byte{0x01, 0x02})
+	assert.NoError(t, d.Sign(ndn.NewEcdsaSigner(site.KeyName(), siteKey)))
+
+	// Valid chain
+	validator := ndn.NewHierarchicalValidator(fetcher)
+	assert.Equal(t, ndn.DefaultMaxCertificateChainDepth, validator.MaxDepth())
+	assert.NoError(t, validator.Validate(d, anchors))
+
+	// Signed directly by trust anchor
+	rootSigned := d.DeepCopy()
+	assert.NoError(t, rootSigned.Sign(ndn.NewEcdsaSigner(root.KeyName(), rootKey)))
+	assert.NoError(t, ndn.NewHierarchicalValidator(nil).Validate(rootSigned, anchors))
+
+	// No trust anchors
+	assert.Error(t, validator.Validate(d, nil))
+
+	// Cannot fetch intermediate certificate
+	assert.Error(t, ndn.NewHierarchicalValidator(nil).Validate(d, anchors))
+
+	// Depth limit
+	validator.SetMaxDepth(0)
+	assert.Error(t, validator.Validate(d, anchors))
+	validator.SetMaxDepth(ndn.DefaultMaxCertificateChainDepth)
+
+	// Signing identity is not a prefix of the packet name
+	otherName, _ := ndn.NameFromString("/other/data")
+	other := ndn.NewData(otherName, []byte{0x01, 0x02})
+	assert.NoError(t, other.Sign(ndn.NewEcdsaSigner(site.KeyName(), siteKey)))
+	assert.Error(t, validator.Validate(other, anchors))
+
+	// Tampered content
+	tampered := d.DeepCopy()
+	tampered.SetContent([]byte{0x03})
+	assert.Error(t, validator.Validate(tampered, anchors))
+
+	// Digest signature has no KeyLocator
+	digestSigned := d.DeepCopy()
+	assert.NoError(t, digestSigned.Sign(ndn.NewSha256Signer()))
+	assert.Error(t, validator.Validate(digestSigned, anchors))
+
+	// Digest signature forged with the KeyLocator of a trust anchor
+	forged := ndn.NewData(name, []byte{0x01, 0x02})
+	assert.NoError(t, forged.Sign(&forgedDigestSigner{keyName: root.KeyName()}))
+	assert.Equal(t, uint64(ndn.SignatureDigestSha256), forged.SignatureInfo().SignatureType())
+	assert.Error(t, validator.Validate(forged, anchors))
+
+	// HMAC signature with the KeyLocator of a trust anchor
+	hmacSigned := ndn.NewData(name, []byte{0x01, 0x02})
+	assert.NoError(t, hmacSigned.Sign(ndn.NewHmacSigner(root.KeyName(), []byte("secret"))))
+	assert.Error(t, validator.Validate(hmacSigned, anchors))
+
+	// Expired intermediate certificate
+	expired, expiredKey := makeCertificate(t, "/root/expired", root.KeyName(), rootKey, time.Now().Add(-time.Minute))
+	certificates[expired.KeyName().String()] = expired
+	expiredName, _ := ndn.NameFromString("/root/expired/data")
+	expiredSigned := ndn.NewData(expiredName, []byte{0x01})
+	assert.NoError(t, expiredSigned.Sign(ndn.NewEcdsaSigner(expired.KeyName(), expiredKey)))
+	assert.Error(t, validator.Validate(expiredSigned, anchors))
+
+	// Self-signed certificate that is not an anchor forms a loop
+	loop, loopKey := makeCertificate(t, "/root/loop", nil, nil, notAfter)
+	certificates[loop.KeyName().String()] = loop
+	loopName, _ := ndn.NameFromString("/root/loop/data")
+	loopSigned := ndn.NewData(loopName, []byte{0x01})
+	assert.NoError(t, loopSigned.Sign(ndn.NewEcdsaSigner(loop.KeyName(), loopKey)))
+	assert.Error(t, validator.Validate(loopSigned, anchors))
+}
+
+func TestValidateDecodedData(t *testing.T) {
+	root, rootKey := makeCertificate(t, "/root", nil, nil, time.Now().Add(time.Hour))
+	anchors := []*ndn.Certificate{root}
+	keyLocator := root.KeyName().Encode()
+	keyLocatorWire, err := keyLocator.Wire()
+	assert.NoError(t, err)
+
+	// Signed portion with a non-minimal FreshnessPeriod and an unrecognized non-critical element
+	signed := []byte{
+		tlv.Name, 0x0b, tlv.GenericNameComponent, 0x04, 0x72, 0x6f, 0x6f, 0x74, tlv.GenericNameComponent, 0x03, 0x6e, 0x64, 0x6e,
+		tlv.MetaInfo, 0x06, tlv.FreshnessPeriod, 0x04, 0x00, 0x00, 0x03, 0xe8,
+		tlv.Content, 0x02, 0x01, 0x02,
+		0xF0, 0x01, 0xAA}
+	signatureInfo := append([]byte{tlv.SignatureType, 0x01, ndn.SignatureSha256WithEcdsa, tlv.KeyLocator, byte(len(keyLocatorWire))}, keyLocatorWire...)
+	signed = append(append(signed, tlv.SignatureInfo, byte(len(signatureInfo))), signatureInfo...)
+	signatureValue, err := ndn.NewEcdsaSigner(root.KeyName(), rootKey).ComputeSignatureValue(signed)
+	assert.NoError(t, err)
+	value := append(append(append([]byte{}, signed...), tlv.SignatureValue, byte(len(signatureValue))), signatureValue...)
+	wire, err := tlv.NewBlock(tlv.Data, value).Wire()
+	assert.NoError(t, err)
+
+	block, _, err := tlv.DecodeBlock(wire)
+	assert.NoError(t, err)
+	d, err := ndn.DecodeData(block)
+	assert.NoError(t, err)
+	assert.NoError(t, ndn.NewHierarchicalValidator(nil).Validate(d, anchors))
+
+	// Re-encoding an unmodified Data reproduces the original wire
+	encoded, err := d.Encode()
+	assert.NoError(t, err)
+	encodedWire, err := encoded.Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, wire, encodedWire)
+	assert.NoError(t, ndn.NewHierarchicalValidator(nil).Validate(d.DeepCopy(), anchors))
+
+	// Modifying the Data invalidates the signature until it is re-signed
+	d.SetContent([]byte{0x03})
+	assert.Error(t, ndn.NewHierarchicalValidator(nil).Validate(d, anchors))
+	assert.NoError(t, d.Sign(ndn.NewEcdsaSigner(root.KeyName(), rootKey)))
+	assert.NoError(t, ndn.NewHierarchicalValidator(nil).Validate(d, anchors))
+}
+
+// makeSelfSignedCertificate creates a self-signed certificate for the specified identity containing the public key, signed by the signer returned for the key name.
+func makeSelfSignedCertificate(t *testing.T, identity string, publicKey crypto.PublicKey, newSigner func(keyName *ndn.Name) ndn.Signer) *ndn.Certificate {
+	encodedKey, err := x509.MarshalPKIXPublicKey(publicKey)
+	assert.NoError(t, err)
+	keyName, err := ndn.NameFromString(identity + "/KEY/abcd")
+	assert.NoError(t, err)
+	d := ndn.NewData(keyName.DeepCopy().AppendGeneric([]byte("self")).AppendVersion(1), encodedKey)
+	signatureInfo := ndn.NewSignatureInfo(ndn.SignatureDigestSha256)
+	signatureInfo.SetValidityPeriod(ndn.NewValidityPeriod(time.Now().Add(-time.Hour), time.Now().Add(time.Hour)))
+	d.SetSignatureInfo(signatureInfo)
+	assert.NoError(t, d.Sign(newSigner(keyName)))
+	certificate, err := ndn.NewCertificate(d)
+	assert.NoError(t, err)
+	return certificate
+}
+
+func TestValidateKeyTypes(t *testing.T) {
+	name, _ := ndn.NameFromString("/root/data")
+
+	// Ed25519
+	edPublicKey, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	edRoot := makeSelfSignedCertificate(t, "/root", edPublicKey, func(keyName *ndn.Name) ndn.Signer {
+		return ndn.NewEd25519Signer(keyName, edKey)
+	})
+	d := ndn.NewData(name, []byte{0x01, 0x02})
+	assert.NoError(t, d.Sign(ndn.NewEd25519Signer(edRoot.KeyName(), edKey)))
+	assert.NoError(t, ndn.NewHierarchicalValidator(nil).Validate(d, []*ndn.Certificate{edRoot}))
+	tampered := d.DeepCopy()
+	tampered.SetContent([]byte{0x03})
+	assert.Error(t, ndn.NewHierarchicalValidator(nil).Validate(tampered, []*ndn.Certificate{edRoot}))
+
+	// P-384
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assert.NoError(t, err)
+	ecRoot := makeSelfSignedCertificate(t, "/root", &ecKey.PublicKey, func(keyName *ndn.Name) ndn.Signer {
+		return ndn.NewEcdsaSigner(keyName, ecKey)
+	})
+	d = ndn.NewData(name, []byte{0x01, 0x02})
+	assert.NoError(t, d.Sign(ndn.NewEcdsaSigner(ecRoot.KeyName(), ecKey)))
+	assert.NoError(t, ndn.NewHierarchicalValidator(nil).Validate(d, []*ndn.Certificate{ecRoot}))
+
+	// Signature type does not match the key in the certificate
+	mismatched := d.DeepCopy()
+	signatureInfo := mismatched.SignatureInfo()
+	signatureInfo.SetSignatureType(ndn.SignatureEd25519)
+	mismatched.SetSignatureInfo(signatureInfo)
+	assert.Error(t, ndn.NewHierarchicalValidator(nil).Validate(mismatched, []*ndn.Certificate{ecRoot}))
+	d = ndn.NewData(name, []byte{0x01, 0x02})
+	assert.NoError(t, d.Sign(ndn.NewEd25519Signer(ecRoot.KeyName(), edKey)))
+	assert.Error(t, ndn.NewHierarchicalValidator(nil).Validate(d, []*ndn.Certificate{ecRoot}))
+}
@@ -0,0 +1,152 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn
+
+import (
+	"errors"
+	"time"
+
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/eric135/go-ndn2/util"
+)
+
+// validityPeriodTimeFormat is the format of NotBefore and NotAfter: UTC, with a literal 'T' separating the date and time.
+const validityPeriodTimeFormat = "20060102T150405"
+
+// ValidityPeriod represents the period during which a certificate is valid.
+type ValidityPeriod struct {
+	notBefore time.Time
+	notAfter  time.Time
+}
+
+// NewValidityPeriod creates a ValidityPeriod between the specified times, truncated to whole seconds in UTC.
+func NewValidityPeriod(notBefore time.Time, notAfter time.Time) *ValidityPeriod {
+	v := new(ValidityPeriod)
+	v.SetNotBefore(notBefore)
+	v.SetNotAfter(notAfter)
+	return v
+}
+
+// DecodeValidityPeriod decodes a ValidityPeriod from the wire.
+func DecodeValidityPeriod(wire *tlv.Block) (*ValidityPeriod, error) {
+	return decodeValidityPeriod(wire, tlv.DecodeOptions{})
+}
+
+// decodeValidityPeriod decodes a ValidityPeriod from the wire, checking the encoding according to the specified options.
+func decodeValidityPeriod(wire *tlv.Block, opts tlv.DecodeOptions) (*ValidityPeriod, error) {
+	if wire == nil {
+		return nil, util.ErrNonExistent
+	}
+	if !wire.Parse() {
+		return nil, tlv.ErrBufferTooShort
+	}
+	if err := checkElements(wire, opts); err != nil {
+		return nil, err
+	}
+
+	v := new(ValidityPeriod)
+	mostRecentElem := 0
+	for _, elem := range wire.Subelements() {
+		switch elem.Type() {
+		case tlv.NotBefore:
+			if mostRecentElem >= 1 {
+				return nil, errors.New("NotBefore is duplicate or out-of-order")
+			}
+			mostRecentElem = 1
+			notBefore, err := parseValidityPeriodTime(elem.Value())
+			if err != nil {
+				return nil, errors.New("Error decoding NotBefore")
+			}
+			v.notBefore = notBefore
+		case tlv.NotAfter:
+			if mostRecentElem >= 2 {
+				return nil, errors.New("NotAfter is duplicate or out-of-order")
+			}
+			if mostRecentElem < 1 {
+				return nil, errors.New("ValidityPeriod is missing NotBefore")
+			}
+			mostRecentElem = 2
+			notAfter, err := parseValidityPeriodTime(elem.Value())
+			if err != nil {
+				return nil, errors.New("Error decoding NotAfter")
+			}
+			v.notAfter = notAfter
+		default:
+			if tlv.IsCritical(elem.Type()) {
+				return nil, tlv.ErrUnrecognizedCritical
+			}
+			// If non-critical, ignore
+		}
+	}
+
+	if mostRecentElem < 2 {
+		return nil, errors.New("ValidityPeriod is missing NotAfter")
+	}
+	return v, nil
+}
+
+// parseValidityPeriodTime parses a timestamp in the exact YYYYMMDDTHHMMSS format.
+func parseValidityPeriodTime(value []byte) (time.Time, error) {
+	if len(value) != len(validityPeriodTimeFormat) {
+		return time.Time{}, util.ErrOutOfRange
+	}
+	for i, c := range value {
+		if i == 8 {
+			if c != 'T' {
+				return time.Time{}, util.ErrOutOfRange
+			}
+		} else if c < '0' || c > '9' {
+			return time.Time{}, util.ErrOutOfRange
+		}
+	}
+	return time.ParseInLocation(validityPeriodTimeFormat, string(value), time.UTC)
+}
+
+func (v *ValidityPeriod) String() string {
+	return "ValidityPeriod(NotBefore=" + v.notBefore.Format(validityPeriodTimeFormat) + ", NotAfter=" + v.notAfter.Format(validityPeriodTimeFormat) + ")"
+}
+
+// DeepCopy returns a deep copy of the ValidityPeriod.
+func (v *ValidityPeriod) DeepCopy() *ValidityPeriod {
+	copyV := *v
+	return &copyV
+}
+
+// NotBefore returns the beginning of the validity period.
+func (v *ValidityPeriod) NotBefore() time.Time {
+	return v.notBefore
+}
+
+// SetNotBefore sets the beginning of the validity period, truncated to whole seconds in UTC.
+func (v *ValidityPeriod) SetNotBefore(notBefore time.Time) {
+	v.notBefore = notBefore.UTC().Truncate(time.Second)
+}
+
+// NotAfter returns the end of the validity period.
+func (v *ValidityPeriod) NotAfter() time.Time {
+	return v.notAfter
+}
+
+// SetNotAfter sets the end of the validity period, truncated to whole seconds in UTC.
+func (v *ValidityPeriod) SetNotAfter(notAfter time.Time) {
+	v.notAfter = notAfter.UTC().Truncate(time.Second)
+}
+
+// IsValid returns whether the specified time falls within the validity period (inclusive).
+func (v *ValidityPeriod) IsValid(at time.Time) bool {
+	return !at.Before(v.notBefore) && !at.After(v.notAfter)
+}
+
+// Encode encodes the ValidityPeriod into a block.
+func (v *ValidityPeriod) Encode() *tlv.Block {
+	wire := tlv.NewEmptyBlock(tlv.ValidityPeriod)
+	wire.Append(tlv.NewBlock(tlv.NotBefore, []byte(v.notBefore.Format(validityPeriodTimeFormat))))
+	wire.Append(tlv.NewBlock(tlv.NotAfter, []byte(v.notAfter.Format(validityPeriodTimeFormat))))
+	wire.Wire()
+	return wire
+}
@@ -0,0 +1,78 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn_test
+
+import (
+	"testing"
+	"time"
+
+	ndn "github.com/eric135/go-ndn2"
+	"github.com/eric135/go-ndn2/tlv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidityPeriod(t *testing.T) {
+	notBefore := time.Date(2020, time.January, 2, 3, 4, 5, 600, time.UTC)
+	notAfter := time.Date(2021, time.December, 31, 23, 59, 59, 0, time.UTC)
+	v := ndn.NewValidityPeriod(notBefore, notAfter)
+	assert.Equal(t, time.Date(2020, time.January, 2, 3, 4, 5, 0, time.UTC), v.NotBefore())
+	assert.Equal(t, notAfter, v.NotAfter())
+	assert.Equal(t, "ValidityPeriod(NotBefore=20200102T030405, NotAfter=20211231T235959)", v.String())
+
+	assert.False(t, v.IsValid(time.Date(2020, time.January, 2, 3, 4, 4, 0, time.UTC)))
+	assert.True(t, v.IsValid(v.NotBefore()))
+	assert.True(t, v.IsValid(time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, v.IsValid(notAfter))
+	assert.False(t, v.IsValid(notAfter.Add(time.Second)))
+
+	wire, err := v.Encode().Wire()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xfd, 0x00, 0xfd, 0x26,
+		0xfd, 0x00, 0xfe, 0x0f, '2', '0', '2', '0', '0', '1', '0', '2', 'T', '0', '3', '0', '4', '0', '5',
+		0xfd, 0x00, 0xff, 0x0f, '2', '0', '2', '1', '1', '2', '3', '1', 'T', '2', '3', '5', '9', '5', '9'}, wire)
+
+	block, _, err := tlv.DecodeBlock(wire)
+	assert.NoError(t, err)
+	decoded, err := ndn.DecodeValidityPeriod(block)
+	assert.NoError(t, err)
+	assert.True(t, v.NotBefore().Equal(decoded.NotBefore()))
+	assert.True(t, v.NotAfter().Equal(decoded.NotAfter()))
+}
+
+func TestValidityPeriodDecodeInvalid(t *testing.T) {
+	notBefore := tlv.NewBlock(tlv.NotBefore, []byte("20200102T030405"))
+	notAfter := tlv.NewBlock(tlv.NotAfter, []byte("20211231T235959"))
+
+	decode := func(elems ...*tlv.Block) (*ndn.ValidityPeriod, error) {
+		wire := tlv.NewEmptyBlock(tlv.ValidityPeriod)
+		for _, elem := range elems {
+			wire.Append(elem)
+		}
+		encoded, err := wire.Wire()
+		assert.NoError(t, err)
+		block, _, err := tlv.DecodeBlock(encoded)
+		assert.NoError(t, err)
+		return ndn.DecodeValidityPeriod(block)
+	}
+
+	v, err := decode(notBefore)
+	assert.Nil(t, v)
+	assert.Error(t, err)
+	v, err = decode(notAfter)
+	assert.Nil(t, v)
+	assert.Error(t, err)
+	v, err = decode(notAfter, notBefore)
+	assert.Nil(t, v)
+	assert.Error(t, err)
+
+	for _, value := range []string{"2020-01-02T03:04:05", "20200102 030405", "20200102T0304", "20201302T030405", "2020010tT030405", "20200102T030405Z"} {
+		v, err = decode(tlv.NewBlock(tlv.NotBefore, []byte(value)), notAfter)
+		assert.Nil(t, v, value)
+		assert.Error(t, err, value)
+	}
+}
@@ -0,0 +1,225 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+
+	"github.com/eric135/go-ndn2/util"
+)
+
+// Verifier checks signatures over the signed portion of packets, as the counterpart of a Signer of the same type.
+type Verifier interface {
+	// Type returns the signature type checked by the verifier.
+	Type() uint64
+	// Verify returns nil if the signature value is valid for the specified signed portion, or an error otherwise.
+	Verify(input []byte, signatureValue []byte) error
+}
+
+// digestVerifier is implemented by verifiers whose signatures are computed over the SHA-256 digest of the signed portion, allowing packets to hash the signed portion incrementally rather than buffering it.
+type digestVerifier interface {
+	verifyDigest(digest []byte, signatureValue []byte) error
+}
+
+// NewVerifier creates a Verifier for signatures of the specified type using the specified public key, which must match the signature type. The public key is ignored for DigestSha256 signatures, while for SignatureHmacWithSha256 signatures it is the shared secret key as a []byte.
+func NewVerifier(signatureType uint64, publicKey crypto.PublicKey) (Verifier, error) {
+	switch signatureType {
+	case SignatureDigestSha256:
+		return NewSha256Verifier(), nil
+	case SignatureSha256WithRsa:
+		key, ok := publicKey.(*rsa.PublicKey)
+		if !ok {
+			return nil, errors.New("Public key does not match signature type")
+		}
+		return NewRsaVerifier(key)
+	case SignatureSha256WithEcdsa:
+		key, ok := publicKey.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, errors.New("Public key does not match signature type")
+		}
+		return NewEcdsaVerifier(key)
+	case SignatureEd25519:
+		key, ok := publicKey.(ed25519.PublicKey)
+		if !ok {
+			return nil, errors.New("Public key does not match signature type")
+		}
+		return NewEd25519Verifier(key)
+	case SignatureHmacWithSha256:
+		key, ok := publicKey.([]byte)
+		if !ok {
+			return nil, errors.New("Public key does not match signature type")
+		}
+		return NewHmacVerifier(key)
+	default:
+		return nil, errors.New("Unsupported signature type")
+	}
+}
+
+// Sha256Verifier checks DigestSha256 signatures.
+type Sha256Verifier struct{}
+
+// NewSha256Verifier creates a Sha256Verifier.
+func NewSha256Verifier() *Sha256Verifier {
+	return new(Sha256Verifier)
+}
+
+// Type returns the signature type checked by the verifier.
+func (v *Sha256Verifier) Type() uint64 {
+	return SignatureDigestSha256
+}
+
+// Verify checks that the signature value is the SHA-256 digest of the specified signed portion.
+func (v *Sha256Verifier) Verify(input []byte, signatureValue []byte) error {
+	digest := sha256.Sum256(input)
+	return v.verifyDigest(digest[:], signatureValue)
+}
+
+// verifyDigest checks that the signature value is the SHA-256 digest of the signed portion.
+func (v *Sha256Verifier) verifyDigest(digest []byte, signatureValue []byte) error {
+	if !bytes.Equal(digest, signatureValue) {
+		return errors.New("Signature verification failed")
+	}
+	return nil
+}
+
+// EcdsaVerifier checks SignatureSha256WithEcdsa signatures using the specified public key, which may be on the P-256, P-384, or P-521 curve.
+type EcdsaVerifier struct {
+	key *ecdsa.PublicKey
+}
+
+// NewEcdsaVerifier creates an EcdsaVerifier that checks signatures with the specified key.
+func NewEcdsaVerifier(key *ecdsa.PublicKey) (*EcdsaVerifier, error) {
+	if key == nil {
+		return nil, util.ErrNonExistent
+	}
+	if !isSupportedEcdsaCurve(key.Curve) {
+		return nil, errors.New("Unsupported ECDSA curve")
+	}
+	v := new(EcdsaVerifier)
+	v.key = key
+	return v, nil
+}
+
+// Type returns the signature type checked by the verifier.
+func (v *EcdsaVerifier) Type() uint64 {
+	return SignatureSha256WithEcdsa
+}
+
+// Verify checks the DER-encoded ECDSA signature of the SHA-256 digest of the specified signed portion.
+func (v *EcdsaVerifier) Verify(input []byte, signatureValue []byte) error {
+	digest := sha256.Sum256(input)
+	return v.verifyDigest(digest[:], signatureValue)
+}
+
+// verifyDigest checks the DER-encoded ECDSA signature of the SHA-256 digest of the signed portion.
+func (v *EcdsaVerifier) verifyDigest(digest []byte, signatureValue []byte) error {
+	if !ecdsa.VerifyASN1(v.key, digest, signatureValue) {
+		return errors.New("Signature verification failed")
+	}
+	return nil
+}
+
+// Ed25519Verifier checks SignatureEd25519 signatures using the specified public key.
+type Ed25519Verifier struct {
+	key ed25519.PublicKey
+}
+
+// NewEd25519Verifier creates an Ed25519Verifier that checks signatures with the specified key.
+func NewEd25519Verifier(key ed25519.PublicKey) (*Ed25519Verifier, error) {
+	if len(key) != ed25519.PublicKeySize {
+		return nil, errors.New("Invalid Ed25519 public key")
+	}
+	v := new(Ed25519Verifier)
+	v.key = key
+	return v, nil
+}
+
+// Type returns the signature type checked by the verifier.
+func (v *Ed25519Verifier) Type() uint64 {
+	return SignatureEd25519
+}
+
+// Verify checks the 64-octet Ed25519 signature of the specified signed portion.
+func (v *Ed25519Verifier) Verify(input []byte, signatureValue []byte) error {
+	if len(signatureValue) != ed25519.SignatureSize || !ed25519.Verify(v.key, input, signatureValue) {
+		return errors.New("Signature verification failed")
+	}
+	return nil
+}
+
+// RsaVerifier checks SignatureSha256WithRsa signatures using the specified public key.
+type RsaVerifier struct {
+	key *rsa.PublicKey
+}
+
+// NewRsaVerifier creates an RsaVerifier that checks signatures with the specified key.
+func NewRsaVerifier(key *rsa.PublicKey) (*RsaVerifier, error) {
+	if key == nil {
+		return nil, util.ErrNonExistent
+	}
+	v := new(RsaVerifier)
+	v.key = key
+	return v, nil
+}
+
+// Type returns the signature type checked by the verifier.
+func (v *RsaVerifier) Type() uint64 {
+	return SignatureSha256WithRsa
+}
+
+// Verify checks the PKCS #1 v1.5 RSA signature of the SHA-256 digest of the specified signed portion.
+func (v *RsaVerifier) Verify(input []byte, signatureValue []byte) error {
+	digest := sha256.Sum256(input)
+	return v.verifyDigest(digest[:], signatureValue)
+}
+
+// verifyDigest checks the PKCS #1 v1.5 RSA signature of the SHA-256 digest of the signed portion.
+func (v *RsaVerifier) verifyDigest(digest []byte, signatureValue []byte) error {
+	if rsa.VerifyPKCS1v15(v.key, crypto.SHA256, digest, signatureValue) != nil {
+		return errors.New("Signature verification failed")
+	}
+	return nil
+}
+
+// HmacVerifier checks SignatureHmacWithSha256 signatures using the specified shared secret key.
+type HmacVerifier struct {
+	key []byte
+}
+
+// NewHmacVerifier creates an HmacVerifier that checks signatures with a copy of the specified key.
+func NewHmacVerifier(key []byte) (*HmacVerifier, error) {
+	if len(key) == 0 {
+		return nil, util.ErrNonExistent
+	}
+	v := new(HmacVerifier)
+	v.key = make([]byte, len(key))
+	copy(v.key, key)
+	return v, nil
+}
+
+// Type returns the signature type checked by the verifier.
+func (v *HmacVerifier) Type() uint64 {
+	return SignatureHmacWithSha256
+}
+
+// Verify checks that the signature value is the HMAC-SHA256 of the specified signed portion, comparing them in constant time.
+func (v *HmacVerifier) Verify(input []byte, signatureValue []byte) error {
+	mac := hmac.New(sha256.New, v.key)
+	mac.Write(input)
+	if !hmac.Equal(mac.Sum(nil), signatureValue) {
+		return errors.New("Signature verification failed")
+	}
+	return nil
+}
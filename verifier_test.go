@@ -0,0 +1,96 @@
+/* GoNDN2 - NDN Forwarder Library for Go
+ *
+ * Copyright (C) 2020 Eric Newberry.
+ *
+ * This file is licensed under the terms of the MIT License, as found in LICENSE.md.
+ */
+
+package ndn_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	ndn "github.com/eric135/go-ndn2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifierRoundTrip(t *testing.T) {
+	keyName, _ := ndn.NameFromString("/go/KEY/abcd")
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	ed25519PublicKey, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	hmacKey := []byte("secret")
+
+	for _, pair := range []struct {
+		signer    ndn.Signer
+		publicKey interface{}
+	}{
+		{ndn.NewSha256Signer(), nil},
+		{ndn.NewEcdsaSigner(keyName, ecdsaKey), &ecdsaKey.PublicKey},
+		{ndn.NewEd25519Signer(keyName, ed25519Key), ed25519PublicKey},
+		{ndn.NewRsaSigner(keyName, rsaKey), &rsaKey.PublicKey},
+		{ndn.NewHmacSigner(keyName, hmacKey), hmacKey},
+	} {
+		verifier, err := ndn.NewVerifier(pair.signer.Type(), pair.publicKey)
+		assert.NoError(t, err)
+		assert.Equal(t, pair.signer.Type(), verifier.Type())
+
+		name, _ := ndn.NameFromString("/go/ndn")
+		d := ndn.NewData(name, []byte{0x01, 0x02, 0x03})
+		assert.NoError(t, d.Sign(pair.signer))
+		assert.NoError(t, d.Verify(verifier))
+
+		// Modifying the Data invalidates the signature
+		d.SetContent([]byte{0x04})
+		assert.Error(t, d.Verify(verifier))
+	}
+}
+
+func TestVerifierMismatch(t *testing.T) {
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	ed25519PublicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	p224Key, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	assert.NoError(t, err)
+
+	_, err = ndn.NewVerifier(ndn.SignatureSha256WithEcdsa, ed25519PublicKey)
+	assert.Error(t, err)
+	_, err = ndn.NewVerifier(ndn.SignatureEd25519, &ecdsaKey.PublicKey)
+	assert.Error(t, err)
+	_, err = ndn.NewVerifier(ndn.SignatureSha256WithEcdsa, &p224Key.PublicKey)
+	assert.Error(t, err)
+	_, err = ndn.NewVerifier(ndn.SignatureSha256WithRsa, &ecdsaKey.PublicKey)
+	assert.Error(t, err)
+	_, err = ndn.NewVerifier(ndn.SignatureHmacWithSha256, &ecdsaKey.PublicKey)
+	assert.Error(t, err)
+	_, err = ndn.NewVerifier(ndn.SignatureHmacWithSha256, []byte{})
+	assert.Error(t, err)
+	_, err = ndn.NewVerifier(0xFF, &ecdsaKey.PublicKey)
+	assert.Error(t, err)
+
+	// HMAC signatures with a different key are rejected
+	name, _ := ndn.NameFromString("/go/ndn")
+	d := ndn.NewData(name, []byte{0x01, 0x02, 0x03})
+	keyName, _ := ndn.NameFromString("/go/KEY/abcd")
+	assert.NoError(t, d.Sign(ndn.NewHmacSigner(keyName, []byte("secret"))))
+	hmacVerifier, err := ndn.NewHmacVerifier([]byte("other"))
+	assert.NoError(t, err)
+	assert.Error(t, d.Verify(hmacVerifier))
+
+	// Signature type of the Data must match the verifier
+	d = ndn.NewData(name, []byte{0x01, 0x02, 0x03})
+	assert.NoError(t, d.Sign(ndn.NewSha256Signer()))
+	verifier, err := ndn.NewVerifier(ndn.SignatureSha256WithEcdsa, &ecdsaKey.PublicKey)
+	assert.NoError(t, err)
+	assert.Error(t, d.Verify(verifier))
+	assert.Error(t, d.Verify(nil))
+}